@@ -11,20 +11,32 @@ type Client interface {
 	GetServer(serverName string) (*model.Node, A10Error)
 	CreateServer(server *model.Node) A10Error
 	UpdateServer(server *model.Node) A10Error
+	DeleteServer(serverName string) A10Error
 
 	GetHealthMonitor(monitorName string) (*model.HealthCheck, A10Error)
 	CreateHealthMonitor(monitor *model.HealthCheck) A10Error
 	UpdateHealthMonitor(monitor *model.HealthCheck) A10Error
+	DeleteHealthMonitor(monitorName string) A10Error
 
 	GetServiceGroup(serviceGroupName string) (*model.ServiceGroup, A10Error)
 	CreateServiceGroup(serviceGroup *model.ServiceGroup) A10Error
 	UpdateServiceGroup(serviceGroup *model.ServiceGroup) A10Error
+	DeleteServiceGroup(serviceGroupName string) A10Error
+	GetMemberStatuses(serviceGroupName string) ([]*model.MemberStatus, A10Error)
 
 	CreateMember(member *model.Member) A10Error
+	UpdateMember(member *model.Member) A10Error
 	DeleteMember(member *model.Member) A10Error
 
+	GetVirtualServer(virtualServerName string) (*model.VirtualServer, A10Error)
+	GetVirtualServers() ([]*model.VirtualServer, A10Error)
+	CreateVirtualServer(virtualServer *model.VirtualServer) A10Error
+	UpdateVirtualServer(virtualServer *model.VirtualServer) A10Error
+	DeleteVirtualServer(virtualServerName string) A10Error
+
 	IsServerNotFound(err A10Error) bool
 	IsHealthMonitorNotFound(err A10Error) bool
 	IsServiceGroupNotFound(err A10Error) bool
 	IsMemberAlreadyExists(err A10Error) bool
+	IsVirtualServerNotFound(err A10Error) bool
 }
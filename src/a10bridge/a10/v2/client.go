@@ -79,8 +79,21 @@ func (client v2Client) GetServer(serverName string) (*model.Node, api.A10Error)
 
 	server = &model.Node{
 		A10Server: response.Server.Name,
-		IPAddress: response.Server.IP,
 		Weight:    strconv.Itoa(response.Server.Weight),
+		ConnLimit: response.Server.ConnLimit,
+		SlowStart: strconv.FormatBool(response.Server.SlowStart == 1),
+		Template:  response.Server.Template,
+		State:     model.ServerStateDisable,
+	}
+	if response.Server.Status == 1 {
+		server.State = model.ServerStateEnable
+	}
+
+	//v2 api keeps ipv4 as well as ipv6 addresses in the host field
+	if util.IsIPv6(response.Server.IP) {
+		server.IPv6Address = response.Server.IP
+	} else {
+		server.IPAddress = response.Server.IP
 	}
 
 	return server, nil
@@ -104,14 +117,34 @@ func (client v2Client) CreateServer(server *model.Node) api.A10Error {
 	return nil
 }
 
+//UpdateServer sends only server attributes of model.ServerFields managed for the node, other attributes are kept by a10
 func (client v2Client) UpdateServer(server *model.Node) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.server.update"
 	request := updateServerRequest{
 		Base:   client.baseRequest,
 		Server: server,
+		Fields: model.Managed(model.ServerFields, server),
 	}
 	response := updateServerResponse{}
-	err := util.HTTPPost(urltpl, "a10/v2/tpl/server.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/server.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v2Client) DeleteServer(serverName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.server.delete"
+	request := deleteServerRequest{
+		Base: client.baseRequest,
+		Name: serverName,
+	}
+	response := deleteServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -171,14 +204,34 @@ func (client v2Client) CreateHealthMonitor(monitor *model.HealthCheck) api.A10Er
 	return nil
 }
 
+//UpdateHealthMonitor sends only monitor attributes of model.HealthCheckFields, passive checking and other attributes are kept by a10
 func (client v2Client) UpdateHealthMonitor(monitor *model.HealthCheck) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.hm.update"
 	request := updateMonitorRequest{
 		Base:    client.baseRequest,
 		Monitor: monitor,
+		Fields:  model.Managed(model.HealthCheckFields, monitor),
 	}
 	response := updateMonitorResponse{}
-	err := util.HTTPPost(urltpl, "a10/v2/tpl/health.monitor.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/health.monitor.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v2Client) DeleteHealthMonitor(monitorName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.hm.delete"
+	request := deleteMonitorRequest{
+		Base: client.baseRequest,
+		Name: monitorName,
+	}
+	response := deleteMonitorResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -211,7 +264,12 @@ func (client v2Client) GetServiceGroup(serviceGroupName string) (*model.ServiceG
 		Health: &model.HealthCheck{
 			Name: sg.HealthMonitorName,
 		},
-		Members: make([]*model.Member, len(sg.Members)),
+		Method:   lbMethodName(sg.LBMethod),
+		Protocol: protocolName(sg.Protocol),
+		Members:  make([]*model.Member, len(sg.Members)),
+	}
+	if sg.MinActiveMember.Status == 1 {
+		serviceGroup.MinActiveMembers = sg.MinActiveMember.Number
 	}
 
 	for idx, member := range sg.Members {
@@ -219,17 +277,54 @@ func (client v2Client) GetServiceGroup(serviceGroupName string) (*model.ServiceG
 			Port:             member.Port,
 			ServerName:       member.ServerName,
 			ServiceGroupName: serviceGroup.Name,
+			Priority:         member.Priority,
 		}
 	}
 
 	return serviceGroup, nil
 }
 
+//lbMethodCodes maps load balancing methods to the numeric codes used by the v2 api
+var lbMethodCodes = map[string]int{
+	model.LBMethodRoundRobin:              0,
+	model.LBMethodWeightedRoundRobin:      1,
+	model.LBMethodLeastConnection:         2,
+	model.LBMethodWeightedLeastConnection: 3,
+	model.LBMethodFastestResponse:         6,
+	model.LBMethodLeastRequest:            7,
+}
+
+func lbMethodName(code int) string {
+	for name, value := range lbMethodCodes {
+		if value == code {
+			return name
+		}
+	}
+	return ""
+}
+
+//protocolCodes maps service group protocols to the numeric codes used by the v2 api
+var protocolCodes = map[string]int{
+	model.ProtocolTCP: 2,
+	model.ProtocolUDP: 3,
+}
+
+func protocolName(code int) string {
+	for name, value := range protocolCodes {
+		if value == code {
+			return name
+		}
+	}
+	return ""
+}
+
 func (client v2Client) CreateServiceGroup(serviceGroup *model.ServiceGroup) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.create"
 	request := createServiceGroupRequest{
 		Base:         client.baseRequest,
 		ServiceGroup: serviceGroup,
+		LBMethod:     lbMethodCodes[serviceGroup.Method],
+		Protocol:     protocolCodes[serviceGroup.ProtocolOrDefault()],
 	}
 	response := createServiceGroupResponse{}
 	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.request", request, &response, client.commonHeaders)
@@ -243,14 +338,37 @@ func (client v2Client) CreateServiceGroup(serviceGroup *model.ServiceGroup) api.
 	return nil
 }
 
+//UpdateServiceGroup sends only service group attributes of model.ServiceGroupFields managed for the service group,
+//members are kept as they are and synced one by one through CreateMember and DeleteMember
 func (client v2Client) UpdateServiceGroup(serviceGroup *model.ServiceGroup) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.update"
 	request := updateServiceGroupRequest{
 		Base:         client.baseRequest,
 		ServiceGroup: serviceGroup,
+		LBMethod:     lbMethodCodes[serviceGroup.Method],
+		Protocol:     protocolCodes[serviceGroup.ProtocolOrDefault()],
+		Fields:       model.Managed(model.ServiceGroupFields, serviceGroup),
 	}
 	response := updateServiceGroupResponse{}
-	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v2Client) DeleteServiceGroup(serviceGroupName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.delete"
+	request := deleteServiceGroupRequest{
+		Base: client.baseRequest,
+		Name: serviceGroupName,
+	}
+	response := deleteServiceGroupResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -261,6 +379,33 @@ func (client v2Client) UpdateServiceGroup(serviceGroup *model.ServiceGroup) api.
 	return nil
 }
 
+//GetMemberStatuses reads statistics of service group members, members are up when their status is 1
+func (client v2Client) GetMemberStatuses(serviceGroupName string) ([]*model.MemberStatus, api.A10Error) {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.fetchStatistics"
+	request := getMemberStatusesRequest{
+		Base: client.baseRequest,
+		Name: serviceGroupName,
+	}
+	response := getMemberStatusesResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return nil, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return nil, response.Result.Error
+	}
+
+	statuses := make([]*model.MemberStatus, len(response.ServiceGroup.Members))
+	for idx, member := range response.ServiceGroup.Members {
+		statuses[idx] = &model.MemberStatus{
+			ServerName: member.ServerName,
+			Port:       member.Port,
+			Up:         member.Status == 1,
+		}
+	}
+	return statuses, nil
+}
+
 func (client v2Client) CreateMember(member *model.Member) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.member.create"
 	request := createServiceGroupMemberRequest{
@@ -268,7 +413,26 @@ func (client v2Client) CreateMember(member *model.Member) api.A10Error {
 		Member: member,
 	}
 	response := createServiceGroupMemberResponse{}
-	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.member.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.member.create.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+//UpdateMember sends the member priority, other member attributes are kept by a10
+func (client v2Client) UpdateMember(member *model.Member) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.service_group.member.update"
+	request := updateServiceGroupMemberRequest{
+		Base:   client.baseRequest,
+		Member: member,
+	}
+	response := updateServiceGroupMemberResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/svcgrp.member.update.request", request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -297,6 +461,125 @@ func (client v2Client) DeleteMember(member *model.Member) api.A10Error {
 	return nil
 }
 
+func (client v2Client) GetVirtualServer(virtualServerName string) (*model.VirtualServer, api.A10Error) {
+	var virtualServer *model.VirtualServer
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.virtual_server.search"
+	request := getVirtualServerRequest{
+		Base: client.baseRequest,
+		Name: virtualServerName,
+	}
+	response := getVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return virtualServer, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return virtualServer, response.Result.Error
+	}
+
+	return buildVirtualServer(response.VirtualServer), nil
+}
+
+func (client v2Client) GetVirtualServers() ([]*model.VirtualServer, api.A10Error) {
+	var virtualServers []*model.VirtualServer
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.virtual_server.getAll"
+	request := getVirtualServersRequest{
+		Base: client.baseRequest,
+	}
+	response := getVirtualServersResponse{}
+	err := util.HTTPGet(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return virtualServers, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return virtualServers, response.Result.Error
+	}
+
+	virtualServers = make([]*model.VirtualServer, len(response.VirtualServers))
+	for idx, virtualServer := range response.VirtualServers {
+		virtualServers[idx] = buildVirtualServer(virtualServer)
+	}
+
+	return virtualServers, nil
+}
+
+func (client v2Client) CreateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.virtual_server.create"
+	request := createVirtualServerRequest{
+		Base:          client.baseRequest,
+		VirtualServer: virtualServer,
+	}
+	response := createVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/vserver.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v2Client) UpdateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.virtual_server.update"
+	request := updateVirtualServerRequest{
+		Base:          client.baseRequest,
+		VirtualServer: virtualServer,
+	}
+	response := updateVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/vserver.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v2Client) DeleteVirtualServer(virtualServerName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/services/rest/V2.1/?session_id={{.Base.SessionID}}&format=json&method=slb.virtual_server.delete"
+	request := deleteVirtualServerRequest{
+		Base: client.baseRequest,
+		Name: virtualServerName,
+	}
+	response := deleteVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v2/tpl/name.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func buildVirtualServer(vs virtualServer) *model.VirtualServer {
+	virtualServer := &model.VirtualServer{
+		Name:        vs.Name,
+		IPAddress:   vs.IPAddress,
+		Description: vs.Description,
+		Ports:       make([]*model.VirtualPort, len(vs.Ports)),
+	}
+
+	for idx, port := range vs.Ports {
+		protocol := "tcp"
+		if port.Protocol == 3 {
+			protocol = "udp"
+		}
+		virtualServer.Ports[idx] = &model.VirtualPort{
+			Port:             port.Port,
+			Protocol:         protocol,
+			ServiceGroupName: port.ServiceGroupName,
+		}
+	}
+
+	return virtualServer
+}
+
 func (client v2Client) IsServerNotFound(err api.A10Error) bool {
 	return err.Code() == 67174402
 }
@@ -312,3 +595,7 @@ func (client v2Client) IsServiceGroupNotFound(err api.A10Error) bool {
 func (client v2Client) IsMemberAlreadyExists(err api.A10Error) bool {
 	return err.Code() == 1405
 }
+
+func (client v2Client) IsVirtualServerNotFound(err api.A10Error) bool {
+	return err.Code() == 67239937
+}
@@ -136,3 +136,78 @@ func testDeleteMember_Failure(testServer *testing.ServerConfig, assert *assert.A
 	assert.NotNil(err, "Expected error when create member call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateMember_withPriority(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	member := model.Member{
+		ServiceGroupName: "sg name",
+		ServerName:       "srv name",
+		Port:             8080,
+		Priority:         16,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.service_group.member.create").
+		Body(`{
+  "member" : {
+    "server" : "`+member.ServerName+`",
+    "port" : `+strconv.Itoa(member.Port)+`,
+    "priority" : 16
+  },
+  "name" : "`+member.ServiceGroupName+`"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateMember(&member)
+	assert.Nil(err, "Unexpected error when creating member")
+}
+
+func testUpdateMember(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	member := model.Member{
+		ServiceGroupName: "sg name",
+		ServerName:       "srv name",
+		Port:             8080,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.service_group.member.update").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "member" : {
+    "server" : "`+member.ServerName+`",
+    "port" : `+strconv.Itoa(member.Port)+`,
+    "priority" : 1
+  },
+  "name" : "`+member.ServiceGroupName+`"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateMember(&member)
+	assert.Nil(err, "Unexpected error when updating member")
+}
+
+func testUpdateMember_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+	member := model.Member{
+		ServiceGroupName: "sg name",
+		ServerName:       "srv name",
+		Port:             8080,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"HTTP","msg":"Unauthorized"}}}`, "application/json")
+
+	err := client.UpdateMember(&member)
+	assert.NotNil(err, "Expected error when update member call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
@@ -42,14 +42,7 @@ func testUpdateMonitor(testServer *testing.ServerConfig, assert *assert.Assertio
     "http": {
       "port": `+strconv.Itoa(monitor.Port)+`,
       "url": "GET `+monitor.Endpoint+`",
-      "expect_code": "`+monitor.ExpectCode+`",
-      "passive": {
-        "status": 0,
-        "status_code_2xx": 0,
-        "threshold": 75,
-        "sample_threshold": 50,
-        "interval": 10
-      }
+      "expect_code": "`+monitor.ExpectCode+`"
     }
   }
 }`).
@@ -269,3 +262,32 @@ func testGetMonitor_Failure(testServer *testing.ServerConfig, assert *assert.Ass
 	assert.NotNil(err, "Expected error when get monitor call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testDeleteMonitor(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.hm.delete").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "monitor"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteHealthMonitor("monitor")
+	assert.Nil(err, "Unexpected error when deleting health monitor")
+}
+
+func testDeleteMonitor_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteHealthMonitor("monitor")
+	assert.NotNil(err, "Expected error when delete health monitor call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
@@ -29,8 +29,7 @@ func testUpdateServer(testServer *testing.ServerConfig, assert *assert.Assertion
   "server": {
 	"name": "`+node.A10Server+`",
 	"host": "`+node.IPAddress+`",
-	"weight": `+node.Weight+`,
-	"conn_limit_log": 1
+	"weight": `+node.Weight+`
   }
 }`).
 		Response().
@@ -104,6 +103,33 @@ func testCreateServer(testServer *testing.ServerConfig, assert *assert.Assertion
 	assert.Nil(err, "Unexpected error when creating server")
 }
 
+func testCreateServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server:   "server",
+		IPv6Address: "fd00::11",
+		Weight:      "1",
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.server.create").
+		Body(`{
+  "server": {
+	"name": "`+node.A10Server+`",
+	"host": "`+node.IPv6Address+`",
+	"weight": `+node.Weight+`,
+	"conn_limit_log": 1
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServer(&node)
+	assert.Nil(err, "Unexpected error when creating ipv6 server")
+}
+
 func testCreateServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
 	node := model.Node{
 		A10Server: "server",
@@ -169,6 +195,26 @@ func testGetServer(testServer *testing.ServerConfig, assert *assert.Assertions,
 	assert.Equal(0, len(node.Labels))
 }
 
+func testGetServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	serverName := "server"
+	ipAddress := "fd00::cb"
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.server.search").
+		Response().
+		Body(`{"server":{"name":"`+serverName+`","host":"`+ipAddress+`","weight":1}}`, "application/json")
+
+	node, err := client.GetServer(serverName)
+
+	assert.Nil(err, "Unexpected error when getting ipv6 server")
+	assert.NotNil(node, "Expected node instance")
+	assert.Equal("", node.IPAddress)
+	assert.Equal(ipAddress, node.IPv6Address)
+}
+
 func testGetServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
 	testServer.Reset().
 		AddRequest().
@@ -191,3 +237,84 @@ func testGetServer_Failure(testServer *testing.ServerConfig, assert *assert.Asse
 	assert.NotNil(err, "Expected error when get server call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateServer_withAttributes(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server:   "server",
+		IPAddress:   "10.10.10.11",
+		Weight:      "1",
+		ConnLimit:   1000,
+		SlowStart:   "true",
+		Description: "not supported by v2",
+		Template:    "k8s-nodes",
+		State:       model.ServerStateDisable,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.server.create").
+		Body(`{
+  "server": {
+	"name": "`+node.A10Server+`",
+	"host": "`+node.IPAddress+`",
+	"weight": `+node.Weight+`,
+	"conn_limit": 1000,
+	"slow_start": 1,
+	"template": "k8s-nodes",
+	"status": 0,
+	"conn_limit_log": 1
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServer(&node)
+	assert.Nil(err, "Unexpected error when creating server")
+}
+
+func testGetServer_attributes(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Query("method", "slb.server.search").
+		Response().
+		Body(`{"server":{"name":"server","host":"10.10.10.11","weight":1,"status":0,"conn_limit":1000,"slow_start":1,"template":"k8s-nodes"}}`, "application/json")
+
+	node, err := client.GetServer("server")
+
+	assert.Nil(err, "Unexpected error when getting server")
+	assert.Equal(1000, node.ConnLimit)
+	assert.Equal("true", node.SlowStart)
+	assert.Equal("k8s-nodes", node.Template)
+	assert.Equal(model.ServerStateDisable, node.State)
+}
+
+func testDeleteServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.server.delete").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "server"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteServer("server")
+	assert.Nil(err, "Unexpected error when deleting server")
+}
+
+func testDeleteServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteServer("server")
+	assert.NotNil(err, "Expected error when delete server call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
@@ -36,14 +36,35 @@ func testUpdateServiceGroup(testServer *testing.ServerConfig, assert *assert.Ass
 		Body(`{
   "service_group": {
     "name": "`+svcGroup.Name+`",
-    "protocol": 2,
     "health_monitor": "`+svcGroup.Health.Name+`",
-    "member_list": [
-	  {
-        "server" : "`+svcGroup.Members[0].ServerName+`",
-        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
-      }
-    ] 
+    "protocol": 2
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when updating service group")
+}
+
+func testUpdateServiceGroup_resetMinActiveMembers(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:                  "test name",
+		ResetMinActiveMembers: true,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.service_group.update").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "service_group": {
+    "name": "`+svcGroup.Name+`",
+    "min_active_member": {"status": 0, "number": 0, "priority_set": 0},
+    "protocol": 2
   }
 }`).
 		Response().
@@ -246,11 +267,14 @@ func testGetServiceGroup(testServer *testing.ServerConfig, assert *assert.Assert
 	assert.NotNil(svcGroup, "Expected service group instance")
 	assert.Equal(expected.Name, svcGroup.Name)
 	assert.Equal(expected.Health.Name, svcGroup.Health.Name)
+	assert.Equal(model.ProtocolTCP, svcGroup.Protocol)
+	assert.Equal(0, svcGroup.MinActiveMembers)
 	assert.Equal(len(expected.Members), len(svcGroup.Members))
 	assert.Equal(1, len(svcGroup.Members))
 	assert.Equal(expected.Members[0].ServerName, svcGroup.Members[0].ServerName)
 	assert.Equal(expected.Members[0].Port, svcGroup.Members[0].Port)
 	assert.Equal(expected.Members[0].ServiceGroupName, svcGroup.Members[0].ServiceGroupName)
+	assert.Equal(1, svcGroup.Members[0].Priority)
 }
 
 func testGetServiceGroup_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
@@ -275,3 +299,117 @@ func testGetServiceGroup_Failure(testServer *testing.ServerConfig, assert *asser
 	assert.NotNil(err, "Expected error when get service group call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateServiceGroup_withMethod(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:   "test name",
+		Method: "least-connection",
+		Members: []*model.Member{
+			&model.Member{
+				ServiceGroupName: "test name",
+				ServerName:       "server name",
+				Port:             30080,
+			},
+		},
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.service_group.create").
+		Body(`{
+  "service_group": {
+    "name": "`+svcGroup.Name+`",
+    "protocol": 2,
+    "lb_method": 2,
+    "member_list": [
+      {
+        "server" : "`+svcGroup.Members[0].ServerName+`",
+        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when creating service group")
+}
+
+func testCreateServiceGroup_withOptions(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:             "test name",
+		Protocol:         model.ProtocolUDP,
+		MinActiveMembers: 2,
+		Members: []*model.Member{
+			&model.Member{
+				ServiceGroupName: "test name",
+				ServerName:       "server name",
+				Port:             30053,
+			},
+		},
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.service_group.create").
+		Body(`{
+  "service_group": {
+    "name": "`+svcGroup.Name+`",
+    "protocol": 3,
+    "min_active_member": {"status": 1, "number": 2, "priority_set": 0},
+    "member_list": [
+      {
+        "server" : "`+svcGroup.Members[0].ServerName+`",
+        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when creating service group")
+}
+
+func testGetMemberStatuses(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.service_group.fetchStatistics").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "sg"
+}`).
+		Response().
+		Body(`{"service_group_stat":{"name":"sg","status":1,"cur_conns":0,"member_stat_list":[`+
+			`{"server":"srv1","port":8080,"status":1,"cur_conns":0},`+
+			`{"server":"srv2","port":8080,"status":2,"cur_conns":0}]}}`, "application/json")
+
+	statuses, err := client.GetMemberStatuses("sg")
+	assert.Nil(err, "Unexpected error when getting member statuses")
+	assert.Equal([]*model.MemberStatus{
+		&model.MemberStatus{ServerName: "srv1", Port: 8080, Up: true},
+		&model.MemberStatus{ServerName: "srv2", Port: 8080, Up: false},
+	}, statuses)
+}
+
+func testGetMemberStatuses_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 67305473
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"msg":" No such Service Group"}}}`, "application/json")
+
+	statuses, err := client.GetMemberStatuses("sg")
+	assert.Nil(statuses)
+	assert.NotNil(err, "Expected error when get member statuses call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
@@ -41,16 +41,23 @@ func TestServerResource(t *tst.T) {
 	assert.Nil(err, "Failed to build client for testing")
 
 	testGetServer(testServer, assert, client)
+	testGetServer_IPv6(testServer, assert, client)
+	testGetServer_attributes(testServer, assert, client)
 	testGetServer_ServerError(testServer, assert, client)
 	testGetServer_Failure(testServer, assert, client)
 
 	testCreateServer(testServer, assert, client)
+	testCreateServer_IPv6(testServer, assert, client)
+	testCreateServer_withAttributes(testServer, assert, client)
 	testCreateServer_ServerError(testServer, assert, client)
 	testCreateServer_Failure(testServer, assert, client)
 
 	testUpdateServer(testServer, assert, client)
 	testUpdateServer_ServerError(testServer, assert, client)
 	testUpdateServer_Failure(testServer, assert, client)
+
+	testDeleteServer(testServer, assert, client)
+	testDeleteServer_ServerError(testServer, assert, client)
 }
 
 func TestHealthMonitorResource(t *tst.T) {
@@ -73,6 +80,9 @@ func TestHealthMonitorResource(t *tst.T) {
 	testUpdateMonitor(testServer, assert, client)
 	testUpdateMonitor_ServerError(testServer, assert, client)
 	testUpdateMonitor_Failure(testServer, assert, client)
+
+	testDeleteMonitor(testServer, assert, client)
+	testDeleteMonitor_ServerError(testServer, assert, client)
 }
 
 func TestServiceGroupResource(t *tst.T) {
@@ -91,10 +101,45 @@ func TestServiceGroupResource(t *tst.T) {
 	testCreateServiceGroup(testServer, assert, client)
 	testCreateServiceGroup_ServerError(testServer, assert, client)
 	testCreateServiceGroup_Failure(testServer, assert, client)
+	testCreateServiceGroup_withoutHealthMonitor(testServer, assert, client)
+	testCreateServiceGroup_withMethod(testServer, assert, client)
+	testCreateServiceGroup_withOptions(testServer, assert, client)
 
 	testUpdateServiceGroup(testServer, assert, client)
+	testUpdateServiceGroup_resetMinActiveMembers(testServer, assert, client)
 	testUpdateServiceGroup_ServerError(testServer, assert, client)
 	testUpdateServiceGroup_Failure(testServer, assert, client)
+
+	testDeleteServiceGroup(testServer, assert, client)
+	testDeleteServiceGroup_ServerError(testServer, assert, client)
+
+	testGetMemberStatuses(testServer, assert, client)
+	testGetMemberStatuses_Failure(testServer, assert, client)
+}
+
+func TestVirtualServerResource(t *tst.T) {
+	sessionId := "test_session_id"
+	assert := assert.New(t)
+	testServer := testing.NewTestServer(t).Start()
+	defer testServer.Stop()
+
+	client, err := buildClient(testServer, sessionId)
+	assert.Nil(err, "Failed to build client for testing")
+
+	testGetVirtualServer(testServer, assert, client)
+	testGetVirtualServer_Failure(testServer, assert, client)
+
+	testGetVirtualServers(testServer, assert, client)
+	testGetVirtualServers_ServerError(testServer, assert, client)
+
+	testCreateVirtualServer(testServer, assert, client)
+	testCreateVirtualServer_Failure(testServer, assert, client)
+
+	testUpdateVirtualServer(testServer, assert, client)
+	testUpdateVirtualServer_ServerError(testServer, assert, client)
+
+	testDeleteVirtualServer(testServer, assert, client)
+	testDeleteVirtualServer_Failure(testServer, assert, client)
 }
 
 func TestServiceGroupMemberResource(t *tst.T) {
@@ -110,6 +155,11 @@ func TestServiceGroupMemberResource(t *tst.T) {
 	testCreateMember_ServerError(testServer, assert, client)
 	testCreateMember_Failure(testServer, assert, client)
 
+	testCreateMember_withPriority(testServer, assert, client)
+
+	testUpdateMember(testServer, assert, client)
+	testUpdateMember_Failure(testServer, assert, client)
+
 	testDeleteMember(testServer, assert, client)
 	testDeleteMember_ServerError(testServer, assert, client)
 	testDeleteMember_Failure(testServer, assert, client)
@@ -140,6 +190,10 @@ func TestErrorCodeDetection(t *tst.T) {
 	assert.False(client.IsMemberAlreadyExists(a10err))
 	a10err = helper.SetErrorCode(a10err, 1405)
 	assert.True(client.IsMemberAlreadyExists(a10err))
+
+	assert.False(client.IsVirtualServerNotFound(a10err))
+	a10err = helper.SetErrorCode(a10err, 67239937)
+	assert.True(client.IsVirtualServerNotFound(a10err))
 }
 
 func buildClient(testServer *testing.ServerConfig, sessionId string) (api.Client, error) {
@@ -0,0 +1,324 @@
+package v2_test
+
+import (
+	"a10bridge/a10/api"
+	"a10bridge/a10/v2"
+	"a10bridge/model"
+	"a10bridge/testing"
+	"net/http"
+	"strconv"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testVirtualServer() model.VirtualServer {
+	return model.VirtualServer{
+		Name:      "k8s-default-web",
+		IPAddress: "10.0.0.10",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{
+				Port:             80,
+				Protocol:         "tcp",
+				ServiceGroupName: "k8s-default-web-80",
+			},
+			&model.VirtualPort{
+				Port:             53,
+				Protocol:         "udp",
+				ServiceGroupName: "k8s-default-web-53",
+			},
+		},
+	}
+}
+
+func testGetVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.virtual_server.search").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "`+virtualServer.Name+`"
+}`).
+		Response().
+		Body(`{
+  "virtual_server": {
+    "name": "`+virtualServer.Name+`",
+    "address": "`+virtualServer.IPAddress+`",
+    "status": 1,
+    "vport_list": [
+      {
+        "protocol": 2,
+        "port": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "service_group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      },
+      {
+        "protocol": 3,
+        "port": `+strconv.Itoa(virtualServer.Ports[1].Port)+`,
+        "service_group": "`+virtualServer.Ports[1].ServiceGroupName+`"
+      }
+    ]
+  }
+}`, "application/json")
+
+	a10VirtualServer, err := client.GetVirtualServer(virtualServer.Name)
+	assert.Nil(err, "Unexpected error when getting virtual server")
+	assert.Equal(virtualServer, *a10VirtualServer)
+}
+
+func testGetVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 67239937
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response": {"status": "fail", "err": {"code": `+strconv.Itoa(errorCode)+`, "msg": "Can not find the virtual server"}}}`, "application/json")
+
+	a10VirtualServer, err := client.GetVirtualServer("vs")
+	assert.Nil(a10VirtualServer)
+	assert.NotNil(err, "Expected error when virtual server doesn't exist")
+	assert.True(client.IsVirtualServerNotFound(err))
+}
+
+func testGetVirtualServers(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.virtual_server.getAll").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Response().
+		Body(`{
+  "virtual_server_list": [
+    {
+      "name": "`+virtualServer.Name+`",
+      "address": "`+virtualServer.IPAddress+`",
+      "vport_list": [
+        {
+          "protocol": 2,
+          "port": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+          "service_group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+        },
+        {
+          "protocol": 3,
+          "port": `+strconv.Itoa(virtualServer.Ports[1].Port)+`,
+          "service_group": "`+virtualServer.Ports[1].ServiceGroupName+`"
+        }
+      ]
+    }
+  ]
+}`, "application/json")
+
+	a10VirtualServers, err := client.GetVirtualServers()
+	assert.Nil(err, "Unexpected error when getting virtual servers")
+	assert.Len(a10VirtualServers, 1)
+	assert.Equal(virtualServer, *a10VirtualServers[0])
+}
+
+func testGetVirtualServers_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	_, err := client.GetVirtualServers()
+	assert.NotNil(err, "Expected error when get virtual servers call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
+
+func testCreateVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.virtual_server.create").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "virtual_server": {
+    "name": "`+virtualServer.Name+`",
+    "address": "`+virtualServer.IPAddress+`",
+    "status": 1,
+    "vport_list": [
+      {
+        "protocol": 2,
+        "port": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "service_group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      },
+      {
+        "protocol": 3,
+        "port": `+strconv.Itoa(virtualServer.Ports[1].Port)+`,
+        "service_group": "`+virtualServer.Ports[1].ServiceGroupName+`"
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateVirtualServer(&virtualServer)
+	assert.Nil(err, "Unexpected error when creating virtual server")
+}
+
+func testCreateVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response": {"status": "fail", "err": {"code": `+strconv.Itoa(errorCode)+`, "msg": "Invalid session ID"}}}`, "application/json")
+
+	err := client.CreateVirtualServer(&virtualServer)
+	assert.NotNil(err, "Expected error when create virtual server call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
+
+func testUpdateVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+	virtualServer.Ports = virtualServer.Ports[:1]
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.virtual_server.update").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "virtual_server": {
+    "name": "`+virtualServer.Name+`",
+    "address": "`+virtualServer.IPAddress+`",
+    "status": 1,
+    "vport_list": [
+      {
+        "protocol": 2,
+        "port": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "service_group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateVirtualServer(&virtualServer)
+	assert.Nil(err, "Unexpected error when updating virtual server")
+}
+
+func testUpdateVirtualServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.UpdateVirtualServer(&virtualServer)
+	assert.NotNil(err, "Expected error when update virtual server call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
+
+func testDeleteVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.virtual_server.delete").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "vs"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteVirtualServer("vs")
+	assert.Nil(err, "Unexpected error when deleting virtual server")
+}
+
+func testDeleteVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response": {"status": "fail", "err": {"code": `+strconv.Itoa(errorCode)+`, "msg": "Invalid session ID"}}}`, "application/json")
+
+	err := client.DeleteVirtualServer("vs")
+	assert.NotNil(err, "Expected error when delete virtual server call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
+
+func testDeleteServiceGroup(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("format", "json").
+		Query("method", "slb.service_group.delete").
+		Query("session_id", v2.TestHelper{}.GetSessionID(client)).
+		Body(`{
+  "name": "sg"
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteServiceGroup("sg")
+	assert.Nil(err, "Unexpected error when deleting service group")
+}
+
+func testDeleteServiceGroup_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteServiceGroup("sg")
+	assert.NotNil(err, "Expected error when delete service group call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
+
+func testCreateServiceGroup_withoutHealthMonitor(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name: "test name",
+		Members: []*model.Member{
+			&model.Member{
+				ServiceGroupName: "test name",
+				ServerName:       "server name",
+				Port:             30080,
+			},
+		},
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/services/rest/V2.1/").
+		Query("method", "slb.service_group.create").
+		Body(`{
+  "service_group": {
+    "name": "`+svcGroup.Name+`",
+    "protocol": 2,
+    "member_list": [
+      {
+        "server" : "`+svcGroup.Members[0].ServerName+`",
+        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when creating service group")
+}
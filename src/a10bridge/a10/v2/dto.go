@@ -42,18 +42,29 @@ type getServerRequest = nameRequest
 type getServerResponse struct {
 	Result result `json:"response"`
 	Server struct {
-		Name   string `json:"name"`
-		IP     string `json:"host"`
-		Weight int    `json:"weight"`
+		Name      string `json:"name"`
+		IP        string `json:"host"`
+		Weight    int    `json:"weight"`
+		ConnLimit int    `json:"conn_limit"`
+		SlowStart int    `json:"slow_start"`
+		Template  string `json:"template"`
+		Status    int    `json:"status"`
 	} `json:"server"`
 }
 
 type createServerRequest = serverRequest
 type createServerResponse = simpleResponse
 
-type updateServerRequest = serverRequest
+type updateServerRequest struct {
+	Base   baseRequest
+	Server *model.Node
+	Fields model.ManagedFields
+}
 type updateServerResponse = simpleResponse
 
+type deleteServerRequest = nameRequest
+type deleteServerResponse = simpleResponse
+
 type monitorRequest struct {
 	Base    baseRequest
 	Monitor *model.HealthCheck
@@ -79,12 +90,21 @@ type getMonitorResponse struct {
 type createMonitorRequest = monitorRequest
 type createMonitorResponse = simpleResponse
 
-type updateMonitorRequest = monitorRequest
+type updateMonitorRequest struct {
+	Base    baseRequest
+	Monitor *model.HealthCheck
+	Fields  model.ManagedFields
+}
 type updateMonitorResponse = simpleResponse
 
+type deleteMonitorRequest = nameRequest
+type deleteMonitorResponse = simpleResponse
+
 type serviceGroupRequest struct {
 	Base         baseRequest
 	ServiceGroup *model.ServiceGroup
+	LBMethod     int
+	Protocol     int
 }
 
 type getServiceGroupRequest = nameRequest
@@ -93,9 +113,16 @@ type getServiceGroupResponse struct {
 	ServiceGroup struct {
 		Name              string `json:"name"`
 		HealthMonitorName string `json:"health_monitor"`
-		Members           []struct {
+		LBMethod          int    `json:"lb_method"`
+		Protocol          int    `json:"protocol"`
+		MinActiveMember   struct {
+			Status int `json:"status"`
+			Number int `json:"number"`
+		} `json:"min_active_member"`
+		Members []struct {
 			ServerName string `json:"server"`
 			Port       int    `json:"port"`
+			Priority   int    `json:"priority"`
 		} `json:"member_list"`
 	} `json:"service_group"`
 }
@@ -103,7 +130,13 @@ type getServiceGroupResponse struct {
 type createServiceGroupRequest = serviceGroupRequest
 type createServiceGroupResponse = simpleResponse
 
-type updateServiceGroupRequest = serviceGroupRequest
+type updateServiceGroupRequest struct {
+	Base         baseRequest
+	ServiceGroup *model.ServiceGroup
+	LBMethod     int
+	Protocol     int
+	Fields       model.ManagedFields
+}
 type updateServiceGroupResponse = simpleResponse
 
 type serviceGroupMemberRequest struct {
@@ -114,5 +147,60 @@ type serviceGroupMemberRequest struct {
 type createServiceGroupMemberRequest = serviceGroupMemberRequest
 type createServiceGroupMemberResponse = simpleResponse
 
+type updateServiceGroupMemberRequest = serviceGroupMemberRequest
+type updateServiceGroupMemberResponse = simpleResponse
+
 type deleteServiceGroupMemberRequest = serviceGroupMemberRequest
 type deleteServiceGroupMemberResponse = simpleResponse
+
+type deleteServiceGroupRequest = nameRequest
+type deleteServiceGroupResponse = simpleResponse
+
+type getMemberStatusesRequest = nameRequest
+type getMemberStatusesResponse struct {
+	Result       result `json:"response"`
+	ServiceGroup struct {
+		Members []struct {
+			ServerName string `json:"server"`
+			Port       int    `json:"port"`
+			Status     int    `json:"status"`
+		} `json:"member_stat_list"`
+	} `json:"service_group_stat"`
+}
+
+type virtualServerRequest struct {
+	Base          baseRequest
+	VirtualServer *model.VirtualServer
+}
+
+type virtualServer struct {
+	Name        string `json:"name"`
+	IPAddress   string `json:"address"`
+	Description string `json:"description"`
+	Ports       []struct {
+		Port             int    `json:"port"`
+		Protocol         int    `json:"protocol"`
+		ServiceGroupName string `json:"service_group"`
+	} `json:"vport_list"`
+}
+
+type getVirtualServerRequest = nameRequest
+type getVirtualServerResponse struct {
+	Result        result        `json:"response"`
+	VirtualServer virtualServer `json:"virtual_server"`
+}
+
+type getVirtualServersRequest = nameRequest
+type getVirtualServersResponse struct {
+	Result         result          `json:"response"`
+	VirtualServers []virtualServer `json:"virtual_server_list"`
+}
+
+type createVirtualServerRequest = virtualServerRequest
+type createVirtualServerResponse = simpleResponse
+
+type updateVirtualServerRequest = virtualServerRequest
+type updateVirtualServerResponse = simpleResponse
+
+type deleteVirtualServerRequest = nameRequest
+type deleteVirtualServerResponse = simpleResponse
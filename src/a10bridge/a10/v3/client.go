@@ -86,9 +86,15 @@ func (client v3Client) GetServer(serverName string) (*model.Node, api.A10Error)
 	}
 
 	server = &model.Node{
-		A10Server: response.Server.Name,
-		IPAddress: response.Server.IP,
-		Weight:    strconv.Itoa(response.Server.Weight),
+		A10Server:   response.Server.Name,
+		IPAddress:   response.Server.IP,
+		IPv6Address: response.Server.IPv6,
+		Weight:      strconv.Itoa(response.Server.Weight),
+		ConnLimit:   response.Server.ConnLimit,
+		SlowStart:   strconv.FormatBool(response.Server.SlowStart == 1),
+		Description: response.Server.Description,
+		Template:    response.Server.Template,
+		State:       response.Server.Action,
 	}
 
 	return server, nil
@@ -112,14 +118,35 @@ func (client v3Client) CreateServer(server *model.Node) api.A10Error {
 	return nil
 }
 
+//UpdateServer posts only server attributes of model.ServerFields managed for the node, a10 merges them into the server.
+//Addresses which are not set are left out, so a server which stops using an address family has to be created again
 func (client v3Client) UpdateServer(server *model.Node) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/server/{{.Server.A10Server}}"
 	request := updateServerRequest{
 		Base:   client.baseRequest,
 		Server: server,
+		Fields: model.Managed(model.ServerFields, server),
 	}
 	response := updateServerResponse{}
-	err := util.HTTPPut(urltpl, "a10/v3/tpl/server.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/server.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v3Client) DeleteServer(serverName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/server/{{.Name}}"
+	request := deleteServerRequest{
+		Base: client.baseRequest,
+		Name: serverName,
+	}
+	response := deleteServerResponse{}
+	err := util.HTTPDelete(urltpl, request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -179,14 +206,34 @@ func (client v3Client) CreateHealthMonitor(monitor *model.HealthCheck) api.A10Er
 	return nil
 }
 
+//UpdateHealthMonitor posts only monitor attributes of model.HealthCheckFields, a10 merges them into the monitor
 func (client v3Client) UpdateHealthMonitor(monitor *model.HealthCheck) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/axapi/v3/health/monitor/{{.Monitor.Name}}"
 	request := updateMonitorRequest{
 		Base:    client.baseRequest,
 		Monitor: monitor,
+		Fields:  model.Managed(model.HealthCheckFields, monitor),
 	}
 	response := updateMonitorResponse{}
-	err := util.HTTPPut(urltpl, "a10/v3/tpl/health.monitor.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/health.monitor.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v3Client) DeleteHealthMonitor(monitorName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/health/monitor/{{.Name}}"
+	request := deleteMonitorRequest{
+		Base: client.baseRequest,
+		Name: monitorName,
+	}
+	response := deleteMonitorResponse{}
+	err := util.HTTPDelete(urltpl, request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -219,7 +266,10 @@ func (client v3Client) GetServiceGroup(serviceGroupName string) (*model.ServiceG
 		Health: &model.HealthCheck{
 			Name: sg.HealthMonitorName,
 		},
-		Members: make([]*model.Member, len(sg.Members)),
+		Method:           sg.Method,
+		Protocol:         sg.Protocol,
+		MinActiveMembers: sg.MinActiveMember,
+		Members:          make([]*model.Member, len(sg.Members)),
 	}
 
 	for idx, member := range sg.Members {
@@ -227,6 +277,7 @@ func (client v3Client) GetServiceGroup(serviceGroupName string) (*model.ServiceG
 			Port:             member.Port,
 			ServerName:       member.ServerName,
 			ServiceGroupName: serviceGroup.Name,
+			Priority:         member.Priority,
 		}
 	}
 
@@ -251,14 +302,35 @@ func (client v3Client) CreateServiceGroup(serviceGroup *model.ServiceGroup) api.
 	return nil
 }
 
+//UpdateServiceGroup posts only service group attributes of model.ServiceGroupFields managed for the service group, a10 merges them
+//into the service group. Members are kept as they are and synced one by one through CreateMember and DeleteMember
 func (client v3Client) UpdateServiceGroup(serviceGroup *model.ServiceGroup) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.ServiceGroup.Name}}"
 	request := updateServiceGroupRequest{
 		Base:         client.baseRequest,
 		ServiceGroup: serviceGroup,
+		Fields:       model.Managed(model.ServiceGroupFields, serviceGroup),
 	}
 	response := updateServiceGroupResponse{}
-	err := util.HTTPPut(urltpl, "a10/v3/tpl/svcgrp.request", request, &response, client.commonHeaders)
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/svcgrp.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func (client v3Client) DeleteServiceGroup(serviceGroupName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.Name}}"
+	request := deleteServiceGroupRequest{
+		Base: client.baseRequest,
+		Name: serviceGroupName,
+	}
+	response := deleteServiceGroupResponse{}
+	err := util.HTTPDelete(urltpl, request, &response, client.commonHeaders)
 	if err != nil {
 		return buildA10Error(err)
 	}
@@ -269,6 +341,33 @@ func (client v3Client) UpdateServiceGroup(serviceGroup *model.ServiceGroup) api.
 	return nil
 }
 
+//GetMemberStatuses reads the operational state of service group members, members are up when their state is UP
+func (client v3Client) GetMemberStatuses(serviceGroupName string) ([]*model.MemberStatus, api.A10Error) {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.Name}}/oper"
+	request := getMemberStatusesRequest{
+		Base: client.baseRequest,
+		Name: serviceGroupName,
+	}
+	response := getMemberStatusesResponse{}
+	err := util.HTTPGet(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return nil, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return nil, response.Result.Error
+	}
+
+	statuses := make([]*model.MemberStatus, len(response.ServiceGroup.Members))
+	for idx, member := range response.ServiceGroup.Members {
+		statuses[idx] = &model.MemberStatus{
+			ServerName: member.ServerName,
+			Port:       member.Port,
+			Up:         member.Oper.State == "UP",
+		}
+	}
+	return statuses, nil
+}
+
 func (client v3Client) CreateMember(member *model.Member) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.Member.ServiceGroupName}}/member/"
 	request := createServiceGroupMemberRequest{
@@ -287,6 +386,25 @@ func (client v3Client) CreateMember(member *model.Member) api.A10Error {
 	return nil
 }
 
+//UpdateMember posts the member priority, a10 merges it into the member
+func (client v3Client) UpdateMember(member *model.Member) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.Member.ServiceGroupName}}/member/{{.Member.ServerName}}+{{.Member.Port}}"
+	request := updateServiceGroupMemberRequest{
+		Base:   client.baseRequest,
+		Member: member,
+	}
+	response := updateServiceGroupMemberResponse{}
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/svcgrp.member.update.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
 func (client v3Client) DeleteMember(member *model.Member) api.A10Error {
 	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/service-group/{{.Member.ServiceGroupName}}/member/{{.Member.ServerName}}+{{.Member.Port}}"
 	request := deleteServiceGroupMemberRequest{
@@ -305,6 +423,172 @@ func (client v3Client) DeleteMember(member *model.Member) api.A10Error {
 	return nil
 }
 
+func (client v3Client) GetVirtualServer(virtualServerName string) (*model.VirtualServer, api.A10Error) {
+	var virtualServer *model.VirtualServer
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/{{.Name}}"
+	request := getVirtualServerRequest{
+		Base: client.baseRequest,
+		Name: virtualServerName,
+	}
+	response := getVirtualServerResponse{}
+	err := util.HTTPGet(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return virtualServer, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return virtualServer, response.Result.Error
+	}
+
+	return buildVirtualServer(response.VirtualServer), nil
+}
+
+func (client v3Client) GetVirtualServers() ([]*model.VirtualServer, api.A10Error) {
+	var virtualServers []*model.VirtualServer
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/"
+	request := getVirtualServersRequest{
+		Base: client.baseRequest,
+	}
+	response := getVirtualServersResponse{}
+	err := util.HTTPGet(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return virtualServers, buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return virtualServers, response.Result.Error
+	}
+
+	virtualServers = make([]*model.VirtualServer, len(response.VirtualServers))
+	for idx, virtualServer := range response.VirtualServers {
+		virtualServers[idx] = buildVirtualServer(virtualServer)
+	}
+
+	return virtualServers, nil
+}
+
+func (client v3Client) CreateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/"
+	request := createVirtualServerRequest{
+		Base:          client.baseRequest,
+		VirtualServer: virtualServer,
+		IPv6:          util.IsIPv6(virtualServer.IPAddress),
+	}
+	response := createVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/vserver.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+//UpdateVirtualServer posts only managed virtual server attributes, address, description when set and ports, a10 merges them into
+//the virtual server. Ports which are no longer served are deleted one by one, other virtual server attributes are kept by a10
+func (client v3Client) UpdateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	current, a10err := client.GetVirtualServer(virtualServer.Name)
+	if a10err != nil {
+		return a10err
+	}
+
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/{{.VirtualServer.Name}}"
+	request := updateVirtualServerRequest{
+		Base:          client.baseRequest,
+		VirtualServer: virtualServer,
+		IPv6:          util.IsIPv6(virtualServer.IPAddress),
+	}
+	response := updateVirtualServerResponse{}
+	err := util.HTTPPost(urltpl, "a10/v3/tpl/vserver.request", request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	for _, port := range current.Ports {
+		if servesPort(virtualServer, port) {
+			continue
+		}
+		a10err = client.deleteVirtualPort(virtualServer.Name, port)
+		if a10err != nil {
+			return a10err
+		}
+	}
+
+	return nil
+}
+
+func (client v3Client) deleteVirtualPort(virtualServerName string, port *model.VirtualPort) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/{{.Name}}/port/{{.Port.Port}}+{{.Port.Protocol}}"
+	request := deleteVirtualPortRequest{
+		Base: client.baseRequest,
+		Name: virtualServerName,
+		Port: port,
+	}
+	response := deleteVirtualPortResponse{}
+	err := util.HTTPDelete(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+//servesPort checks the virtual server has a port with the same number and protocol, ports are identified by both in a10
+func servesPort(virtualServer *model.VirtualServer, port *model.VirtualPort) bool {
+	for _, served := range virtualServer.Ports {
+		if served.Port == port.Port && served.Protocol == port.Protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func (client v3Client) DeleteVirtualServer(virtualServerName string) api.A10Error {
+	urltpl := "{{.Base.A10URL}}/axapi/v3/slb/virtual-server/{{.Name}}"
+	request := deleteVirtualServerRequest{
+		Base: client.baseRequest,
+		Name: virtualServerName,
+	}
+	response := deleteVirtualServerResponse{}
+	err := util.HTTPDelete(urltpl, request, &response, client.commonHeaders)
+	if err != nil {
+		return buildA10Error(err)
+	}
+	if response.Result.Status == "fail" {
+		return response.Result.Error
+	}
+
+	return nil
+}
+
+func buildVirtualServer(vs virtualServer) *model.VirtualServer {
+	virtualServer := &model.VirtualServer{
+		Name:        vs.Name,
+		IPAddress:   vs.IPAddress,
+		Description: vs.Description,
+		Ports:       make([]*model.VirtualPort, len(vs.Ports)),
+	}
+	if len(virtualServer.IPAddress) == 0 {
+		virtualServer.IPAddress = vs.IPv6Address
+	}
+
+	for idx, port := range vs.Ports {
+		virtualServer.Ports[idx] = &model.VirtualPort{
+			Port:             port.Port,
+			Protocol:         port.Protocol,
+			ServiceGroupName: port.ServiceGroupName,
+		}
+	}
+
+	return virtualServer
+}
+
 func (client v3Client) IsServerNotFound(err api.A10Error) bool {
 	return err.Code() == 1023460352
 }
@@ -320,3 +604,7 @@ func (client v3Client) IsServiceGroupNotFound(err api.A10Error) bool {
 func (client v3Client) IsMemberAlreadyExists(err api.A10Error) bool {
 	return err.Code() == 1405
 }
+
+func (client v3Client) IsVirtualServerNotFound(err api.A10Error) bool {
+	return err.Code() == 1023460352
+}
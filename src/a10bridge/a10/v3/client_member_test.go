@@ -126,3 +126,76 @@ func testDeleteMember_Failure(testServer *testing.ServerConfig, assert *assert.A
 	assert.NotNil(err, "Expected error when create member call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateMember_withPriority(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	member := model.Member{
+		ServiceGroupName: "sg_name",
+		ServerName:       "srv_name",
+		Port:             8080,
+		Priority:         16,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/service-group/"+member.ServiceGroupName+"/member/").
+		Body(`{
+  "member" : {
+    "name" : "`+member.ServerName+`",
+    "port" : `+strconv.Itoa(member.Port)+`,
+    "member-state": "enable",
+    "member-stats-data-disable": 0,
+    "member-priority": 16
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateMember(&member)
+	assert.Nil(err, "Unexpected error when creating member")
+}
+
+func testUpdateMember(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	member := model.Member{
+		ServiceGroupName: "sg_name",
+		ServerName:       "srv_name",
+		Port:             8080,
+		Priority:         2,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/service-group/"+member.ServiceGroupName+"/member/"+member.ServerName+"+"+strconv.Itoa(member.Port)).
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Body(`{
+  "member" : {
+    "name" : "`+member.ServerName+`",
+    "port" : `+strconv.Itoa(member.Port)+`,
+    "member-priority": 2
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateMember(&member)
+	assert.Nil(err, "Unexpected error when updating member")
+}
+
+func testUpdateMember_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+	member := model.Member{
+		ServiceGroupName: "sg name",
+		ServerName:       "srv name",
+		Port:             8080,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"HTTP","msg":"Unauthorized"}}}`, "application/json")
+
+	err := client.UpdateMember(&member)
+	assert.NotNil(err, "Expected error when update member call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
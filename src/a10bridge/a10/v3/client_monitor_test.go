@@ -24,7 +24,7 @@ func testUpdateMonitor(testServer *testing.ServerConfig, assert *assert.Assertio
 
 	testServer.Reset().
 		AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/health/monitor/"+monitor.Name).
 		Header("Authorization", "A10 "+helper.GetSessionID(client)).
 		Body(`{
@@ -35,9 +35,6 @@ func testUpdateMonitor(testServer *testing.ServerConfig, assert *assert.Assertio
     "interval": `+strconv.Itoa(monitor.Interval)+`,
     "timeout": `+strconv.Itoa(monitor.Timeout)+`,
     "override-port": `+strconv.Itoa(monitor.Port)+`,
-    "passive":0,
-    "strict-retry-on-server-err-resp":1,
-    "disable-after-down":0,
     "method":{
       "http": {
         "http":1,
@@ -46,8 +43,7 @@ func testUpdateMonitor(testServer *testing.ServerConfig, assert *assert.Assertio
         "http-expect":1,
         "http-response-code": "`+monitor.ExpectCode+`",
         "url-type":"GET",
-        "url-path":"`+monitor.Endpoint+`",
-        "http-kerberos-auth":0
+        "url-path":"`+monitor.Endpoint+`"
       }
     }
   }
@@ -345,3 +341,27 @@ func testGetMonitor_Failure(testServer *testing.ServerConfig, assert *assert.Ass
 	assert.NotNil(err, "Expected error when get monitor call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testDeleteMonitor(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodDelete).
+		Path("/axapi/v3/health/monitor/monitor").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteHealthMonitor("monitor")
+	assert.Nil(err, "Unexpected error when deleting health monitor")
+}
+
+func testDeleteMonitor_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteHealthMonitor("monitor")
+	assert.NotNil(err, "Expected error when delete health monitor call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
@@ -19,16 +19,39 @@ func testUpdateServer(testServer *testing.ServerConfig, assert *assert.Assertion
 
 	testServer.Reset().
 		AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/slb/server/"+node.A10Server).
 		Header("Authorization", "A10 "+helper.GetSessionID(client)).
 		Body(`{
   "server": {
     "name": "`+node.A10Server+`",
     "host": "`+node.IPAddress+`",
-    "action": "enable",
-    "weight": `+node.Weight+`,
-    "conn-limit": 8000000
+    "weight": `+node.Weight+`
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateServer(&node)
+	assert.Nil(err, "Unexpected error when updating server")
+}
+
+func testUpdateServer_ipv6Only(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server:   "server",
+		IPv6Address: "fd00::11",
+		Weight:      "1",
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/server/"+node.A10Server).
+		Body(`{
+  "server": {
+    "name": "`+node.A10Server+`",
+    "server-ipv6-addr": "`+node.IPv6Address+`",
+    "weight": `+node.Weight+`
   }
 }`).
 		Response().
@@ -101,6 +124,58 @@ func testCreateServer(testServer *testing.ServerConfig, assert *assert.Assertion
 	assert.Nil(err, "Unexpected error when creating server")
 }
 
+func testCreateServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server:   "server",
+		IPv6Address: "fd00::11",
+		Weight:      "1",
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/server/").
+		Body(`{
+  "server": {
+    "name": "`+node.A10Server+`",
+    "server-ipv6-addr": "`+node.IPv6Address+`",
+    "action": "enable",
+    "weight": `+node.Weight+`,
+    "conn-limit": 8000000
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServer(&node)
+	assert.Nil(err, "Unexpected error when creating ipv6 server")
+}
+
+func testCreateServer_unsetWeight(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server: "server",
+		IPAddress: "10.10.10.11",
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/server/").
+		Body(`{
+  "server": {
+    "name": "`+node.A10Server+`",
+    "host": "`+node.IPAddress+`",
+    "action": "enable",
+    "conn-limit": 8000000
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServer(&node)
+	assert.Nil(err, "Unexpected error when creating server without weight")
+}
+
 func testCreateServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
 	node := model.Node{
 		A10Server: "server",
@@ -161,6 +236,26 @@ func testGetServer(testServer *testing.ServerConfig, assert *assert.Assertions,
 	assert.Equal(0, len(node.Labels))
 }
 
+func testGetServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	serverName := "server"
+	ipAddress := "10.201.14.203"
+	ipv6Address := "fd00::cb"
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/server/"+serverName).
+		Response().
+		Body(`{"server":{"name":"`+serverName+`","host":"`+ipAddress+`","server-ipv6-addr":"`+ipv6Address+`","weight":1}}`, "application/json")
+
+	node, err := client.GetServer(serverName)
+
+	assert.Nil(err, "Unexpected error when getting dual stack server")
+	assert.NotNil(node, "Expected node instance")
+	assert.Equal(ipAddress, node.IPAddress)
+	assert.Equal(ipv6Address, node.IPv6Address)
+}
+
 func testGetServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
 	testServer.Reset().
 		AddRequest().
@@ -183,3 +278,109 @@ func testGetServer_Failure(testServer *testing.ServerConfig, assert *assert.Asse
 	assert.NotNil(err, "Expected error when get server call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateServer_withAttributes(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server:   "server",
+		IPAddress:   "10.10.10.11",
+		Weight:      "1",
+		ConnLimit:   1000,
+		SlowStart:   "true",
+		Description: "k8s dc-kube node server",
+		Template:    "k8s-nodes",
+		State:       model.ServerStateDisable,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/server/").
+		Body(`{
+  "server": {
+    "name": "`+node.A10Server+`",
+    "host": "`+node.IPAddress+`",
+    "slow-start": 1,
+    "description": "`+node.Description+`",
+    "template-server": "k8s-nodes",
+    "action": "disable",
+    "weight": `+node.Weight+`,
+    "conn-limit": 1000
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.CreateServer(&node)
+	assert.Nil(err, "Unexpected error when creating server")
+}
+
+func testGetServer_attributes(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/server/server").
+		Response().
+		Body(`{"server":{"name":"server","host":"10.10.10.11","action":"disable","weight":1,"conn-limit":1000,"slow-start":1,"description":"k8s dc-kube node server","template-server":"k8s-nodes"}}`, "application/json")
+
+	node, err := client.GetServer("server")
+
+	assert.Nil(err, "Unexpected error when getting server")
+	assert.Equal(1000, node.ConnLimit)
+	assert.Equal("true", node.SlowStart)
+	assert.Equal("k8s dc-kube node server", node.Description)
+	assert.Equal("k8s-nodes", node.Template)
+	assert.Equal(model.ServerStateDisable, node.State)
+}
+
+func testUpdateServer_withAttributes(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	node := model.Node{
+		A10Server: "server",
+		IPAddress: "10.10.10.11",
+		Weight:    "1",
+		ConnLimit: 1000,
+		State:     model.ServerStateEnable,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/server/"+node.A10Server).
+		Body(`{
+  "server": {
+    "name": "`+node.A10Server+`",
+    "host": "`+node.IPAddress+`",
+    "conn-limit": 1000,
+    "action": "enable",
+    "weight": `+node.Weight+`
+  }
+}`).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateServer(&node)
+	assert.Nil(err, "Unexpected error when updating server")
+}
+
+func testDeleteServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodDelete).
+		Path("/axapi/v3/slb/server/server").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteServer("server")
+	assert.Nil(err, "Unexpected error when deleting server")
+}
+
+func testDeleteServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteServer("server")
+	assert.NotNil(err, "Expected error when delete server call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
@@ -27,20 +27,14 @@ func testUpdateServiceGroup(testServer *testing.ServerConfig, assert *assert.Ass
 
 	testServer.Reset().
 		AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/slb/service-group/"+svcGroup.Name).
 		Header("Authorization", "A10 "+helper.GetSessionID(client)).
 		Body(`{
   "service-group": {
     "name": "`+svcGroup.Name+`",
-    "protocol": "tcp",
     "health-check": "`+svcGroup.Health.Name+`",
-    "member-list": [
-      {
-        "name" : "`+svcGroup.Members[0].ServerName+`",
-        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
-      }
-    ] 
+    "protocol": "tcp"
   }
 }`).
 		Response().
@@ -83,6 +77,31 @@ func testUpdateServiceGroup(testServer *testing.ServerConfig, assert *assert.Ass
 	assert.Nil(err, "Unexpected error when updating service group")
 }
 
+func testUpdateServiceGroup_resetMinActiveMembers(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:                  "test name",
+		ResetMinActiveMembers: true,
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/service-group/"+svcGroup.Name).
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Body(`{
+  "service-group": {
+    "name": "`+svcGroup.Name+`",
+    "min-active-member": 0,
+    "protocol": "tcp"
+  }
+}`).
+		Response().
+		Body(`{"service-group": {"name":"`+svcGroup.Name+`"}}`, "application/json")
+
+	err := client.UpdateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when updating service group")
+}
+
 func testUpdateServiceGroup_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
 	svcGroup := model.ServiceGroup{
 		Name: "test name",
@@ -285,6 +304,7 @@ func testGetServiceGroup(testServer *testing.ServerConfig, assert *assert.Assert
     "name":"`+expected.Name+`",
     "protocol":"tcp",
     "lb-method":"round-robin",
+    "min-active-member":2,
     "stateless-auto-switch":0,
     "reset-on-server-selection-fail":0,
     "priority-affinity":0,
@@ -321,11 +341,14 @@ func testGetServiceGroup(testServer *testing.ServerConfig, assert *assert.Assert
 	assert.NotNil(svcGroup, "Expected service group instance")
 	assert.Equal(expected.Name, svcGroup.Name)
 	assert.Equal(expected.Health.Name, svcGroup.Health.Name)
+	assert.Equal(model.ProtocolTCP, svcGroup.Protocol)
+	assert.Equal(2, svcGroup.MinActiveMembers)
 	assert.Equal(len(expected.Members), len(svcGroup.Members))
 	assert.Equal(1, len(svcGroup.Members))
 	assert.Equal(expected.Members[0].ServerName, svcGroup.Members[0].ServerName)
 	assert.Equal(expected.Members[0].Port, svcGroup.Members[0].Port)
 	assert.Equal(expected.Members[0].ServiceGroupName, svcGroup.Members[0].ServiceGroupName)
+	assert.Equal(1, svcGroup.Members[0].Priority)
 }
 
 func testGetServiceGroup_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
@@ -350,3 +373,117 @@ func testGetServiceGroup_Failure(testServer *testing.ServerConfig, assert *asser
 	assert.NotNil(err, "Expected error when get service group call fails in a10")
 	assert.Equal(errorCode, err.Code())
 }
+
+func testCreateServiceGroup_withMethod(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:   "test name",
+		Method: "least-connection",
+		Members: []*model.Member{
+			&model.Member{
+				ServiceGroupName: "test name",
+				ServerName:       "server name",
+				Port:             8080,
+			},
+		},
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/service-group/").
+		Body(`{
+  "service-group": {
+    "name": "`+svcGroup.Name+`",
+    "protocol": "tcp",
+    "lb-method": "`+svcGroup.Method+`",
+    "member-list": [
+      {
+        "name" : "`+svcGroup.Members[0].ServerName+`",
+        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
+      }
+    ] 
+  }
+}`).
+		Response().
+		Body(`{"service-group": {"name":"`+svcGroup.Name+`"}}`, "application/json")
+
+	err := client.CreateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when creating service group")
+}
+
+func testCreateServiceGroup_withOptions(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	svcGroup := model.ServiceGroup{
+		Name:             "test name",
+		Protocol:         model.ProtocolUDP,
+		MinActiveMembers: 2,
+		Members: []*model.Member{
+			&model.Member{
+				ServiceGroupName: "test name",
+				ServerName:       "server name",
+				Port:             8053,
+			},
+		},
+	}
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/service-group/").
+		Body(`{
+  "service-group": {
+    "name": "`+svcGroup.Name+`",
+    "protocol": "udp",
+    "min-active-member": 2,
+    "member-list": [
+      {
+        "name" : "`+svcGroup.Members[0].ServerName+`",
+        "port" : `+strconv.Itoa(svcGroup.Members[0].Port)+`
+      }
+    ] 
+  }
+}`).
+		Response().
+		Body(`{"service-group": {"name":"`+svcGroup.Name+`"}}`, "application/json")
+
+	err := client.CreateServiceGroup(&svcGroup)
+	assert.Nil(err, "Unexpected error when creating service group")
+}
+
+func testGetMemberStatuses(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/service-group/sg/oper").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{
+  "service-group": {
+    "oper": {"state": "Functional Up"},
+    "name": "sg",
+    "member-list": [
+      {"oper": {"state": "UP", "curr_conn": 0}, "name": "srv1", "port": 8080},
+      {"oper": {"state": "DOWN", "curr_conn": 0}, "name": "srv2", "port": 8080}
+    ]
+  }
+}`, "application/json")
+
+	statuses, err := client.GetMemberStatuses("sg")
+	assert.Nil(err, "Unexpected error when getting member statuses")
+	assert.Equal([]*model.MemberStatus{
+		&model.MemberStatus{ServerName: "srv1", Port: 8080, Up: true},
+		&model.MemberStatus{ServerName: "srv2", Port: 8080, Up: false},
+	}, statuses)
+}
+
+func testGetMemberStatuses_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1023460352
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"CM","msg":"Object service-group {sg} does not exist"}}}`, "application/json")
+
+	statuses, err := client.GetMemberStatuses("sg")
+	assert.Nil(statuses)
+	assert.NotNil(err, "Expected error when get member statuses call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
@@ -0,0 +1,313 @@
+package v3_test
+
+import (
+	"a10bridge/a10/api"
+	"a10bridge/model"
+	"a10bridge/testing"
+	"net/http"
+	"strconv"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testVirtualServer() model.VirtualServer {
+	return model.VirtualServer{
+		Name:        "k8s-default-web",
+		IPAddress:   "10.0.0.10",
+		Description: "a10bridge k8s service default/web",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{
+				Port:             80,
+				Protocol:         "tcp",
+				ServiceGroupName: "k8s-default-web-80",
+			},
+		},
+	}
+}
+
+func testGetVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/virtual-server/"+virtualServer.Name).
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{
+  "virtual-server": {
+    "name":"`+virtualServer.Name+`",
+    "ip-address":"`+virtualServer.IPAddress+`",
+    "description":"`+virtualServer.Description+`",
+    "enable-disable-action":"enable",
+    "port-list": [
+      {
+        "port-number":`+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "protocol":"`+virtualServer.Ports[0].Protocol+`",
+        "service-group":"`+virtualServer.Ports[0].ServiceGroupName+`",
+        "uuid":"fafe860c-fb11-11e7-bdaf-97f82d417abc"
+      }
+    ]
+  }
+}`, "application/json")
+
+	a10VirtualServer, err := client.GetVirtualServer(virtualServer.Name)
+	assert.Nil(err, "Unexpected error when getting virtual server")
+	assert.Equal(virtualServer, *a10VirtualServer)
+}
+
+func testGetVirtualServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/virtual-server/vs").
+		Response().
+		Body(`{"virtual-server": {"name":"vs","ipv6-address":"fd00::10","port-list": []}}`, "application/json")
+
+	a10VirtualServer, err := client.GetVirtualServer("vs")
+	assert.Nil(err, "Unexpected error when getting virtual server")
+	assert.Equal("fd00::10", a10VirtualServer.IPAddress)
+	assert.Empty(a10VirtualServer.Ports)
+}
+
+func testGetVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1023460352
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"CM","msg":"Object not found"}}}`, "application/json")
+
+	a10VirtualServer, err := client.GetVirtualServer("vs")
+	assert.Nil(a10VirtualServer)
+	assert.NotNil(err, "Expected error when virtual server doesn't exist")
+	assert.True(client.IsVirtualServerNotFound(err))
+}
+
+func testGetVirtualServers(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/virtual-server/").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{
+  "virtual-server-list": [
+    {
+      "name":"`+virtualServer.Name+`",
+      "ip-address":"`+virtualServer.IPAddress+`",
+      "description":"`+virtualServer.Description+`",
+      "port-list": [
+        {
+          "port-number":`+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+          "protocol":"`+virtualServer.Ports[0].Protocol+`",
+          "service-group":"`+virtualServer.Ports[0].ServiceGroupName+`"
+        }
+      ]
+    }
+  ]
+}`, "application/json")
+
+	a10VirtualServers, err := client.GetVirtualServers()
+	assert.Nil(err, "Unexpected error when getting virtual servers")
+	assert.Len(a10VirtualServers, 1)
+	assert.Equal(virtualServer, *a10VirtualServers[0])
+}
+
+func testGetVirtualServers_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	_, err := client.GetVirtualServers()
+	assert.NotNil(err, "Expected error when get virtual servers call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
+
+func testCreateVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/virtual-server/").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Body(`{
+  "virtual-server": {
+    "name": "`+virtualServer.Name+`",
+    "ip-address": "`+virtualServer.IPAddress+`",
+    "description": "`+virtualServer.Description+`",
+    "port-list": [
+      {
+        "port-number": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "protocol": "`+virtualServer.Ports[0].Protocol+`",
+        "service-group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"virtual-server": {"name":"`+virtualServer.Name+`"}}`, "application/json")
+
+	err := client.CreateVirtualServer(&virtualServer)
+	assert.Nil(err, "Unexpected error when creating virtual server")
+}
+
+func testCreateVirtualServer_IPv6(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+	virtualServer.IPAddress = "fd00::10"
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/virtual-server/").
+		Body(`{
+  "virtual-server": {
+    "name": "`+virtualServer.Name+`",
+    "ipv6-address": "`+virtualServer.IPAddress+`",
+    "description": "`+virtualServer.Description+`",
+    "port-list": [
+      {
+        "port-number": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "protocol": "`+virtualServer.Ports[0].Protocol+`",
+        "service-group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"virtual-server": {"name":"`+virtualServer.Name+`"}}`, "application/json")
+
+	err := client.CreateVirtualServer(&virtualServer)
+	assert.Nil(err, "Unexpected error when creating virtual server")
+}
+
+func testCreateVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"HTTP","msg":"Unauthorized"}}}`, "application/json")
+
+	err := client.CreateVirtualServer(&virtualServer)
+	assert.NotNil(err, "Expected error when create virtual server call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
+
+func testUpdateVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodGet).
+		Path("/axapi/v3/slb/virtual-server/"+virtualServer.Name).
+		Response().
+		Body(`{
+  "virtual-server": {
+    "name":"`+virtualServer.Name+`",
+    "ip-address":"10.0.0.2",
+    "port-list": [
+      {"port-number":`+strconv.Itoa(virtualServer.Ports[0].Port)+`,"protocol":"`+virtualServer.Ports[0].Protocol+`","service-group":"other"},
+      {"port-number":443,"protocol":"tcp","service-group":"vs-443"}
+    ]
+  }
+}`, "application/json")
+	testServer.
+		AddRequest().
+		Method(http.MethodPost).
+		Path("/axapi/v3/slb/virtual-server/"+virtualServer.Name).
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Body(`{
+  "virtual-server": {
+    "name": "`+virtualServer.Name+`",
+    "ip-address": "`+virtualServer.IPAddress+`",
+    "description": "`+virtualServer.Description+`",
+    "port-list": [
+      {
+        "port-number": `+strconv.Itoa(virtualServer.Ports[0].Port)+`,
+        "protocol": "`+virtualServer.Ports[0].Protocol+`",
+        "service-group": "`+virtualServer.Ports[0].ServiceGroupName+`"
+      }
+    ]
+  }
+}`).
+		Response().
+		Body(`{"virtual-server": {"name":"`+virtualServer.Name+`"}}`, "application/json")
+	testServer.
+		AddRequest().
+		Method(http.MethodDelete).
+		Path("/axapi/v3/slb/virtual-server/"+virtualServer.Name+"/port/443+tcp").
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.UpdateVirtualServer(&virtualServer)
+	assert.Nil(err, "Unexpected error when updating virtual server")
+}
+
+func testUpdateVirtualServer_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	virtualServer := testVirtualServer()
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.UpdateVirtualServer(&virtualServer)
+	assert.NotNil(err, "Expected error when update virtual server call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
+
+func testDeleteVirtualServer(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodDelete).
+		Path("/axapi/v3/slb/virtual-server/vs").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteVirtualServer("vs")
+	assert.Nil(err, "Unexpected error when deleting virtual server")
+}
+
+func testDeleteVirtualServer_Failure(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	errorCode := 1009
+
+	testServer.Reset().
+		AddRequest().
+		Response().
+		Body(`{"response":{"status":"fail","err":{"code":`+strconv.Itoa(errorCode)+`,"from":"HTTP","msg":"Unauthorized"}}}`, "application/json")
+
+	err := client.DeleteVirtualServer("vs")
+	assert.NotNil(err, "Expected error when delete virtual server call fails in a10")
+	assert.Equal(errorCode, err.Code())
+}
+
+func testDeleteServiceGroup(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Method(http.MethodDelete).
+		Path("/axapi/v3/slb/service-group/sg").
+		Header("Authorization", "A10 "+helper.GetSessionID(client)).
+		Response().
+		Body(`{"response": {"status": "OK"}}`, "application/json")
+
+	err := client.DeleteServiceGroup("sg")
+	assert.Nil(err, "Unexpected error when deleting service group")
+}
+
+func testDeleteServiceGroup_ServerError(testServer *testing.ServerConfig, assert *assert.Assertions, client api.Client) {
+	testServer.Reset().
+		AddRequest().
+		Response().
+		StatusCode(500)
+
+	err := client.DeleteServiceGroup("sg")
+	assert.NotNil(err, "Expected error when delete service group call fails because of server issues")
+	assert.Equal(0, err.Code(), "Expected 0 failure code for errors not returned by a10")
+}
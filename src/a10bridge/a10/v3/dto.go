@@ -46,18 +46,31 @@ type getServerRequest = nameRequest
 type getServerResponse struct {
 	Result result `json:"response"`
 	Server struct {
-		Name   string `json:"name"`
-		IP     string `json:"host"`
-		Weight int    `json:"weight"`
+		Name        string `json:"name"`
+		IP          string `json:"host"`
+		IPv6        string `json:"server-ipv6-addr"`
+		Weight      int    `json:"weight"`
+		ConnLimit   int    `json:"conn-limit"`
+		SlowStart   int    `json:"slow-start"`
+		Description string `json:"description"`
+		Template    string `json:"template-server"`
+		Action      string `json:"action"`
 	} `json:"server"`
 }
 
 type createServerRequest = serverRequest
 type createServerResponse = simpleResponse
 
-type updateServerRequest = serverRequest
+type updateServerRequest struct {
+	Base   baseRequest
+	Server *model.Node
+	Fields model.ManagedFields
+}
 type updateServerResponse = simpleResponse
 
+type deleteServerRequest = nameRequest
+type deleteServerResponse = simpleResponse
+
 type monitorRequest struct {
 	Base    baseRequest
 	Monitor *model.HealthCheck
@@ -86,9 +99,16 @@ type getMonitorResponse struct {
 type createMonitorRequest = monitorRequest
 type createMonitorResponse = simpleResponse
 
-type updateMonitorRequest = monitorRequest
+type updateMonitorRequest struct {
+	Base    baseRequest
+	Monitor *model.HealthCheck
+	Fields  model.ManagedFields
+}
 type updateMonitorResponse = simpleResponse
 
+type deleteMonitorRequest = nameRequest
+type deleteMonitorResponse = simpleResponse
+
 type serviceGroupRequest struct {
 	Base         baseRequest
 	ServiceGroup *model.ServiceGroup
@@ -100,9 +120,13 @@ type getServiceGroupResponse struct {
 	ServiceGroup struct {
 		Name              string `json:"name"`
 		HealthMonitorName string `json:"health-check"`
+		Method            string `json:"lb-method"`
+		Protocol          string `json:"protocol"`
+		MinActiveMember   int    `json:"min-active-member"`
 		Members           []struct {
 			ServerName string `json:"name"`
 			Port       int    `json:"port"`
+			Priority   int    `json:"member-priority"`
 		} `json:"member-list"`
 	} `json:"service-group"`
 }
@@ -110,7 +134,11 @@ type getServiceGroupResponse struct {
 type createServiceGroupRequest = serviceGroupRequest
 type createServiceGroupResponse = simpleResponse
 
-type updateServiceGroupRequest = serviceGroupRequest
+type updateServiceGroupRequest struct {
+	Base         baseRequest
+	ServiceGroup *model.ServiceGroup
+	Fields       model.ManagedFields
+}
 type updateServiceGroupResponse = simpleResponse
 
 type serviceGroupMemberRequest struct {
@@ -121,5 +149,71 @@ type serviceGroupMemberRequest struct {
 type createServiceGroupMemberRequest = serviceGroupMemberRequest
 type createServiceGroupMemberResponse = simpleResponse
 
+type updateServiceGroupMemberRequest = serviceGroupMemberRequest
+type updateServiceGroupMemberResponse = simpleResponse
+
 type deleteServiceGroupMemberRequest = serviceGroupMemberRequest
 type deleteServiceGroupMemberResponse = simpleResponse
+
+type virtualServerRequest struct {
+	Base          baseRequest
+	VirtualServer *model.VirtualServer
+	IPv6          bool
+}
+
+type virtualServer struct {
+	Name        string `json:"name"`
+	IPAddress   string `json:"ip-address"`
+	IPv6Address string `json:"ipv6-address"`
+	Description string `json:"description"`
+	Ports       []struct {
+		Port             int    `json:"port-number"`
+		Protocol         string `json:"protocol"`
+		ServiceGroupName string `json:"service-group"`
+	} `json:"port-list"`
+}
+
+type getVirtualServerRequest = nameRequest
+type getVirtualServerResponse struct {
+	Result        result        `json:"response"`
+	VirtualServer virtualServer `json:"virtual-server"`
+}
+
+type getVirtualServersRequest = nameRequest
+type getVirtualServersResponse struct {
+	Result         result          `json:"response"`
+	VirtualServers []virtualServer `json:"virtual-server-list"`
+}
+
+type createVirtualServerRequest = virtualServerRequest
+type createVirtualServerResponse = simpleResponse
+
+type updateVirtualServerRequest = virtualServerRequest
+type updateVirtualServerResponse = simpleResponse
+
+type deleteVirtualServerRequest = nameRequest
+type deleteVirtualServerResponse = simpleResponse
+
+type deleteVirtualPortRequest struct {
+	Base baseRequest
+	Name string
+	Port *model.VirtualPort
+}
+type deleteVirtualPortResponse = simpleResponse
+
+type deleteServiceGroupRequest = nameRequest
+type deleteServiceGroupResponse = simpleResponse
+
+type getMemberStatusesRequest = nameRequest
+type getMemberStatusesResponse struct {
+	Result       result `json:"response"`
+	ServiceGroup struct {
+		Members []struct {
+			ServerName string `json:"name"`
+			Port       int    `json:"port"`
+			Oper       struct {
+				State string `json:"state"`
+			} `json:"oper"`
+		} `json:"member-list"`
+	} `json:"service-group"`
+}
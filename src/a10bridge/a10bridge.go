@@ -1,20 +1,30 @@
 package main
 
 import (
+	"a10bridge/apiserver"
 	"a10bridge/config"
 	"a10bridge/model"
 	"a10bridge/processor"
 	"a10bridge/util"
+	"a10bridge/webhook"
+	"errors"
+	"fmt"
+	"hash/fnv"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 )
 
 var processorBuildK8sProcessor = processor.BuildK8sProcessor
+var processorBuildClusterK8sProcessor = processor.BuildClusterK8sProcessor
+var processorBuildStateSources = processor.BuildStateSources
 var configBuildConfig = config.BuildConfig
 var processorBuildA10Processors = processor.BuildA10Processors
+var webhookStart = webhook.Start
+var timeSleep = time.Sleep
 
 type exitCode = int
 
@@ -24,6 +34,7 @@ const (
 	ExcutionTimedOut           exitCode = 2
 	FailedToBuildExpectedState exitCode = 3
 	FailedToProcessA10Instance exitCode = 4
+	CanaryFailed               exitCode = 5
 )
 
 func main() {
@@ -38,6 +49,13 @@ func mainInternal() exitCode {
 		return FailedToBuildConfig
 	}
 
+	if len(*context.Arguments.WebhookAddr) > 0 {
+		go func() {
+			err := webhookStart(*context.Arguments.WebhookAddr, *context.Arguments.WebhookCert, *context.Arguments.WebhookKey, k8sClientConfig(context.Arguments), context.Environment)
+			glog.Errorf("Validating webhook has stopped. error: %s", err)
+		}()
+	}
+
 	done := make(chan exitCode)
 	interval := time.Second * time.Duration(*context.Arguments.Interval)
 	ticker := time.NewTicker(interval)
@@ -76,50 +94,405 @@ func mainInternal() exitCode {
 	return executionFunc()
 }
 
+//k8sClientConfig selects the kubernetes cluster a10bridge works with when clusters are not configured
+func k8sClientConfig(args *config.Args) apiserver.ClientConfig {
+	return apiserver.ClientConfig{
+		Kubeconfig: *args.Kubeconfig,
+		Context:    *args.Context,
+		Master:     *args.Master,
+		InCluster:  *args.InCluster,
+	}
+}
+
+//expectedState desired state of a10 instances built by inspecting kubernetes configuration
+type expectedState struct {
+	//service groups by a10 instance name, their names may depend on the instance
+	serviceGroups map[string]map[string]*model.ServiceGroup
+	environment   *model.Environment
+	nodes         map[string]*model.Node
+	loadBalancers []*model.LoadBalancer
+	resources     []*model.A10ServiceGroup
+	trafficShifts []*trafficShift
+	//partial set when state of some clusters is not known
+	partial bool
+}
+
+//clusterState expected state built from a single kubernetes cluster or static source, the state is nil when it couldn't be built.
+//Static sources have no kubernetes processor
+type clusterState struct {
+	environmentConfig *config.EnvironmentConfig
+	k8sProcessor      processor.K8sProcessor
+	state             *expectedState
+}
+
+//trafficShift configured traffic shift with its progress
+type trafficShift struct {
+	config config.TrafficShiftConfig
+	state  *model.TrafficShiftState
+}
+
+//syncReport outcomes of syncing the expected state into a10 instances
+type syncReport struct {
+	results map[string][]*model.SyncResult
+	events  []*model.SyncEvent
+	//applied states applied to a10 instances by instance name, nil when they are not kept
+	applied map[string]*model.AppliedState
+	//loadBalancers load balancers processed in a10 instances, true when virtual servers were synced in all of them
+	loadBalancers map[*model.LoadBalancer]bool
+	//removedMembers members removed from service groups of all a10 instances, counted into the removal limit of the run
+	removedMembers int
+}
+
 func reconcile(context *config.RunContext) exitCode {
-	serviceGroups, nodesMap, err := buildexpectedState(context)
+	clusters, err := buildClusterStates(context)
 	if err != nil {
 		glog.Errorf("Failed to build expected state by inspecting kubernetes configuration. error: %s", err)
 		return FailedToBuildExpectedState
 	}
+	clusters = append(clusters, buildSourceStates(context, clusters)...)
+	state := mergeClusterStates(clusters)
+
+	//progress of traffic shifts is kept in the first cluster
+	primary := clusters[0]
+	if len(context.TrafficShifts.Shifts) > 0 {
+		if primary.state == nil {
+			glog.Error("Skipping traffic shifts, state of the cluster keeping their progress is not known")
+		} else {
+			state.trafficShifts, err = applyTrafficShifts(context, primary, state)
+			if err != nil {
+				return FailedToBuildExpectedState
+			}
+		}
+	}
 
 	if *context.Arguments.Sort {
 		sort.Sort(context.A10Instances)
 	}
 
 	result := Normal
+	report := &syncReport{
+		results:       make(map[string][]*model.SyncResult),
+		events:        make([]*model.SyncEvent, 0),
+		loadBalancers: make(map[*model.LoadBalancer]bool),
+	}
 
-	for _, a10Instance := range context.A10Instances {
-		err := processContext(context, &a10Instance, serviceGroups, nodesMap)
+	appliedStateStore := buildAppliedStateStore(context, primary)
+	if appliedStateStore != nil {
+		report.applied, err = appliedStateStore.Load()
+		if err != nil {
+			glog.Errorf("Failed to load state applied to a10 instances, changes made outside of a10bridge are not detected. error: %s", err)
+			report.applied = nil
+		}
+	}
+
+	//error halting the rollout after the canary instance failed
+	var halted error
+	for _, a10Instance := range rolloutOrder(context) {
+		serviceGroups := selectServiceGroups(&a10Instance, state)
+		if halted != nil {
+			glog.Errorf("Skipping a10 server %s. %s", a10Instance.Name, halted)
+			for _, serviceGroup := range serviceGroups {
+				recordSyncResult(report.results, serviceGroup, &a10Instance, halted)
+			}
+			continue
+		}
+		err := processContext(context, &a10Instance, serviceGroups, state, report)
 		if err != nil {
 			glog.Errorf("Failed to process context for a10 server %s. error: %s", a10Instance.Name, err)
 			result = FailedToProcessA10Instance
+			if a10Instance.Name == context.Canary.Instance {
+				halted = fmt.Errorf("rollout is halted, canary a10 instance %s failed. %s", a10Instance.Name, err)
+				result = CanaryFailed
+			}
+		}
+	}
+
+	if report.applied != nil {
+		err := appliedStateStore.Save(report.applied)
+		if err != nil {
+			glog.Errorf("Failed to persist state applied to a10 instances. error: %s", err)
+		}
+	}
+
+	if len(state.trafficShifts) > 0 {
+		states := make(map[string]*model.TrafficShiftState)
+		for _, shift := range state.trafficShifts {
+			states[shift.config.Name] = shift.state
+		}
+		err := primary.k8sProcessor.SaveTrafficShiftStates(primary.environmentConfig.Namespace, context.TrafficShifts.ConfigMap, states)
+		if err != nil {
+			glog.Errorf("Failed to persist progress of traffic shifts. error: %s", err)
+		}
+	}
+
+	for _, cluster := range clusters {
+		if cluster.state == nil || cluster.k8sProcessor == nil {
+			continue
+		}
+
+		publishLoadBalancerStatuses(cluster.k8sProcessor, cluster.state.loadBalancers, report.loadBalancers)
+
+		if *context.Arguments.CRD {
+			updateResourceStatuses(context, cluster.k8sProcessor, cluster.state.resources, report.results)
+		}
+
+		if *context.Arguments.Events {
+			err := cluster.k8sProcessor.PublishSyncEvents(clusterEvents(report.events, cluster.state.environment.Cluster))
+			if err != nil {
+				glog.Errorf("Failed to publish sync events to kubernetes cluster %s. error: %s", cluster.state.environment.Cluster, err)
+			}
 		}
 	}
 
 	return result
 }
 
-func buildexpectedState(context *config.RunContext) (map[string]*model.ServiceGroup, map[string]*model.Node, error) {
-	var serviceGroups map[string]*model.ServiceGroup
-	nodesMap := make(map[string]*model.Node)
-	k8sProcessor, err := processorBuildK8sProcessor()
-	if err != nil {
-		glog.Errorf("Failed to build kubernetes processor. error: %s", err)
-		return serviceGroups, nodesMap, err
+//buildAppliedStateStore builds store of state applied to a10 instances, nil when the state is not kept or the cluster keeping it is not known
+func buildAppliedStateStore(context *config.RunContext, primary *clusterState) processor.AppliedStateStore {
+	applied := context.AppliedState
+	if len(applied.File) > 0 {
+		return processor.BuildFileAppliedStateStore(applied.File)
+	}
+	if len(applied.ConfigMap) > 0 {
+		if primary.state == nil {
+			glog.Error("Changes made outside of a10bridge are not detected, state of the cluster keeping the applied state is not known")
+			return nil
+		}
+		return processor.BuildConfigMapAppliedStateStore(primary.k8sProcessor, primary.environmentConfig.Namespace, applied.ConfigMap)
+	}
+	return nil
+}
+
+//buildClusterStates builds expected state of every configured cluster, or of the cluster a10bridge runs in when none is configured.
+//Clusters whose state can't be built are kept with nil state as long as state of at least one cluster is known
+func buildClusterStates(context *config.RunContext) ([]*clusterState, error) {
+	if len(context.Clusters) == 0 {
+		k8sProcessor, err := processorBuildK8sProcessor(k8sClientConfig(context.Arguments))
+		if err != nil {
+			glog.Errorf("Failed to build kubernetes processor. error: %s", err)
+			return nil, err
+		}
+		state, err := buildexpectedState(context, context.Environment, k8sProcessor)
+		if err != nil {
+			return nil, err
+		}
+		return []*clusterState{
+			&clusterState{environmentConfig: context.Environment, k8sProcessor: k8sProcessor, state: state},
+		}, nil
+	}
+
+	clusters := make([]*clusterState, 0)
+	known := 0
+	for idx := range context.Clusters {
+		cluster := &context.Clusters[idx]
+		clusterState := &clusterState{environmentConfig: &cluster.Environment}
+		clusters = append(clusters, clusterState)
+
+		k8sProcessor, err := processorBuildClusterK8sProcessor(*cluster)
+		if err != nil {
+			glog.Errorf("Failed to build kubernetes processor for cluster %s, its members are kept. error: %s", cluster, err)
+			continue
+		}
+		state, err := buildexpectedState(context, &cluster.Environment, k8sProcessor)
+		if err != nil {
+			glog.Errorf("Failed to build expected state of cluster %s, its members are kept. error: %s", cluster, err)
+			continue
+		}
+		clusterState.k8sProcessor = k8sProcessor
+		clusterState.state = state
+		known++
 	}
 
-	environment, err := k8sProcessor.BuildEnvironment()
+	if known == 0 {
+		return nil, errors.New("state of none of the clusters could be built")
+	}
+	return clusters, nil
+}
+
+//buildSourceStates loads the static sources, a source which can't be loaded is kept with nil state so that its members are not removed
+func buildSourceStates(context *config.RunContext, clusters []*clusterState) []*clusterState {
+	var environment *model.Environment
+	for _, cluster := range clusters {
+		if cluster.state != nil {
+			environment = cluster.state.environment
+			break
+		}
+	}
+
+	sources := make([]*clusterState, 0, len(context.Sources))
+	for _, source := range processorBuildStateSources(context.Sources) {
+		sourceState := &clusterState{}
+		sources = append(sources, sourceState)
+
+		loaded, err := source.Load()
+		if err != nil {
+			glog.Errorf("Failed to load source %s, its members are kept. error: %s", source.Name(), err)
+			continue
+		}
+		sourceState.state = buildSourceExpectedState(context, environment, loaded)
+	}
+	return sources
+}
+
+//buildSourceExpectedState expected state of a static source, servers and service groups get the configured defaults like the ones of clusters
+func buildSourceExpectedState(context *config.RunContext, environment *model.Environment, source *model.SourceState) *expectedState {
+	state := &expectedState{
+		serviceGroups: make(map[string]map[string]*model.ServiceGroup),
+		nodes:         make(map[string]*model.Node),
+		environment:   environment,
+	}
+
+	for _, node := range source.Nodes {
+		err := context.Servers.Apply(node, environment)
+		if err != nil {
+			glog.Errorf("Failed to apply server defaults to server %s. error: %s", node.Name, err)
+		}
+		state.nodes[node.Name] = node
+	}
+
+	for _, a10Instance := range context.A10Instances {
+		serviceGroups := make(map[string]*model.ServiceGroup)
+		for _, serviceGroup := range source.ServiceGroups {
+			instanceServiceGroup := *serviceGroup
+			context.ServiceGroups.Apply(&instanceServiceGroup)
+			serviceGroups[serviceGroup.Name] = &instanceServiceGroup
+		}
+		state.serviceGroups[a10Instance.Name] = serviceGroups
+	}
+
+	glog.Infof("Static service groups: %s", util.ToJSON(state.serviceGroups))
+
+	return state
+}
+
+//mergeClusterStates joins states of clusters, identically named service groups share members of all clusters and nodes are keyed by a10 server.
+//When state of a cluster is not known, service groups only remove members on servers of the known clusters
+func mergeClusterStates(clusters []*clusterState) *expectedState {
+	merged := &expectedState{
+		serviceGroups: make(map[string]map[string]*model.ServiceGroup),
+		nodes:         make(map[string]*model.Node),
+	}
+
+	for _, cluster := range clusters {
+		state := cluster.state
+		if state == nil {
+			merged.partial = true
+			continue
+		}
+		if merged.environment == nil {
+			merged.environment = state.environment
+		}
+
+		for _, node := range state.nodes {
+			existing, exists := merged.nodes[node.A10Server]
+			if exists {
+				if existing.IPAddress != node.IPAddress || existing.IPv6Address != node.IPv6Address {
+					glog.Errorf("A10 server %s has conflicting addresses, %s declares '%s' '%s' and %s declares '%s' '%s', using the one of %s",
+						node.A10Server, existing.Cluster, existing.IPAddress, existing.IPv6Address, node.Cluster, node.IPAddress, node.IPv6Address, existing.Cluster)
+				} else if existing.Cluster != node.Cluster {
+					glog.Warningf("A10 server %s is claimed by nodes of clusters %s and %s, using the one of %s", node.A10Server, existing.Cluster, node.Cluster, existing.Cluster)
+				}
+				continue
+			}
+			merged.nodes[node.A10Server] = node
+		}
+
+		for instanceName, serviceGroups := range state.serviceGroups {
+			mergedServiceGroups, exists := merged.serviceGroups[instanceName]
+			if !exists {
+				mergedServiceGroups = make(map[string]*model.ServiceGroup)
+				merged.serviceGroups[instanceName] = mergedServiceGroups
+			}
+			for name, serviceGroup := range serviceGroups {
+				existing, exists := mergedServiceGroups[name]
+				if !exists {
+					mergedServiceGroups[name] = serviceGroup
+					continue
+				}
+				mergedServiceGroups[name] = mergeServiceGroups(existing, serviceGroup)
+			}
+		}
+
+		merged.loadBalancers = append(merged.loadBalancers, state.loadBalancers...)
+		merged.resources = append(merged.resources, state.resources...)
+	}
+
+	if merged.partial {
+		ownedServers := make([]string, 0)
+		for serverName := range merged.nodes {
+			ownedServers = append(ownedServers, serverName)
+		}
+		sort.Strings(ownedServers)
+		for _, serviceGroups := range merged.serviceGroups {
+			for _, serviceGroup := range serviceGroups {
+				serviceGroup.OwnedServers = ownedServers
+			}
+		}
+	}
+
+	return merged
+}
+
+//mergeServiceGroups joins ingress controllers of identically named service groups of two clusters, settings of the first one win
+func mergeServiceGroups(serviceGroup *model.ServiceGroup, other *model.ServiceGroup) *model.ServiceGroup {
+	if healthCheckName(serviceGroup) != healthCheckName(other) || serviceGroup.Method != other.Method ||
+		serviceGroup.Protocol != other.Protocol || serviceGroup.MinActiveMembers != other.MinActiveMembers {
+		glog.Warningf("Service group %s has different settings in several clusters, using the ones of the first cluster", serviceGroup.Name)
+	}
+
+	merged := *serviceGroup
+	merged.IngressControllers = make([]*model.IngressController, 0, len(serviceGroup.IngressControllers)+len(other.IngressControllers))
+	merged.IngressControllers = append(merged.IngressControllers, serviceGroup.IngressControllers...)
+	merged.IngressControllers = append(merged.IngressControllers, other.IngressControllers...)
+	if len(serviceGroup.A10Instances) > 0 && len(other.A10Instances) > 0 {
+		merged.A10Instances = append([]string{}, serviceGroup.A10Instances...)
+		for _, instance := range other.A10Instances {
+			if !util.Contains(merged.A10Instances, instance) {
+				merged.A10Instances = append(merged.A10Instances, instance)
+			}
+		}
+	} else {
+		merged.A10Instances = nil
+	}
+	return &merged
+}
+
+func healthCheckName(serviceGroup *model.ServiceGroup) string {
+	if serviceGroup.Health == nil {
+		return ""
+	}
+	return serviceGroup.Health.Name
+}
+
+//clusterEvents picks sync events of objects of the cluster
+func clusterEvents(events []*model.SyncEvent, cluster string) []*model.SyncEvent {
+	clusterEvents := make([]*model.SyncEvent, 0)
+	for _, event := range events {
+		if event.Cluster == cluster {
+			clusterEvents = append(clusterEvents, event)
+		}
+	}
+	return clusterEvents
+}
+
+func buildexpectedState(context *config.RunContext, environmentConfig *config.EnvironmentConfig, k8sProcessor processor.K8sProcessor) (*expectedState, error) {
+	state := &expectedState{
+		serviceGroups: make(map[string]map[string]*model.ServiceGroup),
+		nodes:         make(map[string]*model.Node),
+	}
+
+	environment, err := k8sProcessor.BuildEnvironment(environmentConfig)
 	if err != nil {
 		glog.Errorf("Failed to build environment. error: %s", err)
-		return serviceGroups, nodesMap, err
+		return state, err
 	}
 	glog.Infof("Using environment: %s", util.ToJSON(environment))
+	state.environment = environment
 
 	controllers, err := k8sProcessor.FindIngressControllers()
 	if err != nil {
 		glog.Errorf("Failed to get ingress controllers. error: %s", err)
-		return serviceGroups, nodesMap, err
+		return state, err
 	}
 
 	glog.Infof("Ingress controllers: %s", util.ToJSON(controllers))
@@ -129,32 +502,209 @@ func buildexpectedState(context *config.RunContext) (map[string]*model.ServiceGr
 		nodes, err := k8sProcessor.FindNodes(controller.NodeSelectors)
 		if err != nil {
 			glog.Errorf("Failed to get nodes for controller %s. error: %s", controller.Name, err)
-			return serviceGroups, nodesMap, err
+			return state, err
 		}
 
 		for _, node := range nodes {
 			controller.Nodes = append(controller.Nodes, node)
-			nodesMap[node.Name] = node
+			state.nodes[node.Name] = node
 		}
 	}
 
 	glog.Info("Generating service groups based on ingress controllers")
 
-	serviceGroups = k8sProcessor.BuildServiceGroups(controllers, environment)
-	glog.Infof("Service groups: %s", util.ToJSON(serviceGroups))
+	for _, a10Instance := range context.A10Instances {
+		state.serviceGroups[a10Instance.Name] = k8sProcessor.BuildServiceGroups(controllers, environment, a10Instance.Name)
+	}
+
+	if *context.Arguments.CRD {
+		state.resources, err = buildResourceServiceGroups(k8sProcessor, environment, state)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	if context.LoadBalancer != nil {
+		state.loadBalancers, err = buildLoadBalancers(context.LoadBalancer, k8sProcessor, state)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	for _, serviceGroups := range state.serviceGroups {
+		for _, serviceGroup := range serviceGroups {
+			context.ServiceGroups.Apply(serviceGroup)
+		}
+	}
+
+	if context.Servers.Weight.NeedsControllerPods() {
+		counts, err := k8sProcessor.CountControllerPods(controllers)
+		if err != nil {
+			glog.Errorf("Failed to count ingress controller pods. error: %s", err)
+			return state, err
+		}
+		for name, node := range state.nodes {
+			node.ControllerPods = counts[name]
+		}
+	}
+
+	for _, node := range state.nodes {
+		err = context.Servers.Apply(node, environment)
+		if err != nil {
+			glog.Errorf("Failed to apply server defaults to node %s. error: %s", node.Name, err)
+		}
+	}
+
+	//objects remember their cluster so that merged state can be traced back to it
+	for _, node := range state.nodes {
+		node.Cluster = environment.Cluster
+	}
+	for _, serviceGroups := range state.serviceGroups {
+		for _, serviceGroup := range serviceGroups {
+			for _, controller := range serviceGroup.IngressControllers {
+				controller.Cluster = environment.Cluster
+				for _, node := range controller.Nodes {
+					node.Cluster = environment.Cluster
+				}
+			}
+		}
+	}
+
+	glog.Infof("Service groups: %s", util.ToJSON(state.serviceGroups))
 
-	return serviceGroups, nodesMap, nil
+	return state, nil
 }
 
-func processContext(context *config.RunContext, a10instance *config.A10Instance, serviceGroups map[string]*model.ServiceGroup, nodesMap map[string]*model.Node) error {
+func buildResourceServiceGroups(k8sProcessor processor.K8sProcessor, environment *model.Environment, state *expectedState) ([]*model.A10ServiceGroup, error) {
+	resources, err := k8sProcessor.FindServiceGroupResources()
+	if err != nil {
+		glog.Errorf("Failed to get A10ServiceGroup resources. error: %s", err)
+		return resources, err
+	}
+
+	glog.Info("Generating service groups based on A10ServiceGroup resources")
+	for name, serviceGroup := range k8sProcessor.BuildResourceServiceGroups(resources, environment) {
+		for instanceName, serviceGroups := range state.serviceGroups {
+			if _, exists := serviceGroups[name]; exists {
+				glog.Warningf("Service group %s is declared by both ingress controller annotations and A10ServiceGroup %s/%s for a10 %s, using the resource", name, serviceGroup.Resource.Namespace, serviceGroup.Resource.Name, instanceName)
+			}
+			serviceGroups[name] = serviceGroup
+		}
+		for _, controller := range serviceGroup.IngressControllers {
+			for _, node := range controller.Nodes {
+				state.nodes[node.Name] = node
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+func buildLoadBalancers(loadBalancerConfig *config.LoadBalancerConfig, k8sProcessor processor.K8sProcessor, state *expectedState) ([]*model.LoadBalancer, error) {
+	loadBalancers, err := k8sProcessor.FindLoadBalancers()
+	if err != nil {
+		glog.Errorf("Failed to get services of type LoadBalancer. error: %s", err)
+		return loadBalancers, err
+	}
+
+	vips, err := loadBalancerConfig.VIPs()
+	if err != nil {
+		glog.Errorf("Failed to build vip pool. error: %s", err)
+		return loadBalancers, err
+	}
+	k8sProcessor.AssignVIPs(loadBalancers, vips)
+
+	nodes, err := k8sProcessor.FindNodes(map[string]string{})
+	if err != nil {
+		glog.Errorf("Failed to get nodes for load balancers. error: %s", err)
+		return loadBalancers, err
+	}
+	for _, node := range nodes {
+		state.nodes[node.Name] = node
+	}
+
+	glog.Info("Generating service groups based on services of type LoadBalancer")
+	for name, serviceGroup := range k8sProcessor.BuildLoadBalancerServiceGroups(loadBalancers, nodes, loadBalancerConfig.NamePrefix) {
+		for _, serviceGroups := range state.serviceGroups {
+			serviceGroups[name] = serviceGroup
+		}
+	}
+
+	return loadBalancers, nil
+}
+
+//applyTrafficShifts resumes traffic shifts from their persisted progress, moves them forward and splits traffic of their service groups accordingly
+func applyTrafficShifts(context *config.RunContext, cluster *clusterState, state *expectedState) ([]*trafficShift, error) {
+	states, err := cluster.k8sProcessor.GetTrafficShiftStates(cluster.environmentConfig.Namespace, context.TrafficShifts.ConfigMap)
+	if err != nil {
+		glog.Errorf("Failed to read progress of traffic shifts. error: %s", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	shifts := make([]*trafficShift, 0)
+	for _, shiftConfig := range context.TrafficShifts.Shifts {
+		shift := &trafficShift{
+			config: shiftConfig,
+			state:  shiftConfig.Advance(states[shiftConfig.Name], now),
+		}
+		percentage := shiftConfig.Percentage(shift.state)
+		glog.Infof("Traffic shift %s is %s, sending %d%% of service group %s to the target", shiftConfig.Name, shift.state.Status, percentage, shiftConfig.ServiceGroup)
+
+		serviceGroups := make([]*model.ServiceGroup, 0)
+		for _, instanceServiceGroups := range state.serviceGroups {
+			if serviceGroup, exists := instanceServiceGroups[shiftConfig.ServiceGroup]; exists {
+				serviceGroups = append(serviceGroups, serviceGroup)
+			}
+		}
+		shiftConfig.Apply(percentage, serviceGroups, state.nodes)
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
+
+//rolloutOrder a10 instances in the order changes roll out to them, the canary instance goes first
+func rolloutOrder(context *config.RunContext) config.A10Instances {
+	if !context.Canary.Enabled() {
+		return context.A10Instances
+	}
+	instances := make(config.A10Instances, 0, len(context.A10Instances))
+	for _, instance := range context.A10Instances {
+		if instance.Name == context.Canary.Instance {
+			instances = append(instances, instance)
+		}
+	}
+	for _, instance := range context.A10Instances {
+		if instance.Name != context.Canary.Instance {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+//selectServiceGroups picks service groups which should be synced into the a10 instance
+func selectServiceGroups(a10instance *config.A10Instance, state *expectedState) model.ServiceGroups {
+	environment := state.environment.Fields()
+	serviceGroups := make(model.ServiceGroups, 0)
+	for _, serviceGroup := range state.serviceGroups[a10instance.Name] {
+		if !serviceGroup.TargetsInstance(a10instance.Name) {
+			glog.Infof("Service group %s is not targeting a10 load balancer %s, skipping", serviceGroup.Name, a10instance.Name)
+			continue
+		}
+		if a10instance.Selector != nil && !a10instance.Selector.Matches(serviceGroup.Name, serviceGroup.Labels(), environment) {
+			glog.Infof("Service group %s doesn't match selector of a10 load balancer %s, skipping", serviceGroup.Name, a10instance.Name)
+			continue
+		}
+		serviceGroups = append(serviceGroups, serviceGroup)
+	}
+	return serviceGroups
+}
+
+func processContext(context *config.RunContext, a10instance *config.A10Instance, serviceGroupSlice model.ServiceGroups, state *expectedState, report *syncReport) error {
 	nodesSlice := make(model.Nodes, 0)
-	for _, node := range nodesMap {
+	for _, node := range state.nodes {
 		nodesSlice = append(nodesSlice, node)
 	}
-	serviceGroupSlice := make(model.ServiceGroups, 0)
-	for _, serviceGroup := range serviceGroups {
-		serviceGroupSlice = append(serviceGroupSlice, serviceGroup)
-	}
 	if *context.Arguments.Sort {
 		sort.Sort(nodesSlice)
 		sort.Sort(serviceGroupSlice)
@@ -163,34 +713,280 @@ func processContext(context *config.RunContext, a10instance *config.A10Instance,
 	glog.Infof("Processing context for a10 load balancer %s", a10instance.Name)
 	processors, err := processorBuildA10Processors(a10instance)
 	if err != nil {
+		for _, serviceGroup := range serviceGroupSlice {
+			recordSyncResult(report.results, serviceGroup, a10instance, err)
+		}
 		return err
 	}
 	defer processors.Destroy()
+	if report.applied != nil {
+		processors.Applied.Start(report.applied[a10instance.Name], context.AppliedState.KeepManualChanges)
+	}
+	processors.Removals.Start(context.RemovalLimits, *context.Arguments.AllowMassRemoval, report.removedMembers)
+	canary := a10instance.Name == context.Canary.Instance
+	var fingerprint string
+	if canary {
+		fingerprint = desiredStateFingerprint(nodesSlice, serviceGroupSlice)
+		if fingerprint == processors.Applied.HaltedRollout() {
+			//retrying would apply the same change and revert it again, the rollout stays halted until the desired state changes
+			err := errors.New("desired state didn't change since its rollout failed canary verification and was reverted")
+			glog.Warningf("Not processing canary a10 instance %s. %s", a10instance.Name, err)
+			for _, serviceGroup := range serviceGroupSlice {
+				recordSyncResult(report.results, serviceGroup, a10instance, err)
+			}
+			return err
+		}
+		processors.Canary.Start()
+	}
+
 	glog.Info("Making sure servers in a10 are in sync with ingress nodes")
 	failedNodeNames := make([]string, 0)
+	failedServers := make([]string, 0)
 	for _, node := range nodesSlice {
 		err := processors.Node.ProcessNode(node)
 		if err != nil {
 			glog.Errorf("Failed to process node %s. error: %s", node.Name, err)
 			failedNodeNames = append(failedNodeNames, node.Name)
+			failedServers = append(failedServers, node.A10Server)
+			if context.Servers.OnFailure == config.ServerOnFailureKeep {
+				processors.Recorder.MembershipKept(node)
+			}
 		}
 	}
 
+	//members of failed nodes are left out of service groups and removed, unless the policy keeps them as they are
+	var keptServers []string
+	if context.Servers.OnFailure == config.ServerOnFailureKeep && len(failedServers) > 0 {
+		glog.Warningf("Keeping service group members of nodes which failed to sync: %s", strings.Join(failedNodeNames, ", "))
+		keptServers = failedServers
+	}
+	for _, serviceGroup := range serviceGroupSlice {
+		serviceGroup.KeptServers = keptServers
+	}
+
 	glog.Info("Processing service groups")
+	failedServiceGroupNames := make([]string, 0)
 	for _, serviceGroup := range serviceGroupSlice {
-		err := processors.HealthCheck.ProcessHealthCheck(serviceGroup.Health)
-		if err != nil {
-			glog.Errorf("Failed to process health check %s, error: %s", serviceGroup.Name, err)
-			continue
+		if serviceGroup.Health != nil {
+			err := processors.HealthCheck.ProcessHealthCheck(serviceGroup.Health, serviceGroup)
+			if err != nil {
+				glog.Errorf("Failed to process health check %s, error: %s", serviceGroup.Name, err)
+				failedServiceGroupNames = append(failedServiceGroupNames, serviceGroup.Name)
+				processors.Recorder.ServiceGroupFailed(serviceGroup, err)
+				recordSyncResult(report.results, serviceGroup, a10instance, err)
+				continue
+			}
 		}
 
-		err = processors.ServiceGroup.ProcessServiceGroup(serviceGroup, failedNodeNames)
+		err := processors.ServiceGroup.ProcessServiceGroup(serviceGroup, failedServers)
 		if err != nil {
 			glog.Errorf("Failed to process service group %s, error: %s", serviceGroup.Name, err)
+			failedServiceGroupNames = append(failedServiceGroupNames, serviceGroup.Name)
+		}
+		recordSyncResult(report.results, serviceGroup, a10instance, err)
+	}
+
+	var canaryErr error
+	if canary {
+		synced := make([]string, 0)
+		for _, serviceGroup := range serviceGroupSlice {
+			if !util.Contains(failedServiceGroupNames, serviceGroup.Name) {
+				synced = append(synced, serviceGroup.Name)
+			}
+		}
+		canaryErr = verifyCanary(context.Canary, a10instance, processors, synced)
+		if canaryErr != nil && context.Canary.Revert && processors.Canary.Changed() {
+			processors.Applied.RolloutHalted(fingerprint)
+		} else {
+			processors.Applied.RolloutHalted("")
+		}
+	}
+
+	for _, shift := range state.trafficShifts {
+		if !shift.state.Running() || !containsServiceGroup(serviceGroupSlice, shift.config.ServiceGroup) {
+			continue
+		}
+		down, err := processors.ServiceGroup.CountDownMembers(shift.config.ServiceGroup)
+		if err != nil {
+			glog.Errorf("Failed to check members of service group %s for traffic shift %s, error: %s", shift.config.ServiceGroup, shift.config.Name, err)
 			continue
 		}
+		shift.config.CheckDownMembers(shift.state, a10instance.Name, down)
+	}
+
+	if context.LoadBalancer != nil && canaryErr != nil {
+		glog.Warningf("Not processing load balancers of canary a10 instance %s, it failed verification", a10instance.Name)
+	} else if context.LoadBalancer != nil {
+		//virtual servers can't point to service groups which were not selected for this instance
+		for name := range state.serviceGroups[a10instance.Name] {
+			if !containsServiceGroup(serviceGroupSlice, name) {
+				failedServiceGroupNames = append(failedServiceGroupNames, name)
+			}
+		}
+
+		glog.Info("Processing load balancers")
+		for _, loadBalancer := range state.loadBalancers {
+			err := processors.LoadBalancer.ProcessLoadBalancer(loadBalancer, failedServiceGroupNames)
+			if err != nil {
+				glog.Errorf("Failed to process load balancer %s/%s, error: %s", loadBalancer.Namespace, loadBalancer.Name, err)
+			}
+			synced, processed := report.loadBalancers[loadBalancer]
+			report.loadBalancers[loadBalancer] = err == nil && (synced || !processed)
+		}
+
+		if state.partial {
+			glog.Warning("Not removing stale load balancers, state of some clusters is not known")
+		} else {
+			err := processors.LoadBalancer.DeleteStaleLoadBalancers(state.loadBalancers, context.LoadBalancer.NamePrefix)
+			if err != nil {
+				glog.Errorf("Failed to remove stale load balancers, error: %s", err)
+			}
+		}
+	}
+
+	report.events = append(report.events, processors.Recorder.Events()...)
+	report.removedMembers += processors.Removals.Removed()
+	if applied := processors.Applied.State(); applied != nil {
+		report.applied[a10instance.Name] = applied
 	}
 
 	glog.Infof("Done processing context for a10 load balancer %s", a10instance.Name)
+	return canaryErr
+}
+
+//desiredStateFingerprint hashes nodes and service groups synced into the canary a10 instance, independently of their order
+func desiredStateFingerprint(nodes model.Nodes, serviceGroups model.ServiceGroups) string {
+	sortedNodes := append(model.Nodes{}, nodes...)
+	sort.Sort(sortedNodes)
+	sortedServiceGroups := append(model.ServiceGroups{}, serviceGroups...)
+	sort.Sort(sortedServiceGroups)
+	hash := fnv.New64a()
+	hash.Write([]byte(util.ToJSON(sortedNodes)))
+	hash.Write([]byte(util.ToJSON(sortedServiceGroups)))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+//verifyCanary waits for health monitors of the canary a10 instance and checks members of service groups changed in it are up,
+//changed servers and health monitors can affect any service group so all synced ones are checked then.
+//The changes are reverted when the check fails and the canary is configured to revert them
+func verifyCanary(canary config.CanaryConfig, a10instance *config.A10Instance, processors *processor.A10Processors, synced []string) error {
+	if !processors.Canary.Changed() {
+		//changes halted by previous runs stay in the canary unless reverted, so the synced service groups are checked without waiting
+		glog.Infof("Nothing changed in canary a10 instance %s, checking its members", a10instance.Name)
+		return countCanaryDownMembers(canary, synced, processors)
+	}
+	verified := processors.Canary.ServiceGroups()
+	if len(processors.Canary.Servers()) > 0 || len(processors.Canary.HealthChecks()) > 0 {
+		for _, name := range synced {
+			if !util.Contains(verified, name) {
+				verified = append(verified, name)
+			}
+		}
+	}
+	glog.Infof("Waiting %d seconds before verifying service groups %s of canary a10 instance %s", canary.Wait, strings.Join(verified, ", "), a10instance.Name)
+	timeSleep(time.Duration(canary.Wait) * time.Second)
+
+	err := countCanaryDownMembers(canary, verified, processors)
+	if err == nil {
+		glog.Infof("Canary a10 instance %s is verified", a10instance.Name)
+		return nil
+	}
+	glog.Errorf("Canary a10 instance %s failed verification. error: %s", a10instance.Name, err)
+	if canary.Revert {
+		revertCanary(a10instance, processors)
+	}
+	return err
+}
+
+//revertCanary reverts service groups first, so the servers and health monitors are no longer used by new members or settings when they are reverted
+func revertCanary(a10instance *config.A10Instance, processors *processor.A10Processors) {
+	for _, name := range processors.Canary.ServiceGroups() {
+		err := processors.ServiceGroup.RevertServiceGroup(name, processors.Canary.PreviousServiceGroup(name))
+		if err != nil {
+			glog.Errorf("Failed to revert service group %s in canary a10 instance %s. error: %s", name, a10instance.Name, err)
+		}
+	}
+	for _, name := range processors.Canary.HealthChecks() {
+		err := processors.HealthCheck.RevertHealthCheck(name, processors.Canary.PreviousHealthCheck(name))
+		if err != nil {
+			glog.Errorf("Failed to revert health monitor %s in canary a10 instance %s. error: %s", name, a10instance.Name, err)
+		}
+	}
+	for _, name := range processors.Canary.Servers() {
+		err := processors.Node.RevertServer(name, processors.Canary.PreviousServer(name))
+		if err != nil {
+			glog.Errorf("Failed to revert server %s in canary a10 instance %s. error: %s", name, a10instance.Name, err)
+		}
+	}
+}
+
+func countCanaryDownMembers(canary config.CanaryConfig, serviceGroupNames []string, processors *processor.A10Processors) error {
+	down := 0
+	for _, name := range serviceGroupNames {
+		count, err := processors.ServiceGroup.CountDownMembers(name)
+		if err != nil {
+			return fmt.Errorf("failed to check members of service group %s. %s", name, err)
+		}
+		down += count
+	}
+	if down > canary.MaxDownMembers {
+		return fmt.Errorf("%d members of service groups %s are down, at most %d are allowed", down, strings.Join(serviceGroupNames, ", "), canary.MaxDownMembers)
+	}
 	return nil
 }
+
+func containsServiceGroup(serviceGroups model.ServiceGroups, name string) bool {
+	for _, serviceGroup := range serviceGroups {
+		if serviceGroup.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+//recordSyncResult remembers the outcome of syncing a service group declared by a custom resource
+func recordSyncResult(syncResults map[string][]*model.SyncResult, serviceGroup *model.ServiceGroup, a10instance *config.A10Instance, err error) {
+	if serviceGroup.Resource == nil {
+		return
+	}
+	result := &model.SyncResult{Instance: a10instance.Name}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	syncResults[serviceGroup.Name] = append(syncResults[serviceGroup.Name], result)
+}
+
+//publishLoadBalancerStatuses publishes vips of load balancers only once their virtual servers exist, so services never advertise a vip which doesn't work
+func publishLoadBalancerStatuses(k8sProcessor processor.K8sProcessor, loadBalancers []*model.LoadBalancer, synced map[*model.LoadBalancer]bool) {
+	for _, loadBalancer := range loadBalancers {
+		if !synced[loadBalancer] {
+			continue
+		}
+		err := k8sProcessor.PublishLoadBalancerStatus(loadBalancer)
+		if err != nil {
+			glog.Errorf("Failed to update status of service %s/%s. error: %s", loadBalancer.Namespace, loadBalancer.Name, err)
+		}
+	}
+}
+
+func updateResourceStatuses(context *config.RunContext, k8sProcessor processor.K8sProcessor, resources []*model.A10ServiceGroup, syncResults map[string][]*model.SyncResult) {
+	for _, resource := range resources {
+		results := make([]*model.SyncResult, 0)
+		if len(resource.ServiceGroupName) > 0 {
+			results = append(results, syncResults[resource.ServiceGroupName]...)
+		}
+		for _, instanceName := range resource.A10Instances {
+			if !context.A10Instances.Contains(instanceName) {
+				results = append(results, &model.SyncResult{
+					Instance: instanceName,
+					Error:    "a10 instance is not configured",
+				})
+			}
+		}
+
+		err := k8sProcessor.UpdateServiceGroupResourceStatus(resource, results)
+		if err != nil {
+			glog.Errorf("Failed to update status of A10ServiceGroup %s/%s. error: %s", resource.Namespace, resource.Name, err)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -126,7 +128,7 @@ func (suite MainFunctionalTestSuite) TestV2Protocol() {
 		Query("format", "json").
 		Query("method", "slb.server.update").
 		Query("session_id", sessionId).
-		Body(v2ServerRequest(expectedNode1)).
+		Body(v2ServerUpdateRequest(expectedNode1)).
 		Response().
 		Body(v2OkResponse(), "application/json")
 
@@ -194,7 +196,7 @@ func (suite MainFunctionalTestSuite) TestV2Protocol() {
 		Query("format", "json").
 		Query("method", "slb.hm.update").
 		Query("session_id", sessionId).
-		Body(v2HealthMonitorRequest(expectedMonitor1)).
+		Body(v2HealthMonitorUpdateRequest(expectedMonitor1)).
 		Response().
 		Body(v2OkResponse(), "application/json")
 
@@ -251,7 +253,7 @@ func (suite MainFunctionalTestSuite) TestV2Protocol() {
 		Query("format", "json").
 		Query("method", "slb.service_group.update").
 		Query("session_id", sessionId).
-		Body(v2ServiceGroupRequest(expectedSvcGroup1)).
+		Body(v2ServiceGroupUpdateRequest(expectedSvcGroup1)).
 		Response().
 		Body(v2OkResponse(), "application/json")
 
@@ -440,9 +442,10 @@ func (suite MainFunctionalTestSuite) TestV3Protocol() {
 		Weight:    "5",
 	}
 	expectedNode1 := model.Node{
-		A10Server: "node1",
-		IPAddress: "10.10.10.1",
-		Weight:    "1",
+		A10Server:   "node1",
+		Description: "k8s dc-kube-test node node1",
+		IPAddress:   "10.10.10.1",
+		Weight:      "1",
 	}
 
 	suite.testServer.AddRequest().
@@ -453,17 +456,18 @@ func (suite MainFunctionalTestSuite) TestV3Protocol() {
 		Body(v3ServerResponse(existingNode1), "application/json")
 
 	suite.testServer.AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/slb/server/"+expectedNode1.A10Server).
 		Header("Authorization", "A10 "+sessionId).
-		Body(v3ServerRequest(expectedNode1)).
+		Body(v3ServerUpdateRequest(expectedNode1)).
 		Response().
 		Body(v3ServerResponse(expectedNode1), "application/json")
 
 	expectedNode4 := model.Node{
-		A10Server: "node4",
-		IPAddress: "10.10.10.4",
-		Weight:    "1",
+		A10Server:   "node4",
+		Description: "k8s dc-kube-test node node4",
+		IPAddress:   "10.10.10.4",
+		Weight:      "1",
 	}
 
 	suite.testServer.AddRequest().
@@ -511,10 +515,10 @@ func (suite MainFunctionalTestSuite) TestV3Protocol() {
 		Body(v3HealthMonitorResponse(existingMonitor1), "application/json")
 
 	suite.testServer.AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/health/monitor/"+expectedMonitor1.Name).
 		Header("Authorization", "A10 "+sessionId).
-		Body(v3HealthMonitorRequest(expectedMonitor1)).
+		Body(v3HealthMonitorUpdateRequest(expectedMonitor1)).
 		Response().
 		Body(v3HealthMonitorResponse(expectedMonitor1), "application/json")
 
@@ -564,10 +568,10 @@ func (suite MainFunctionalTestSuite) TestV3Protocol() {
 		Body(v3ServiceGroupResponse(existingSvcGroup1), "application/json")
 
 	suite.testServer.AddRequest().
-		Method(http.MethodPut).
+		Method(http.MethodPost).
 		Path("/axapi/v3/slb/service-group/"+expectedSvcGroup1.Name).
 		Header("Authorization", "A10 "+sessionId).
-		Body(v3ServiceGroupRequest(expectedSvcGroup1)).
+		Body(v3ServiceGroupUpdateRequest(expectedSvcGroup1)).
 		Response().
 		Body(v3ServiceGroupResponse(expectedSvcGroup1), "application/json")
 
@@ -866,6 +870,16 @@ func v2ServerRequest(node model.Node) string {
 	  }`
 }
 
+func v2ServerUpdateRequest(node model.Node) string {
+	return `{
+		"server": {
+		  "name": "` + node.A10Server + `",
+		  "host": "` + node.IPAddress + `",
+		  "weight": ` + node.Weight + `
+		}
+	  }`
+}
+
 func v2ServerResponse(node model.Node) string {
 	return `{"server":{"name":"` + node.A10Server + `","host":"` + node.IPAddress + `","gslb_external_address":"0.0.0.0","weight":` + node.Weight + `,"health_monitor":"(default)","status":1,"conn_limit":8000000,"conn_limit_log":1,"conn_resume":0,"stats_data":1,"extended_stats":0,"slow_start":0,"spoofing_cache":0,"template":"default","port_list":[{"port_num":81,"protocol":2,"status":1,"weight":1,"no_ssl":0,"conn_limit":8000000,"conn_limit_log":0,"conn_resume":0,"template":"default","stats_data":1,"health_monitor":"(default)","extended_stats":0},{"port_num":90,"protocol":2,"status":1,"weight":1,"no_ssl":0,"conn_limit":8000000,"conn_limit_log":1,"conn_resume":0,"template":"default","stats_data":1,"health_monitor":"(default)","extended_stats":0}]}}`
 }
@@ -896,6 +910,25 @@ func v2HealthMonitorRequest(monitor model.HealthCheck) string {
 	  }`
 }
 
+func v2HealthMonitorUpdateRequest(monitor model.HealthCheck) string {
+	return `{
+		"health_monitor": {
+		  "name": "` + monitor.Name + `",
+		  "retry": ` + strconv.Itoa(monitor.RetryCount) + `,
+		  "consec_pass_reqd": ` + strconv.Itoa(monitor.RequiredConsecutivePasses) + `,
+		  "interval": ` + strconv.Itoa(monitor.Interval) + `,
+		  "timeout": ` + strconv.Itoa(monitor.Timeout) + `,
+		  "override_port": ` + strconv.Itoa(monitor.Port) + `,
+		  "type": 3,
+		  "http": {
+			"port": ` + strconv.Itoa(monitor.Port) + `,
+			"url": "GET ` + monitor.Endpoint + `",
+			"expect_code": "` + monitor.ExpectCode + `"
+		  }
+		}
+	  }`
+}
+
 func v2HealthMonitorResponse(monitor model.HealthCheck) string {
 	return `{"health_monitor":{"name":"` + monitor.Name +
 		`","retry":` + strconv.Itoa(monitor.RetryCount) + `,"consec_pass_reqd":` + strconv.Itoa(monitor.RequiredConsecutivePasses) +
@@ -931,6 +964,16 @@ func v2ServiceGroupRequest(serviceGroup model.ServiceGroup) string {
 	  }`
 }
 
+func v2ServiceGroupUpdateRequest(serviceGroup model.ServiceGroup) string {
+	return `{
+		"service_group": {
+		  "name": "` + serviceGroup.Name + `",
+		  "health_monitor": "` + serviceGroup.Health.Name + `",
+		  "protocol": 2
+		}
+	  }`
+}
+
 func v2ServiceGroupResponse(serviceGroup model.ServiceGroup) string {
 	responseBody := `{"service_group":{"name":"` + serviceGroup.Name +
 		`","protocol":2,"lb_method":0,"health_monitor":"` + serviceGroup.Health.Name +
@@ -992,7 +1035,7 @@ func v3ServerRequest(node model.Node) string {
 	return `{
 		"server": {
 		  "name": "` + node.A10Server + `",
-		  "host": "` + node.IPAddress + `",
+		  "host": "` + node.IPAddress + `",` + v3ServerDescription(node) + `
 		  "action": "enable",
 		  "weight": ` + node.Weight + `,
 		  "conn-limit": 8000000
@@ -1000,11 +1043,29 @@ func v3ServerRequest(node model.Node) string {
 	  }`
 }
 
+func v3ServerDescription(node model.Node) string {
+	if len(node.Description) == 0 {
+		return ""
+	}
+	return `
+		  "description": "` + node.Description + `",`
+}
+
+func v3ServerUpdateRequest(node model.Node) string {
+	return `{
+		"server": {
+		  "name": "` + node.A10Server + `",
+		  "host": "` + node.IPAddress + `",` + v3ServerDescription(node) + `
+		  "weight": ` + node.Weight + `
+		}
+	  }`
+}
+
 func v3ServerResponse(node model.Node) string {
 	return `{
 		"server": {
 		  "name": "` + node.A10Server + `",
-		  "host": "` + node.IPAddress + `",
+		  "host": "` + node.IPAddress + `",` + v3ServerDescription(node) + `
 		  "action": "enable",
 		  "weight": ` + node.Weight + `,
 		  "conn-limit": 8000000
@@ -1040,6 +1101,30 @@ func v3HealthMonitorRequest(monitor model.HealthCheck) string {
 	  }`
 }
 
+func v3HealthMonitorUpdateRequest(monitor model.HealthCheck) string {
+	return `{
+		"monitor": {
+		  "name": "` + monitor.Name + `",
+		  "retry": ` + strconv.Itoa(monitor.RetryCount) + `,
+		  "up-retry": ` + strconv.Itoa(monitor.RequiredConsecutivePasses) + `,
+		  "interval": ` + strconv.Itoa(monitor.Interval) + `,
+		  "timeout": ` + strconv.Itoa(monitor.Timeout) + `,
+		  "override-port": ` + strconv.Itoa(monitor.Port) + `,
+		  "method":{
+			"http": {
+			  "http":1,
+			  "http-port": ` + strconv.Itoa(monitor.Port) + `,
+			  "http-url":1,
+			  "http-expect":1,
+			  "http-response-code": "` + monitor.ExpectCode + `",
+			  "url-type":"GET",
+			  "url-path":"` + monitor.Endpoint + `"
+			}
+		  }
+		}
+	  }`
+}
+
 func v3HealthMonitorResponse(monitor model.HealthCheck) string {
 	return `{
 		"monitor": {
@@ -1095,6 +1180,16 @@ func v3ServiceGroupRequest(serviceGroup model.ServiceGroup) string {
 	return requestBody + ` ] } }`
 }
 
+func v3ServiceGroupUpdateRequest(serviceGroup model.ServiceGroup) string {
+	return `{
+		"service-group": {
+		  "name": "` + serviceGroup.Name + `",
+		  "health-check": "` + serviceGroup.Health.Name + `",
+		  "protocol": "tcp"
+		}
+	  }`
+}
+
 func v3ServiceGroupResponse(serviceGroup model.ServiceGroup) string {
 	responseBody := `{
 		"service-group": {
@@ -1174,8 +1269,8 @@ func (suite *MainTestSuite) Test() {
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
 	k8sProcessor := new(mocks.K8sProcessor)
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
-		defer suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		defer suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 			return nil, errors.New("failure")
 		})
 		return k8sProcessor, nil
@@ -1195,17 +1290,17 @@ func (suite *MainTestSuite) Test() {
 	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
 
 	environment := environment()
-	k8sProcessor.On("BuildEnvironment").Return(environment, nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	nodes := nodes()
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
 	svcGroupName := "svcGroup"
 	serviceGroups := serviceGroups(svcGroupName)
-	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment).Return(serviceGroups)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
 	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
 	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
 	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
 
 	exitCode := mainInternal()
@@ -1228,7 +1323,7 @@ func (suite *MainTestSuite) Test_buildK8sProcessorFails() {
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return nil, errors.New("failure")
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
@@ -1245,12 +1340,12 @@ func (suite *MainTestSuite) Test_buildEnvironmentFails() {
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
 
-	k8sProcessor.On("BuildEnvironment").Return(nil, errors.New("failure"))
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(nil, errors.New("failure"))
 
 	exitCode := mainInternal()
 	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
@@ -1264,12 +1359,12 @@ func (suite *MainTestSuite) Test_findIngressControllersFails() {
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
 
-	k8sProcessor.On("BuildEnvironment").Return(environment(), nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment(), nil)
 	k8sProcessor.On("FindIngressControllers").Return(nil, errors.New("failure"))
 
 	exitCode := mainInternal()
@@ -1284,12 +1379,12 @@ func (suite *MainTestSuite) Test_findNodesFails() {
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
 
-	k8sProcessor.On("BuildEnvironment").Return(environment(), nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment(), nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nil, errors.New("failure"))
@@ -1306,7 +1401,7 @@ func (suite *MainTestSuite) Test_findBuildA10ProcessorsFails() {
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
@@ -1317,11 +1412,11 @@ func (suite *MainTestSuite) Test_findBuildA10ProcessorsFails() {
 	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
 
 	environment := environment()
-	k8sProcessor.On("BuildEnvironment").Return(environment, nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes(), nil)
-	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment).Return(serviceGroups())
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups())
 
 	exitCode := mainInternal()
 	suite.Assert().Equal(FailedToProcessA10Instance, exitCode)
@@ -1335,7 +1430,7 @@ func (suite *MainTestSuite) Test_processNodeFails() {
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
@@ -1353,34 +1448,36 @@ func (suite *MainTestSuite) Test_processNodeFails() {
 	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
 
 	environment := environment()
-	k8sProcessor.On("BuildEnvironment").Return(environment, nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	nodes := nodes()
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
 	svcGroupName := "svcGroup"
 	serviceGroups := serviceGroups(svcGroupName)
-	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment).Return(serviceGroups)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
 	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
 
 	nodeProcessor.On("ProcessNode", nodes[1]).Return(errors.New("failure"))
 
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health).Return(nil)
-	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{nodes[1].Name}).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{nodes[1].A10Server}).Return(nil)
 
 	exitCode := mainInternal()
 	suite.Assert().Equal(Normal, exitCode)
 }
 
-func (suite *MainTestSuite) Test_processHealthCheckFails() {
+func (suite *MainTestSuite) Test_processNodeFailsKeepsMembership() {
+	runContext := runContext()
+	runContext.Servers.OnFailure = config.ServerOnFailureKeep
 	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
-		return runContext(), nil
+		return runContext, nil
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
 	k8sProcessor := new(mocks.K8sProcessor)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
@@ -1388,46 +1485,51 @@ func (suite *MainTestSuite) Test_processHealthCheckFails() {
 	healthCheckProcessor := new(mocks.HealthCheckProcessor)
 	nodeProcessor := new(mocks.NodeProcessor)
 	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	recorder := processor.NewSyncRecorder("lb")
 	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
 		return &processor.A10Processors{
 			HealthCheck:  healthCheckProcessor,
 			Node:         nodeProcessor,
 			ServiceGroup: serviceGroupsProcessor,
+			Recorder:     recorder,
 		}, nil
 	})
 	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
 
 	environment := environment()
-	k8sProcessor.On("BuildEnvironment").Return(environment, nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	nodes := nodes()
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
-	svcGroupNameFail := "failingHealthCheck"
 	svcGroupName := "svcGroup"
-	serviceGroups := serviceGroups(svcGroupNameFail, svcGroupName)
-	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment).Return(serviceGroups)
+	serviceGroups := serviceGroups(svcGroupName)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
 	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
-	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
-
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupNameFail].Health).Return(errors.New("failure"))
-
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health).Return(nil)
-	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[1]).Return(errors.New("failure"))
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.MatchedBy(func(serviceGroup *model.ServiceGroup) bool {
+		return serviceGroup.Name == svcGroupName && reflect.DeepEqual(serviceGroup.KeptServers, []string{nodes[1].A10Server})
+	}), []string{nodes[1].A10Server}).Return(nil)
 
 	exitCode := mainInternal()
 	suite.Assert().Equal(Normal, exitCode)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+	suite.Assert().Len(recorder.Events(), 1)
+	suite.Assert().Equal(model.ReasonMembershipKept, recorder.Events()[0].Reason)
+	suite.Assert().Equal(nodes[1].Name, recorder.Events()[0].Name)
 }
 
-func (suite *MainTestSuite) Test_processServiceGroupFails() {
+func (suite *MainTestSuite) Test_controllerPodWeights() {
+	runContext := runContext()
+	runContext.Servers.Weight = config.WeightConfig{Strategy: config.WeightStrategyPods, Min: 1, Max: 100}
 	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
-		return runContext(), nil
+		return runContext, nil
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
 	k8sProcessor := new(mocks.K8sProcessor)
-
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
 		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
@@ -1445,146 +1547,1269 @@ func (suite *MainTestSuite) Test_processServiceGroupFails() {
 	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
 
 	environment := environment()
-	k8sProcessor.On("BuildEnvironment").Return(environment, nil)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
 	ingressControllers := ingressControllers()
 	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
 	nodes := nodes()
+	nodes[0].Weight = ""
+	nodes[1].Name = "node2"
+	nodes[1].Weight = ""
 	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
-	svcGroupNameFail := "failingGroup"
+	k8sProcessor.On("CountControllerPods", ingressControllers).Return(map[string]int{"node": 2}, nil)
 	svcGroupName := "svcGroup"
-	serviceGroups := serviceGroups(svcGroupNameFail, svcGroupName)
-	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment).Return(serviceGroups)
+	serviceGroups := serviceGroups(svcGroupName)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
 	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
 	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupNameFail].Health).Return(nil)
-	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health).Return(nil)
-
-	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupNameFail], []string{}).Return(errors.New("failure"))
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
 	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
 
 	exitCode := mainInternal()
 	suite.Assert().Equal(Normal, exitCode)
+	suite.Assert().Equal("2", nodes[0].Weight)
+	suite.Assert().Equal(2, nodes[0].ControllerPods)
+	suite.Assert().Equal("1", nodes[1].Weight)
+	k8sProcessor.AssertExpectations(suite.T())
 }
 
-func (suite *MainTestSuite) Test_executionTimesOut() {
+func (suite *MainTestSuite) Test_trafficShift() {
 	runContext := runContext()
-	runContext.Arguments.Interval = intPtr(1)
+	runContext.TrafficShifts = config.TrafficShiftsConfig{
+		ConfigMap: "shifts",
+		Shifts: []config.TrafficShiftConfig{
+			config.TrafficShiftConfig{
+				Name:         "migrate",
+				ServiceGroup: "svcGroup",
+				Source:       config.ShiftSide{Controller: "blue"},
+				Target:       config.ShiftSide{Controller: "green"},
+				Steps:        []int{20, 100},
+				Hold:         10,
+				OnFailure:    config.ShiftOnFailureStop,
+			},
+		},
+	}
 	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
 		return runContext, nil
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
-		time.Sleep(time.Second * 5)
-		return nil, nil
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
 	})
 	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
 
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := []*model.IngressController{
+		&model.IngressController{Name: "blue", NodeSelectors: map[string]string{"side": "blue"}},
+		&model.IngressController{Name: "green", NodeSelectors: map[string]string{"side": "green"}},
+	}
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()
+	nodes[1].Name = "node2"
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes[0:1], nil)
+	k8sProcessor.On("FindNodes", ingressControllers[1].NodeSelectors).Return(nodes[1:2], nil)
+	svcGroupName := "svcGroup"
+	serviceGroups := serviceGroups(svcGroupName)
+	serviceGroups[svcGroupName].IngressControllers = ingressControllers
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	k8sProcessor.On("GetTrafficShiftStates", "ingress", "shifts").Return(map[string]*model.TrafficShiftState{}, nil)
+	k8sProcessor.On("SaveTrafficShiftStates", "ingress", "shifts", mock.MatchedBy(func(states map[string]*model.TrafficShiftState) bool {
+		state := states["migrate"]
+		return state != nil && state.Running() && state.Step == 0 && state.DownMembers["lb"] == 1
+	})).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Return(1, nil)
+
 	exitCode := mainInternal()
-	suite.Assert().Equal(ExcutionTimedOut, exitCode)
+	suite.Assert().Equal(Normal, exitCode)
+	suite.Assert().Equal("100", nodes[0].Weight)
+	suite.Assert().Equal("25", nodes[1].Weight)
+	suite.Assert().Equal(map[string]int{"server1": 2, "server2": 2}, serviceGroups[svcGroupName].MemberPriorities)
+	k8sProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertExpectations(suite.T())
 }
 
-func (suite *MainTestSuite) Test_daemonExecutionTimesOut() {
+func clustersRunContext() *config.RunContext {
 	runContext := runContext()
-	runContext.Arguments.Interval = intPtr(1)
-	runContext.Arguments.Daemon = boolPtr(true)
+	runContext.Clusters = []config.ClusterConfig{
+		config.ClusterConfig{Context: "blue", Environment: *config.DefaultEnvironmentConfig()},
+		config.ClusterConfig{Context: "green", Environment: *config.DefaultEnvironmentConfig()},
+	}
+	return runContext
+}
+
+//clusterK8sProcessor mocks kubernetes processor of a cluster with single ingress controller on a single node serving svcGroup
+func clusterK8sProcessor(cluster string, serverName string) *mocks.K8sProcessor {
+	k8sProcessor := new(mocks.K8sProcessor)
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(&model.Environment{Cluster: cluster}, nil)
+	controller := &model.IngressController{Name: "ingress-controller", Namespace: "ingress", NodeSelectors: map[string]string{"cluster": cluster}}
+	k8sProcessor.On("FindIngressControllers").Return([]*model.IngressController{controller}, nil)
+	k8sProcessor.On("FindNodes", controller.NodeSelectors).Return([]*model.Node{
+		&model.Node{Name: "node", A10Server: serverName, IPAddress: "10.10.10.1", Weight: "1"},
+	}, nil)
+	serviceGroups := serviceGroups("svcGroup")
+	serviceGroups["svcGroup"].IngressControllers = []*model.IngressController{controller}
+	k8sProcessor.On("BuildServiceGroups", mock.Anything, mock.Anything, "lb").Return(serviceGroups)
+	return k8sProcessor
+}
+
+func (suite *MainTestSuite) Test_clusters() {
+	runContext := clustersRunContext()
 	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
 		return runContext, nil
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
-		time.Sleep(time.Second * 5)
-		return nil, nil
+	k8sProcessors := map[string]*mocks.K8sProcessor{
+		"blue":  clusterK8sProcessor("blue", "server1"),
+		"green": clusterK8sProcessor("green", "server2"),
+	}
+	originalBuildClusterK8sProcessor := suite.helper.SetBuildClusterK8sProcessorFunc(func(cluster config.ClusterConfig) (processor.K8sProcessor, error) {
+		return k8sProcessors[cluster.Context], nil
 	})
-	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+	defer suite.helper.SetBuildClusterK8sProcessorFunc(originalBuildClusterK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	//nodes with the same name in both clusters are distinct a10 servers
+	nodeProcessor.On("ProcessNode", mock.MatchedBy(func(node *model.Node) bool { return node.A10Server == "server1" && node.Cluster == "blue" })).Once().Return(nil)
+	nodeProcessor.On("ProcessNode", mock.MatchedBy(func(node *model.Node) bool { return node.A10Server == "server2" && node.Cluster == "green" })).Once().Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.MatchedBy(func(serviceGroup *model.ServiceGroup) bool {
+		return len(serviceGroup.IngressControllers) == 2 &&
+			serviceGroup.IngressControllers[0].Cluster == "blue" &&
+			serviceGroup.IngressControllers[1].Cluster == "green" &&
+			serviceGroup.OwnedServers == nil
+	}), []string{}).Once().Return(nil)
 
 	exitCode := mainInternal()
-	suite.Assert().Equal(ExcutionTimedOut, exitCode)
+	suite.Assert().Equal(Normal, exitCode)
+	nodeProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertExpectations(suite.T())
 }
 
-func (suite *MainTestSuite) Test_daemonExecutionErrorsOut() {
-	runContext := runContext()
-	runContext.Arguments.Daemon = boolPtr(true)
+func (suite *MainTestSuite) Test_clusterStateFails() {
+	runContext := clustersRunContext()
 	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
 		return runContext, nil
 	})
 	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
-	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func() (processor.K8sProcessor, error) {
-		return nil, errors.New("failure")
+	green := new(mocks.K8sProcessor)
+	green.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(nil, errors.New("apiserver unavailable"))
+	k8sProcessors := map[string]*mocks.K8sProcessor{
+		"blue":  clusterK8sProcessor("blue", "server1"),
+		"green": green,
+	}
+	originalBuildClusterK8sProcessor := suite.helper.SetBuildClusterK8sProcessorFunc(func(cluster config.ClusterConfig) (processor.K8sProcessor, error) {
+		return k8sProcessors[cluster.Context], nil
 	})
-	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+	defer suite.helper.SetBuildClusterK8sProcessorFunc(originalBuildClusterK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	nodeProcessor.On("ProcessNode", mock.Anything).Once().Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.MatchedBy(func(serviceGroup *model.ServiceGroup) bool {
+		return len(serviceGroup.IngressControllers) == 1 && len(serviceGroup.OwnedServers) == 1 && serviceGroup.OwnedServers[0] == "server1"
+	}), []string{}).Once().Return(nil)
 
 	exitCode := mainInternal()
-	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
+	suite.Assert().Equal(Normal, exitCode)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
 }
 
-func runContext() *config.RunContext {
-	return &config.RunContext{
-		Arguments: &config.Args{
-			Sort:     boolPtr(false),
-			Interval: intPtr(60),
-			Daemon:   boolPtr(false),
-		},
-		A10Instances: config.A10Instances{
-			config.A10Instance{
-				Name:       "lb",
-				UserName:   "user",
-				Password:   "pwd",
-				APIUrl:     "http://lb.com",
-				APIVersion: 3,
-			},
-		},
-	}
+func (suite *MainTestSuite) Test_allClustersFail() {
+	runContext := clustersRunContext()
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	originalBuildClusterK8sProcessor := suite.helper.SetBuildClusterK8sProcessorFunc(func(cluster config.ClusterConfig) (processor.K8sProcessor, error) {
+		return nil, errors.New("context not found")
+	})
+	defer suite.helper.SetBuildClusterK8sProcessorFunc(originalBuildClusterK8sProcessor)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
 }
 
-func serviceGroups(names ...string) map[string]*model.ServiceGroup {
-	serviceGroups := make(map[string]*model.ServiceGroup)
-	for _, svcGroupName := range names {
-		serviceGroup := model.ServiceGroup{
-			Name: svcGroupName,
-			Health: &model.HealthCheck{
-				Name: svcGroupName,
+//staticSource mocks static source with a server which serves svcGroup
+func staticSource(serverName string, ip string) *mocks.StateSource {
+	node := &model.Node{Name: serverName, Cluster: "metal", A10Server: serverName, IPAddress: ip, Weight: "10"}
+	source := new(mocks.StateSource)
+	source.On("Name").Return("metal")
+	source.On("Load").Return(&model.SourceState{
+		Nodes: []*model.Node{node},
+		ServiceGroups: []*model.ServiceGroup{
+			&model.ServiceGroup{
+				Name: "svcGroup",
+				IngressControllers: []*model.IngressController{
+					&model.IngressController{Name: "metal", Cluster: "metal", Nodes: []*model.Node{node}, Port: 8080},
+				},
 			},
-		}
-		serviceGroups[serviceGroup.Name] = &serviceGroup
-	}
-	return serviceGroups
+		},
+	}, nil)
+	return source
 }
 
-func nodes() []*model.Node {
-	nodes := make([]*model.Node, 0)
-	nodes = append(nodes, &model.Node{
-		A10Server: "server1",
-		IPAddress: "10.10.10.1",
-		Labels:    map[string]string{"test": "label"},
-		Name:      "node",
-		Weight:    "1",
-	})
-	nodes = append(nodes, &model.Node{
-		A10Server: "server2",
-		IPAddress: "10.10.10.2",
-		Labels:    map[string]string{"test": "label"},
-		Name:      "node",
-		Weight:    "2",
+func (suite *MainTestSuite) Test_staticSource() {
+	runContext := clustersRunContext()
+	runContext.Clusters = runContext.Clusters[:1]
+	runContext.Sources = []config.SourceConfig{config.SourceConfig{Name: "metal", File: "static.yaml"}}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
 	})
-	return nodes
-}
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
 
-func ingressControllers() []*model.IngressController {
-	controllers := make([]*model.IngressController, 0)
-	controllers = append(controllers, &model.IngressController{
-		NodeSelectors: map[string]string{"test": "selector"},
+	originalBuildClusterK8sProcessor := suite.helper.SetBuildClusterK8sProcessorFunc(func(cluster config.ClusterConfig) (processor.K8sProcessor, error) {
+		return clusterK8sProcessor("blue", "server1"), nil
 	})
-	return controllers
-}
+	defer suite.helper.SetBuildClusterK8sProcessorFunc(originalBuildClusterK8sProcessor)
 
-func environment() *model.Environment {
-	return &model.Environment{}
-}
+	//server1 is claimed by the cluster with a different ip, the node of the cluster wins
+	originalBuildStateSources := suite.helper.SetBuildStateSourcesFunc(func(sources []config.SourceConfig) []processor.StateSource {
+		return []processor.StateSource{staticSource("metal1", "10.20.0.1"), staticSource("server1", "10.20.0.2")}
+	})
+	defer suite.helper.SetBuildStateSourcesFunc(originalBuildStateSources)
 
-func intPtr(value int) *int {
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	nodeProcessor.On("ProcessNode", mock.MatchedBy(func(node *model.Node) bool { return node.A10Server == "server1" && node.Cluster == "blue" })).Once().Return(nil)
+	nodeProcessor.On("ProcessNode", mock.MatchedBy(func(node *model.Node) bool { return node.A10Server == "metal1" && node.Cluster == "metal" })).Once().Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.MatchedBy(func(serviceGroup *model.ServiceGroup) bool {
+		return len(serviceGroup.IngressControllers) == 3 &&
+			serviceGroup.IngressControllers[0].Cluster == "blue" &&
+			serviceGroup.IngressControllers[1].Cluster == "metal" &&
+			serviceGroup.OwnedServers == nil
+	}), []string{}).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	nodeProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_staticSourceFails() {
+	runContext := clustersRunContext()
+	runContext.Clusters = runContext.Clusters[:1]
+	runContext.Sources = []config.SourceConfig{config.SourceConfig{Name: "metal", File: "static.yaml"}}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	originalBuildClusterK8sProcessor := suite.helper.SetBuildClusterK8sProcessorFunc(func(cluster config.ClusterConfig) (processor.K8sProcessor, error) {
+		return clusterK8sProcessor("blue", "server1"), nil
+	})
+	defer suite.helper.SetBuildClusterK8sProcessorFunc(originalBuildClusterK8sProcessor)
+
+	source := new(mocks.StateSource)
+	source.On("Name").Return("metal")
+	source.On("Load").Return(nil, errors.New("no such file"))
+	originalBuildStateSources := suite.helper.SetBuildStateSourcesFunc(func(sources []config.SourceConfig) []processor.StateSource {
+		return []processor.StateSource{source}
+	})
+	defer suite.helper.SetBuildStateSourcesFunc(originalBuildStateSources)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	//members of the static servers are kept while the source can't be read
+	nodeProcessor.On("ProcessNode", mock.Anything).Once().Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.MatchedBy(func(serviceGroup *model.ServiceGroup) bool {
+		return len(serviceGroup.IngressControllers) == 1 && len(serviceGroup.OwnedServers) == 1 && serviceGroup.OwnedServers[0] == "server1"
+	}), []string{}).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_appliedState() {
+	runContext := runContext()
+	runContext.AppliedState = config.AppliedStateConfig{ConfigMap: "a10bridge-applied-state", KeepManualChanges: true}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	tracker := processor.NewAppliedStateTracker()
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Applied:      tracker,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
+	serviceGroups := serviceGroups("svcGroup")
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	previous := model.NewAppliedState()
+	previous.Servers["server1"] = &model.Node{A10Server: "server1"}
+	other := model.NewAppliedState()
+	k8sProcessor.On("GetAppliedStates", "ingress", "a10bridge-applied-state").Once().Return(map[string]*model.AppliedState{"lb": previous, "other": other}, nil)
+	k8sProcessor.On("SaveAppliedStates", "ingress", "a10bridge-applied-state", mock.MatchedBy(func(states map[string]*model.AppliedState) bool {
+		return len(states) == 2 && states["other"] == other && states["lb"] != previous && states["lb"].Servers["server1"] != nil
+	})).Once().Return(nil)
+	nodeProcessor.On("ProcessNode", mock.Anything).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups["svcGroup"], []string{}).Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	k8sProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_processHealthCheckFails() {
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext(), nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
+	svcGroupNameFail := "failingHealthCheck"
+	svcGroupName := "svcGroup"
+	serviceGroups := serviceGroups(svcGroupNameFail, svcGroupName)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
+
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupNameFail].Health, serviceGroups[svcGroupNameFail]).Return(errors.New("failure"))
+
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+}
+
+func (suite *MainTestSuite) Test_processServiceGroupFails() {
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext(), nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
+	svcGroupNameFail := "failingGroup"
+	svcGroupName := "svcGroup"
+	serviceGroups := serviceGroups(svcGroupNameFail, svcGroupName)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupNameFail].Health, serviceGroups[svcGroupNameFail]).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(nil)
+
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupNameFail], []string{}).Return(errors.New("failure"))
+	serviceGroupsProcessor.On("ProcessServiceGroup", serviceGroups[svcGroupName], []string{}).Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+}
+
+func (suite *MainTestSuite) Test_loadBalancers() {
+	runContext := runContext()
+	runContext.LoadBalancer = &config.LoadBalancerConfig{
+		NamePrefix: "k8s-",
+		VIPPool:    []string{"10.0.0.1"},
+	}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	loadBalancerProcessor := new(mocks.LoadBalancerProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			LoadBalancer: loadBalancerProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	k8sProcessor.On("FindIngressControllers").Return([]*model.IngressController{}, nil)
+	k8sProcessor.On("BuildServiceGroups", []*model.IngressController{}, environment, "lb").Return(map[string]*model.ServiceGroup{})
+	nodes := nodes()[:1]
+	loadBalancers := []*model.LoadBalancer{&model.LoadBalancer{Name: "web", Namespace: "default"}}
+	lbServiceGroup := &model.ServiceGroup{Name: "k8s-default-web-80"}
+	k8sProcessor.On("FindLoadBalancers").Return(loadBalancers, nil)
+	k8sProcessor.On("AssignVIPs", loadBalancers, []string{"10.0.0.1"}).Return()
+	k8sProcessor.On("FindNodes", map[string]string{}).Return(nodes, nil)
+	k8sProcessor.On("BuildLoadBalancerServiceGroups", loadBalancers, nodes, "k8s-").Return(map[string]*model.ServiceGroup{lbServiceGroup.Name: lbServiceGroup})
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", lbServiceGroup, []string{}).Return(nil)
+	loadBalancerProcessor.On("ProcessLoadBalancer", loadBalancers[0], []string{}).Return(nil)
+	loadBalancerProcessor.On("DeleteStaleLoadBalancers", loadBalancers, "k8s-").Return(nil)
+	k8sProcessor.On("PublishLoadBalancerStatus", loadBalancers[0]).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	healthCheckProcessor.AssertExpectations(suite.T())
+	loadBalancerProcessor.AssertExpectations(suite.T())
+	k8sProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_loadBalancerStatusNotPublishedWhenVirtualServerFails() {
+	runContext := runContext()
+	runContext.LoadBalancer = &config.LoadBalancerConfig{
+		NamePrefix: "k8s-",
+		VIPPool:    []string{"10.0.0.1"},
+	}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	loadBalancerProcessor := new(mocks.LoadBalancerProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			LoadBalancer: loadBalancerProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	k8sProcessor.On("FindIngressControllers").Return([]*model.IngressController{}, nil)
+	k8sProcessor.On("BuildServiceGroups", []*model.IngressController{}, environment, "lb").Return(map[string]*model.ServiceGroup{})
+	nodes := nodes()[:1]
+	loadBalancers := []*model.LoadBalancer{&model.LoadBalancer{Name: "web", Namespace: "default"}}
+	lbServiceGroup := &model.ServiceGroup{Name: "k8s-default-web-80"}
+	k8sProcessor.On("FindLoadBalancers").Return(loadBalancers, nil)
+	k8sProcessor.On("AssignVIPs", loadBalancers, []string{"10.0.0.1"}).Return()
+	k8sProcessor.On("FindNodes", map[string]string{}).Return(nodes, nil)
+	k8sProcessor.On("BuildLoadBalancerServiceGroups", loadBalancers, nodes, "k8s-").Return(map[string]*model.ServiceGroup{lbServiceGroup.Name: lbServiceGroup})
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", lbServiceGroup, []string{}).Return(nil)
+	loadBalancerProcessor.On("ProcessLoadBalancer", loadBalancers[0], []string{}).Return(errors.New("failure"))
+	loadBalancerProcessor.On("DeleteStaleLoadBalancers", loadBalancers, "k8s-").Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	healthCheckProcessor.AssertExpectations(suite.T())
+	loadBalancerProcessor.AssertExpectations(suite.T())
+	k8sProcessor.AssertNotCalled(suite.T(), "PublishLoadBalancerStatus", mock.Anything)
+}
+
+func (suite *MainTestSuite) Test_resources() {
+	runContext := runContext()
+	runContext.Arguments.CRD = boolPtr(true)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	k8sProcessor.On("FindIngressControllers").Return([]*model.IngressController{}, nil)
+	k8sProcessor.On("BuildServiceGroups", []*model.IngressController{}, environment, "lb").Return(map[string]*model.ServiceGroup{})
+
+	nodes := nodes()[:1]
+	web := &model.A10ServiceGroup{Name: "web", Namespace: "ingress", ServiceGroupName: "web", A10Instances: []string{"lb", "missing"}}
+	other := &model.A10ServiceGroup{Name: "other", Namespace: "ingress", ServiceGroupName: "other", A10Instances: []string{"other"}}
+	resources := []*model.A10ServiceGroup{web, other}
+	webServiceGroup := &model.ServiceGroup{
+		Name:               "web",
+		A10Instances:       web.A10Instances,
+		Resource:           web,
+		IngressControllers: []*model.IngressController{&model.IngressController{Port: 80, Nodes: nodes}},
+	}
+	otherServiceGroup := &model.ServiceGroup{
+		Name:         "other",
+		A10Instances: other.A10Instances,
+		Resource:     other,
+	}
+	k8sProcessor.On("FindServiceGroupResources").Return(resources, nil)
+	k8sProcessor.On("BuildResourceServiceGroups", resources, environment).Return(map[string]*model.ServiceGroup{
+		webServiceGroup.Name:   webServiceGroup,
+		otherServiceGroup.Name: otherServiceGroup,
+	})
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", webServiceGroup, []string{}).Return(nil)
+	k8sProcessor.On("UpdateServiceGroupResourceStatus", web, []*model.SyncResult{
+		&model.SyncResult{Instance: "lb"},
+		&model.SyncResult{Instance: "missing", Error: "a10 instance is not configured"},
+	}).Return(nil)
+	k8sProcessor.On("UpdateServiceGroupResourceStatus", other, []*model.SyncResult{
+		&model.SyncResult{Instance: "other", Error: "a10 instance is not configured"},
+	}).Return(errors.New("failure"))
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	k8sProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_instanceSelectors() {
+	runContext := runContext()
+	runContext.A10Instances[0].Selector = &config.InstanceSelector{ServiceGroups: []string{"^web"}}
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{
+		Name:     "lb2",
+		Selector: &config.InstanceSelector{Environment: map[string]string{"dataCenter": "dc"}},
+	})
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := &model.Environment{DataCenter: "dc"}
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	web := &model.IngressController{Name: "web", NodeSelectors: map[string]string{"test": "selector"}}
+	api := &model.IngressController{Name: "api", NodeSelectors: map[string]string{"test": "selector"}, A10Instances: []string{"lb"}}
+	ingressControllers := []*model.IngressController{web, api}
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()[:1]
+	k8sProcessor.On("FindNodes", web.NodeSelectors).Return(nodes, nil)
+
+	lbServiceGroups := map[string]*model.ServiceGroup{
+		"web-lb": &model.ServiceGroup{Name: "web-lb", IngressControllers: []*model.IngressController{web}},
+		"api-lb": &model.ServiceGroup{Name: "api-lb", IngressControllers: []*model.IngressController{api}},
+	}
+	lb2ServiceGroups := map[string]*model.ServiceGroup{
+		"web-lb2": &model.ServiceGroup{Name: "web-lb2", IngressControllers: []*model.IngressController{web}},
+		"api-lb2": &model.ServiceGroup{Name: "api-lb2", IngressControllers: []*model.IngressController{api}},
+	}
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(lbServiceGroups)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(lb2ServiceGroups)
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", lbServiceGroups["web-lb"], []string{}).Once().Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", lb2ServiceGroups["web-lb2"], []string{}).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertNumberOfCalls(suite.T(), "ProcessServiceGroup", 2)
+}
+
+func (suite *MainTestSuite) Test_canaryFailureHaltsRollout() {
+	runContext := runContext()
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{Name: "lb2"})
+	runContext.Canary = config.CanaryConfig{Instance: "lb2", Wait: 30, MaxDownMembers: 1, Revert: true}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	waited := make([]time.Duration, 0)
+	originalSleep := suite.helper.SetSleepFunc(func(duration time.Duration) {
+		waited = append(waited, duration)
+	})
+	defer suite.helper.SetSleepFunc(originalSleep)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	canary := processor.NewCanaryRollout()
+	built := make([]string, 0)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		built = append(built, a10instance.Name)
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Canary:       canary,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes(), nil)
+	svcGroupName := "svcGroup"
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups(svcGroupName))
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(serviceGroups(svcGroupName))
+	nodeProcessor.On("ProcessNode", mock.Anything).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	previous := &model.ServiceGroup{Name: svcGroupName, Members: []*model.Member{&model.Member{ServerName: "node1", Port: 80}}}
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.Anything, []string{}).Once().Run(func(args mock.Arguments) {
+		canary.ServiceGroupChanged(svcGroupName, previous)
+	}).Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Return(2, nil)
+	serviceGroupsProcessor.On("RevertServiceGroup", svcGroupName, previous).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(CanaryFailed, exitCode)
+	suite.Assert().Equal([]string{"lb2"}, built)
+	suite.Assert().Equal([]time.Duration{30 * time.Second}, waited)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_canaryFailureRevertsServersAndHealthMonitors() {
+	runContext := runContext()
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{Name: "lb2"})
+	runContext.Canary = config.CanaryConfig{Instance: "lb2", Wait: 30, Revert: true}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	originalSleep := suite.helper.SetSleepFunc(func(duration time.Duration) {})
+	defer suite.helper.SetSleepFunc(originalSleep)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	canary := processor.NewCanaryRollout()
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Canary:       canary,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes(), nil)
+	svcGroupName := "svcGroup"
+	serviceGroups := serviceGroups(svcGroupName)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(serviceGroups)
+	previousServer := &model.Node{A10Server: "server", IPAddress: "10.0.0.1"}
+	nodeProcessor.On("ProcessNode", mock.Anything).Once().Run(func(args mock.Arguments) {
+		canary.ServerChanged("server", previousServer)
+	}).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+		canary.HealthCheckChanged(serviceGroups[svcGroupName].Health.Name, nil)
+	}).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.Anything, []string{}).Once().Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Once().Return(1, nil)
+	nodeProcessor.On("RevertServer", "server", previousServer).Once().Return(nil)
+	healthCheckProcessor.On("RevertHealthCheck", serviceGroups[svcGroupName].Health.Name, (*model.HealthCheck)(nil)).Once().Return(nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(CanaryFailed, exitCode)
+	nodeProcessor.AssertExpectations(suite.T())
+	healthCheckProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertNotCalled(suite.T(), "RevertServiceGroup", mock.Anything, mock.Anything)
+}
+
+func (suite *MainTestSuite) Test_revertedCanaryChangeIsNotRetried() {
+	directory, err := ioutil.TempDir("", "a10bridge")
+	suite.Require().Nil(err)
+	defer os.RemoveAll(directory)
+	runContext := runContext()
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{Name: "lb2"})
+	runContext.Canary = config.CanaryConfig{Instance: "lb2", Wait: 30, Revert: true}
+	runContext.AppliedState = config.AppliedStateConfig{File: directory + "/applied.json"}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	waited := make([]time.Duration, 0)
+	originalSleep := suite.helper.SetSleepFunc(func(duration time.Duration) {
+		waited = append(waited, duration)
+	})
+	defer suite.helper.SetSleepFunc(originalSleep)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	canary := processor.NewCanaryRollout()
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Canary:       canary,
+			Applied:      processor.NewAppliedStateTracker(),
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Twice().Return(nodes(), nil)
+	changedNodes := nodes()
+	changedNodes[1].Weight = "3"
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Once().Return(changedNodes, nil)
+	svcGroupName := "svcGroup"
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups(svcGroupName))
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(serviceGroups(svcGroupName))
+	nodeProcessor.On("ProcessNode", mock.Anything).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.Anything, []string{}).Twice().Run(func(args mock.Arguments) {
+		canary.ServiceGroupChanged(svcGroupName, nil)
+	}).Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Twice().Return(1, nil)
+	serviceGroupsProcessor.On("RevertServiceGroup", svcGroupName, (*model.ServiceGroup)(nil)).Twice().Return(nil)
+
+	suite.Assert().Equal(CanaryFailed, mainInternal())
+	suite.Assert().Equal(CanaryFailed, mainInternal())
+	suite.Assert().Equal([]time.Duration{30 * time.Second}, waited)
+	serviceGroupsProcessor.AssertNumberOfCalls(suite.T(), "ProcessServiceGroup", 1)
+
+	suite.Assert().Equal(CanaryFailed, mainInternal())
+	suite.Assert().Equal([]time.Duration{30 * time.Second, 30 * time.Second}, waited)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_canaryVerified() {
+	runContext := runContext()
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{Name: "lb2"})
+	runContext.Canary = config.CanaryConfig{Instance: "lb2", Wait: 30, Revert: true}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	originalSleep := suite.helper.SetSleepFunc(func(duration time.Duration) {})
+	defer suite.helper.SetSleepFunc(originalSleep)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	canary := processor.NewCanaryRollout()
+	built := make([]string, 0)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		built = append(built, a10instance.Name)
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Canary:       canary,
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes(), nil)
+	svcGroupName := "svcGroup"
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups(svcGroupName))
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(serviceGroups(svcGroupName))
+	nodeProcessor.On("ProcessNode", mock.Anything).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.Anything, []string{}).Twice().Run(func(args mock.Arguments) {
+		canary.ServiceGroupChanged(svcGroupName, nil)
+	}).Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Once().Return(0, nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	suite.Assert().Equal([]string{"lb2", "lb"}, built)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertNotCalled(suite.T(), "RevertServiceGroup", mock.Anything, mock.Anything)
+}
+
+func (suite *MainTestSuite) Test_unchangedCanaryIsChecked() {
+	runContext := runContext()
+	runContext.A10Instances = append(runContext.A10Instances, config.A10Instance{Name: "lb2"})
+	runContext.Canary = config.CanaryConfig{Instance: "lb2", Wait: 30, Revert: true}
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	originalSleep := suite.helper.SetSleepFunc(func(duration time.Duration) {
+		suite.Fail("unchanged canary should be checked without waiting")
+	})
+	defer suite.helper.SetSleepFunc(originalSleep)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	built := make([]string, 0)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		built = append(built, a10instance.Name)
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Canary:       processor.NewCanaryRollout(),
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes(), nil)
+	svcGroupName := "svcGroup"
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups(svcGroupName))
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb2").Return(serviceGroups(svcGroupName))
+	nodeProcessor.On("ProcessNode", mock.Anything).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", mock.Anything, mock.Anything).Return(nil)
+	serviceGroupsProcessor.On("ProcessServiceGroup", mock.Anything, []string{}).Once().Return(nil)
+	serviceGroupsProcessor.On("CountDownMembers", svcGroupName).Once().Return(1, nil)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(CanaryFailed, exitCode)
+	suite.Assert().Equal([]string{"lb2"}, built)
+	serviceGroupsProcessor.AssertExpectations(suite.T())
+	serviceGroupsProcessor.AssertNotCalled(suite.T(), "RevertServiceGroup", mock.Anything, mock.Anything)
+}
+
+func (suite *MainTestSuite) Test_findServiceGroupResourcesFails() {
+	runContext := runContext()
+	runContext.Arguments.CRD = boolPtr(true)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	k8sProcessor.On("FindIngressControllers").Return([]*model.IngressController{}, nil)
+	k8sProcessor.On("BuildServiceGroups", []*model.IngressController{}, environment, "lb").Return(map[string]*model.ServiceGroup{})
+	k8sProcessor.On("FindServiceGroupResources").Return(nil, errors.New("failure"))
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
+}
+
+func (suite *MainTestSuite) Test_events() {
+	runContext := runContext()
+	runContext.Arguments.Events = boolPtr(true)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	k8sProcessor := new(mocks.K8sProcessor)
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return k8sProcessor, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	healthCheckProcessor := new(mocks.HealthCheckProcessor)
+	nodeProcessor := new(mocks.NodeProcessor)
+	serviceGroupsProcessor := new(mocks.ServiceGroupProcessor)
+	originalBuildA10Processors := suite.helper.SetBuildA10ProcessorsFunc(func(a10instance *config.A10Instance) (*processor.A10Processors, error) {
+		return &processor.A10Processors{
+			HealthCheck:  healthCheckProcessor,
+			Node:         nodeProcessor,
+			ServiceGroup: serviceGroupsProcessor,
+			Recorder:     processor.NewSyncRecorder(a10instance.Name),
+		}, nil
+	})
+	defer suite.helper.SetBuildA10ProcessorsFunc(originalBuildA10Processors)
+
+	environment := environment()
+	k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(environment, nil)
+	ingressControllers := ingressControllers()
+	ingressControllers[0].Name = "ingress-controller"
+	ingressControllers[0].Namespace = "ingress"
+	k8sProcessor.On("FindIngressControllers").Return(ingressControllers, nil)
+	nodes := nodes()
+	k8sProcessor.On("FindNodes", ingressControllers[0].NodeSelectors).Return(nodes, nil)
+	svcGroupName := "svcGroup"
+	serviceGroups := serviceGroups(svcGroupName)
+	serviceGroups[svcGroupName].IngressControllers = ingressControllers
+	k8sProcessor.On("BuildServiceGroups", ingressControllers, environment, "lb").Return(serviceGroups)
+	nodeProcessor.On("ProcessNode", nodes[0]).Return(nil)
+	nodeProcessor.On("ProcessNode", nodes[1]).Return(nil)
+	healthCheckProcessor.On("ProcessHealthCheck", serviceGroups[svcGroupName].Health, serviceGroups[svcGroupName]).Return(errors.New("failure"))
+	k8sProcessor.On("PublishSyncEvents", []*model.SyncEvent{
+		&model.SyncEvent{
+			Kind:      model.KindDaemonSet,
+			Namespace: "ingress",
+			Name:      "ingress-controller",
+			Instance:  "lb",
+			Type:      model.EventTypeWarning,
+			Reason:    model.ReasonSyncFailed,
+			Message:   "a10 lb: failure",
+		},
+	}).Once().Return(errors.New("failure"))
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(Normal, exitCode)
+	k8sProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *MainTestSuite) Test_webhook() {
+	runContext := runContext()
+	runContext.Arguments.WebhookAddr = stringPtr(":8443")
+	runContext.Arguments.WebhookCert = stringPtr("tls.crt")
+	runContext.Arguments.WebhookKey = stringPtr("tls.key")
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	started := make(chan []string, 1)
+	originalWebhookStart := suite.helper.SetWebhookStartFunc(func(address, certFile, keyFile string, clientConfig apiserver.ClientConfig, environmentConfig *config.EnvironmentConfig) error {
+		started <- []string{address, certFile, keyFile}
+		return errors.New("failure")
+	})
+	defer suite.helper.SetWebhookStartFunc(originalWebhookStart)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return nil, errors.New("failure")
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
+	suite.Assert().Equal([]string{":8443", "tls.crt", "tls.key"}, <-started)
+}
+
+func (suite *MainTestSuite) Test_executionTimesOut() {
+	runContext := runContext()
+	runContext.Arguments.Interval = intPtr(1)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		time.Sleep(time.Second * 5)
+		return nil, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(ExcutionTimedOut, exitCode)
+}
+
+func (suite *MainTestSuite) Test_daemonExecutionTimesOut() {
+	runContext := runContext()
+	runContext.Arguments.Interval = intPtr(1)
+	runContext.Arguments.Daemon = boolPtr(true)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		time.Sleep(time.Second * 5)
+		return nil, nil
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(ExcutionTimedOut, exitCode)
+}
+
+func (suite *MainTestSuite) Test_daemonExecutionErrorsOut() {
+	runContext := runContext()
+	runContext.Arguments.Daemon = boolPtr(true)
+	originalBuildConfig := suite.helper.SetBuildConfigFunc(func() (*config.RunContext, error) {
+		return runContext, nil
+	})
+	defer suite.helper.SetBuildConfigFunc(originalBuildConfig)
+
+	originalBuildK8sProcessor := suite.helper.SetBuildK8sProcessorFunc(func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error) {
+		return nil, errors.New("failure")
+	})
+	defer suite.helper.SetBuildK8sProcessorFunc(originalBuildK8sProcessor)
+
+	exitCode := mainInternal()
+	suite.Assert().Equal(FailedToBuildExpectedState, exitCode)
+}
+
+func runContext() *config.RunContext {
+	return &config.RunContext{
+		Arguments: &config.Args{
+			Sort:             boolPtr(false),
+			Interval:         intPtr(60),
+			Daemon:           boolPtr(false),
+			CRD:              boolPtr(false),
+			Events:           boolPtr(false),
+			WebhookAddr:      stringPtr(""),
+			Kubeconfig:       stringPtr(""),
+			Context:          stringPtr(""),
+			Master:           stringPtr(""),
+			InCluster:        boolPtr(false),
+			AllowMassRemoval: boolPtr(false),
+		},
+		Environment: config.DefaultEnvironmentConfig(),
+		A10Instances: config.A10Instances{
+			config.A10Instance{
+				Name:       "lb",
+				UserName:   "user",
+				Password:   "pwd",
+				APIUrl:     "http://lb.com",
+				APIVersion: 3,
+			},
+		},
+	}
+}
+
+func serviceGroups(names ...string) map[string]*model.ServiceGroup {
+	serviceGroups := make(map[string]*model.ServiceGroup)
+	for _, svcGroupName := range names {
+		serviceGroup := model.ServiceGroup{
+			Name: svcGroupName,
+			Health: &model.HealthCheck{
+				Name: svcGroupName,
+			},
+		}
+		serviceGroups[serviceGroup.Name] = &serviceGroup
+	}
+	return serviceGroups
+}
+
+func nodes() []*model.Node {
+	nodes := make([]*model.Node, 0)
+	nodes = append(nodes, &model.Node{
+		A10Server: "server1",
+		IPAddress: "10.10.10.1",
+		Labels:    map[string]string{"test": "label"},
+		Name:      "node",
+		Weight:    "1",
+	})
+	nodes = append(nodes, &model.Node{
+		A10Server: "server2",
+		IPAddress: "10.10.10.2",
+		Labels:    map[string]string{"test": "label"},
+		Name:      "node",
+		Weight:    "2",
+	})
+	return nodes
+}
+
+func ingressControllers() []*model.IngressController {
+	controllers := make([]*model.IngressController, 0)
+	controllers = append(controllers, &model.IngressController{
+		NodeSelectors: map[string]string{"test": "selector"},
+	})
+	return controllers
+}
+
+func environment() *model.Environment {
+	return &model.Environment{}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func stringPtr(value string) *string {
 	return &value
 }
 
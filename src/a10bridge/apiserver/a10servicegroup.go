@@ -0,0 +1,275 @@
+package apiserver
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	a10ServiceGroupAPIGroup   = "a10bridge.io"
+	a10ServiceGroupAPIVersion = "v1alpha1"
+	a10ServiceGroupPlural     = "a10servicegroups"
+	syncedCondition           = "Synced"
+)
+
+type a10ServiceGroupResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              a10ServiceGroupSpec   `json:"spec"`
+	Status            a10ServiceGroupStatus `json:"status,omitempty"`
+}
+
+type a10ServiceGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []a10ServiceGroupResource `json:"items"`
+}
+
+type a10ServiceGroupSpec struct {
+	Name             string                 `json:"name"`
+	Instances        []string               `json:"instances,omitempty"`
+	NodeSelector     map[string]string      `json:"nodeSelector,omitempty"`
+	PodSelector      map[string]string      `json:"podSelector,omitempty"`
+	Ports            []int                  `json:"ports"`
+	Health           *a10ServiceGroupHealth `json:"health,omitempty"`
+	LBMethod         string                 `json:"lbMethod,omitempty"`
+	Protocol         string                 `json:"protocol,omitempty"`
+	MinActiveMembers int                    `json:"minActiveMembers,omitempty"`
+}
+
+type a10ServiceGroupHealth struct {
+	Endpoint                  string `json:"endpoint"`
+	Port                      int    `json:"port,omitempty"`
+	ExpectCode                string `json:"expectCode,omitempty"`
+	Interval                  int    `json:"interval,omitempty"`
+	Timeout                   int    `json:"timeout,omitempty"`
+	RetryCount                int    `json:"retryCount,omitempty"`
+	RequiredConsecutivePasses int    `json:"requiredConsecutivePasses,omitempty"`
+}
+
+type a10ServiceGroupStatus struct {
+	ObservedGeneration int64                           `json:"observedGeneration,omitempty"`
+	Instances          []a10ServiceGroupInstanceStatus `json:"instances,omitempty"`
+	Conditions         []a10ServiceGroupCondition      `json:"conditions,omitempty"`
+}
+
+type a10ServiceGroupInstanceStatus struct {
+	Name    string `json:"name"`
+	Synced  bool   `json:"synced"`
+	Message string `json:"message,omitempty"`
+}
+
+type a10ServiceGroupCondition struct {
+	Type               string             `json:"type"`
+	Status             v1.ConditionStatus `json:"status"`
+	Reason             string             `json:"reason,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	LastTransitionTime metav1.Time        `json:"lastTransitionTime,omitempty"`
+}
+
+//a10ServiceGroupClient reads and updates A10ServiceGroup custom resources through the raw rest api
+type a10ServiceGroupClient struct {
+	restClient rest.Interface
+}
+
+func (client a10ServiceGroupClient) list() (*a10ServiceGroupList, error) {
+	if client.restClient == nil {
+		return nil, errors.New("A10ServiceGroup custom resources are not supported by this client")
+	}
+
+	body, err := client.restClient.Get().
+		AbsPath("/apis", a10ServiceGroupAPIGroup, a10ServiceGroupAPIVersion, a10ServiceGroupPlural).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	list := a10ServiceGroupList{}
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (client a10ServiceGroupClient) get(namespace, name string) (*a10ServiceGroupResource, error) {
+	if client.restClient == nil {
+		return nil, errors.New("A10ServiceGroup custom resources are not supported by this client")
+	}
+
+	body, err := client.restClient.Get().
+		AbsPath("/apis", a10ServiceGroupAPIGroup, a10ServiceGroupAPIVersion, "namespaces", namespace, a10ServiceGroupPlural, name).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	resource := a10ServiceGroupResource{}
+	err = json.Unmarshal(body, &resource)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (client a10ServiceGroupClient) updateStatus(resource *a10ServiceGroupResource) error {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.restClient.Put().
+		AbsPath("/apis", a10ServiceGroupAPIGroup, a10ServiceGroupAPIVersion, "namespaces", resource.GetNamespace(), a10ServiceGroupPlural, resource.GetName(), "status").
+		Body(body).
+		DoRaw()
+	return err
+}
+
+func buildA10ServiceGroup(resource a10ServiceGroupResource) *model.A10ServiceGroup {
+	spec := resource.Spec
+	serviceGroup := &model.A10ServiceGroup{
+		Name:                     resource.GetName(),
+		Namespace:                resource.GetNamespace(),
+		Generation:               resource.GetGeneration(),
+		ServiceGroupNameTemplate: spec.Name,
+		A10Instances:             spec.Instances,
+		NodeSelectors:            spec.NodeSelector,
+		PodSelectors:             spec.PodSelector,
+		Ports:                    spec.Ports,
+		Method:                   spec.LBMethod,
+		Protocol:                 spec.Protocol,
+		MinActiveMembers:         spec.MinActiveMembers,
+	}
+
+	err := validateA10ServiceGroupSpec(spec)
+	if err != nil {
+		serviceGroup.Error = err.Error()
+		return serviceGroup
+	}
+
+	if spec.Health != nil {
+		serviceGroup.Health = &model.HealthCheck{
+			Endpoint:                  spec.Health.Endpoint,
+			Port:                      spec.Health.Port,
+			ExpectCode:                spec.Health.ExpectCode,
+			Interval:                  spec.Health.Interval,
+			Timeout:                   spec.Health.Timeout,
+			RetryCount:                spec.Health.RetryCount,
+			RequiredConsecutivePasses: spec.Health.RequiredConsecutivePasses,
+		}
+		if serviceGroup.Health.Port == 0 {
+			serviceGroup.Health.Port = spec.Ports[0]
+		}
+		if len(serviceGroup.Health.ExpectCode) == 0 {
+			serviceGroup.Health.ExpectCode = "200"
+		}
+		if serviceGroup.Health.Interval == 0 {
+			serviceGroup.Health.Interval = 5
+		}
+		if serviceGroup.Health.Timeout == 0 {
+			serviceGroup.Health.Timeout = 5
+		}
+		if serviceGroup.Health.RetryCount == 0 {
+			serviceGroup.Health.RetryCount = 3
+		}
+		if serviceGroup.Health.RequiredConsecutivePasses == 0 {
+			serviceGroup.Health.RequiredConsecutivePasses = 1
+		}
+	}
+
+	return serviceGroup
+}
+
+func validateA10ServiceGroupSpec(spec a10ServiceGroupSpec) error {
+	if len(strings.TrimSpace(spec.Name)) == 0 {
+		return errors.New("spec.name is required")
+	}
+	if len(spec.Ports) == 0 {
+		return errors.New("spec.ports requires at least one port")
+	}
+	for _, port := range spec.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("spec.ports contains invalid port %d", port)
+		}
+	}
+	if spec.Health != nil {
+		if len(spec.Health.Endpoint) == 0 {
+			return errors.New("spec.health.endpoint is required when health is set")
+		}
+		if spec.Health.Port < 0 || spec.Health.Port > 65535 {
+			return fmt.Errorf("spec.health.port contains invalid port %d", spec.Health.Port)
+		}
+	}
+	if len(spec.LBMethod) > 0 && !util.Contains(model.LBMethods, spec.LBMethod) {
+		return fmt.Errorf("spec.lbMethod '%s' is not supported, use one of %s", spec.LBMethod, strings.Join(model.LBMethods, ", "))
+	}
+	if len(spec.Protocol) > 0 && !util.Contains(model.Protocols, spec.Protocol) {
+		return fmt.Errorf("spec.protocol '%s' is not supported, use one of %s", spec.Protocol, strings.Join(model.Protocols, ", "))
+	}
+	if spec.MinActiveMembers < 0 || spec.MinActiveMembers > 1024 {
+		return fmt.Errorf("spec.minActiveMembers has to be between 0 and 1024, got %d", spec.MinActiveMembers)
+	}
+	return nil
+}
+
+//applyA10ServiceGroupStatus writes sync results into the resource status keeping the transition time of unchanged conditions
+func applyA10ServiceGroupStatus(resource *a10ServiceGroupResource, serviceGroup *model.A10ServiceGroup, results []*model.SyncResult) {
+	status := a10ServiceGroupStatus{
+		ObservedGeneration: serviceGroup.Generation,
+		Instances:          make([]a10ServiceGroupInstanceStatus, 0),
+	}
+
+	failures := make([]string, 0)
+	for _, result := range results {
+		status.Instances = append(status.Instances, a10ServiceGroupInstanceStatus{
+			Name:    result.Instance,
+			Synced:  len(result.Error) == 0,
+			Message: result.Error,
+		})
+		if len(result.Error) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Instance, result.Error))
+		}
+	}
+	sort.Slice(status.Instances, func(i, j int) bool {
+		return status.Instances[i].Name < status.Instances[j].Name
+	})
+
+	condition := a10ServiceGroupCondition{
+		Type:   syncedCondition,
+		Status: v1.ConditionTrue,
+		Reason: "Synced",
+	}
+	switch {
+	case len(serviceGroup.Error) > 0:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "InvalidSpec"
+		condition.Message = serviceGroup.Error
+	case len(failures) > 0:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "SyncFailed"
+		condition.Message = strings.Join(failures, "; ")
+	case len(results) == 0:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "NoInstances"
+		condition.Message = "service group is not targeting any configured a10 instance"
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	for _, existing := range resource.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	status.Conditions = []a10ServiceGroupCondition{condition}
+
+	resource.Status = status
+}
@@ -0,0 +1,297 @@
+package apiserver_test
+
+import (
+	"a10bridge/apiserver"
+	"a10bridge/model"
+	a10bridgetesting "a10bridge/testing"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+type A10ServiceGroupTestSuite struct {
+	suite.Suite
+	helper     *apiserver.TestHelper
+	testServer *a10bridgetesting.ServerConfig
+	client     apiserver.K8sClient
+}
+
+func (suite *A10ServiceGroupTestSuite) SetupTest() {
+	suite.testServer.Reset()
+}
+
+func TestA10ServiceGroup(t *testing.T) {
+	tests := new(A10ServiceGroupTestSuite)
+	tests.helper = new(apiserver.TestHelper)
+	tests.testServer = a10bridgetesting.NewTestServer(t).Start()
+	defer tests.testServer.Stop()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{
+		Host:            tests.testServer.GetURL(),
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests.client = tests.helper.BuildClientWithRestClient(fake.NewSimpleClientset(), clientset.CoreV1().RESTClient())
+
+	suite.Run(t, tests)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestGetA10ServiceGroups() {
+	suite.testServer.AddRequest().
+		Method(http.MethodGet).
+		Path("/apis/a10bridge.io/v1alpha1/a10servicegroups").
+		Response().
+		Body(`{
+  "apiVersion": "a10bridge.io/v1alpha1",
+  "kind": "A10ServiceGroupList",
+  "items": [
+    {
+      "metadata": {"name": "web", "namespace": "ingress", "generation": 3},
+      "spec": {
+        "name": "{{.Cluster}}-web",
+        "instances": ["lb01"],
+        "nodeSelector": {"role": "ingress"},
+        "podSelector": {"app": "web"},
+        "ports": [80, 443],
+        "health": {"endpoint": "/healthz", "port": 10254},
+        "lbMethod": "least-connection",
+        "protocol": "udp",
+        "minActiveMembers": 2
+      }
+    },
+    {
+      "metadata": {"name": "minimal", "namespace": "ingress", "generation": 1},
+      "spec": {"name": "minimal", "ports": [8080]}
+    }
+  ]
+}`, "application/json")
+
+	serviceGroups, err := suite.client.GetA10ServiceGroups()
+	suite.Assert().Nil(err)
+	suite.Assert().Len(serviceGroups, 2)
+
+	suite.Assert().Equal(&model.A10ServiceGroup{
+		Name:                     "web",
+		Namespace:                "ingress",
+		Generation:               3,
+		ServiceGroupNameTemplate: "{{.Cluster}}-web",
+		A10Instances:             []string{"lb01"},
+		NodeSelectors:            map[string]string{"role": "ingress"},
+		PodSelectors:             map[string]string{"app": "web"},
+		Ports:                    []int{80, 443},
+		Health: &model.HealthCheck{
+			Endpoint:                  "/healthz",
+			Port:                      10254,
+			ExpectCode:                "200",
+			Interval:                  5,
+			Timeout:                   5,
+			RetryCount:                3,
+			RequiredConsecutivePasses: 1,
+		},
+		Method:           "least-connection",
+		Protocol:         model.ProtocolUDP,
+		MinActiveMembers: 2,
+	}, serviceGroups[0])
+
+	suite.Assert().Equal("minimal", serviceGroups[1].ServiceGroupNameTemplate)
+	suite.Assert().Nil(serviceGroups[1].Health)
+	suite.Assert().Empty(serviceGroups[1].Error)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestGetA10ServiceGroups_invalidSpecs() {
+	suite.testServer.AddRequest().
+		Method(http.MethodGet).
+		Path("/apis/a10bridge.io/v1alpha1/a10servicegroups").
+		Response().
+		Body(`{
+  "items": [
+    {"metadata": {"name": "noname"}, "spec": {"ports": [80]}},
+    {"metadata": {"name": "noports"}, "spec": {"name": "sg"}},
+    {"metadata": {"name": "badport"}, "spec": {"name": "sg", "ports": [70000]}},
+    {"metadata": {"name": "noendpoint"}, "spec": {"name": "sg", "ports": [80], "health": {"port": 80}}},
+    {"metadata": {"name": "badmethod"}, "spec": {"name": "sg", "ports": [80], "lbMethod": "random"}},
+    {"metadata": {"name": "badprotocol"}, "spec": {"name": "sg", "ports": [80], "protocol": "sctp"}},
+    {"metadata": {"name": "badminactive"}, "spec": {"name": "sg", "ports": [80], "minActiveMembers": -1}}
+  ]
+}`, "application/json")
+
+	serviceGroups, err := suite.client.GetA10ServiceGroups()
+	suite.Assert().Nil(err)
+	suite.Assert().Len(serviceGroups, 7)
+	for _, serviceGroup := range serviceGroups {
+		suite.Assert().NotEmpty(serviceGroup.Error, serviceGroup.Name)
+	}
+}
+
+func (suite *A10ServiceGroupTestSuite) TestGetA10ServiceGroups_apiCallFails() {
+	suite.testServer.AddRequest().
+		Response().
+		StatusCode(404)
+
+	serviceGroups, err := suite.client.GetA10ServiceGroups()
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(serviceGroups)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestGetA10ServiceGroups_notSupportedByClient() {
+	client := suite.helper.BuildClient(fake.NewSimpleClientset())
+
+	serviceGroups, err := client.GetA10ServiceGroups()
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(serviceGroups)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestUpdateA10ServiceGroupStatus() {
+	serviceGroup := &model.A10ServiceGroup{Name: "web", Namespace: "ingress", Generation: 4}
+	results := []*model.SyncResult{
+		&model.SyncResult{Instance: "lb02", Error: "connection refused"},
+		&model.SyncResult{Instance: "lb01"},
+	}
+
+	suite.testServer.AddRequest().
+		Method(http.MethodGet).
+		Path("/apis/a10bridge.io/v1alpha1/namespaces/ingress/a10servicegroups/web").
+		Response().
+		Body(`{
+  "metadata": {"name": "web", "namespace": "ingress", "generation": 4, "resourceVersion": "42"},
+  "spec": {"name": "web", "ports": [80]},
+  "status": {
+    "conditions": [{"type": "Synced", "status": "True", "reason": "Synced", "lastTransitionTime": "2018-01-01T00:00:00Z"}]
+  }
+}`, "application/json")
+	suite.testServer.AddRequest().
+		Method(http.MethodPut).
+		Path("/apis/a10bridge.io/v1alpha1/namespaces/ingress/a10servicegroups/web/status").
+		BodyInspector(func(body string) (bool, string) {
+			resource := struct {
+				Metadata struct {
+					ResourceVersion string `json:"resourceVersion"`
+				} `json:"metadata"`
+				Status struct {
+					ObservedGeneration int64 `json:"observedGeneration"`
+					Instances          []struct {
+						Name    string `json:"name"`
+						Synced  bool   `json:"synced"`
+						Message string `json:"message"`
+					} `json:"instances"`
+					Conditions []struct {
+						Type               string `json:"type"`
+						Status             string `json:"status"`
+						Reason             string `json:"reason"`
+						Message            string `json:"message"`
+						LastTransitionTime string `json:"lastTransitionTime"`
+					} `json:"conditions"`
+				} `json:"status"`
+			}{}
+			err := json.Unmarshal([]byte(body), &resource)
+			if err != nil {
+				return false, err.Error()
+			}
+			status := resource.Status
+			switch {
+			case resource.Metadata.ResourceVersion != "42":
+				return false, "resource version was not preserved"
+			case status.ObservedGeneration != 4:
+				return false, "unexpected observed generation"
+			case len(status.Instances) != 2 || status.Instances[0].Name != "lb01" || !status.Instances[0].Synced:
+				return false, "unexpected status of lb01"
+			case status.Instances[1].Synced || status.Instances[1].Message != "connection refused":
+				return false, "unexpected status of lb02"
+			case len(status.Conditions) != 1 || status.Conditions[0].Status != "False" || status.Conditions[0].Reason != "SyncFailed":
+				return false, "unexpected condition " + body
+			case status.Conditions[0].LastTransitionTime == "2018-01-01T00:00:00Z":
+				return false, "transition time should change with condition status"
+			}
+			return true, ""
+		}).
+		Response().
+		Body(`{}`, "application/json")
+
+	err := suite.client.UpdateA10ServiceGroupStatus(serviceGroup, results)
+	suite.Assert().Nil(err)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestUpdateA10ServiceGroupStatus_keepsTransitionTime() {
+	serviceGroup := &model.A10ServiceGroup{Name: "web", Namespace: "ingress", Generation: 1}
+
+	suite.testServer.AddRequest().
+		Method(http.MethodGet).
+		Response().
+		Body(`{
+  "metadata": {"name": "web", "namespace": "ingress"},
+  "status": {
+    "conditions": [{"type": "Synced", "status": "True", "reason": "Synced", "lastTransitionTime": "2018-01-01T00:00:00Z"}]
+  }
+}`, "application/json")
+	suite.testServer.AddRequest().
+		Method(http.MethodPut).
+		BodyInspector(func(body string) (bool, string) {
+			resource := struct {
+				Status struct {
+					Conditions []struct {
+						Status             string `json:"status"`
+						LastTransitionTime string `json:"lastTransitionTime"`
+					} `json:"conditions"`
+				} `json:"status"`
+			}{}
+			json.Unmarshal([]byte(body), &resource)
+			conditions := resource.Status.Conditions
+			if len(conditions) != 1 || conditions[0].Status != "True" || conditions[0].LastTransitionTime != "2018-01-01T00:00:00Z" {
+				return false, "unexpected condition " + body
+			}
+			return true, ""
+		}).
+		Response().
+		Body(`{}`, "application/json")
+
+	err := suite.client.UpdateA10ServiceGroupStatus(serviceGroup, []*model.SyncResult{&model.SyncResult{Instance: "lb01"}})
+	suite.Assert().Nil(err)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestUpdateA10ServiceGroupStatus_invalidSpec() {
+	serviceGroup := &model.A10ServiceGroup{Name: "web", Namespace: "ingress", Error: "spec.ports requires at least one port"}
+
+	suite.testServer.AddRequest().
+		Method(http.MethodGet).
+		Response().
+		Body(`{"metadata": {"name": "web", "namespace": "ingress"}}`, "application/json")
+	suite.testServer.AddRequest().
+		Method(http.MethodPut).
+		BodyInspector(func(body string) (bool, string) {
+			resource := struct {
+				Status struct {
+					Conditions []struct {
+						Reason  string `json:"reason"`
+						Message string `json:"message"`
+					} `json:"conditions"`
+				} `json:"status"`
+			}{}
+			json.Unmarshal([]byte(body), &resource)
+			conditions := resource.Status.Conditions
+			if len(conditions) != 1 || conditions[0].Reason != "InvalidSpec" || conditions[0].Message != serviceGroup.Error {
+				return false, "unexpected condition " + body
+			}
+			return true, ""
+		}).
+		Response().
+		Body(`{}`, "application/json")
+
+	err := suite.client.UpdateA10ServiceGroupStatus(serviceGroup, []*model.SyncResult{})
+	suite.Assert().Nil(err)
+}
+
+func (suite *A10ServiceGroupTestSuite) TestUpdateA10ServiceGroupStatus_getFails() {
+	suite.testServer.AddRequest().
+		Response().
+		StatusCode(404)
+
+	err := suite.client.UpdateA10ServiceGroupStatus(&model.A10ServiceGroup{Name: "web", Namespace: "ingress"}, []*model.SyncResult{})
+	suite.Assert().NotNil(err)
+}
@@ -2,11 +2,18 @@ package apiserver
 
 import (
 	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	extensionsv1beta1 "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
@@ -16,12 +23,21 @@ import (
 type K8sClient interface {
 	GetNodes() ([]*model.Node, error)
 	GetConfigMap(namespace string, name string) (*model.ConfigMap, error)
+	SaveConfigMap(configMap *model.ConfigMap) error
 	GetIngressControllers() ([]*model.IngressController, error)
+	GetLoadBalancers() ([]*model.LoadBalancer, error)
+	UpdateLoadBalancerStatus(loadBalancer *model.LoadBalancer) error
+	GetPodNodeNames(namespace string, podSelectors map[string]string) ([]string, error)
+	GetReadyPodCounts(namespace string, podSelectors map[string]string) (map[string]int, error)
+	GetA10ServiceGroups() ([]*model.A10ServiceGroup, error)
+	UpdateA10ServiceGroupStatus(serviceGroup *model.A10ServiceGroup, results []*model.SyncResult) error
+	PublishSyncStatus(kind, namespace, name string, events []*model.SyncEvent, statuses map[string]*model.SyncStatus) error
 }
 
 type clientImpl struct {
 	corev1Impl            corev1.CoreV1Interface
 	extensionsv1beta1Impl extensionsv1beta1.ExtensionsV1beta1Interface
+	a10ServiceGroups      a10ServiceGroupClient
 }
 
 //New build new client
@@ -29,6 +45,9 @@ func newClient(clientset *kubernetes.Clientset) K8sClient {
 	return clientImpl{
 		corev1Impl:            clientset.CoreV1(),
 		extensionsv1beta1Impl: clientset.ExtensionsV1beta1(),
+		a10ServiceGroups: a10ServiceGroupClient{
+			restClient: clientset.CoreV1().RESTClient(),
+		},
 	}
 }
 
@@ -72,13 +91,39 @@ func (client clientImpl) GetConfigMap(namespace string, name string) (*model.Con
 	return config, err
 }
 
+//SaveConfigMap replaces data of the config map, the config map is created when it doesn't exist
+func (client clientImpl) SaveConfigMap(configMap *model.ConfigMap) error {
+	configMaps := client.corev1Impl.ConfigMaps(configMap.Namespace)
+	k8sConfigMap, err := configMaps.Get(configMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		k8sConfigMap = &v1.ConfigMap{}
+		k8sConfigMap.SetName(configMap.Name)
+		k8sConfigMap.SetNamespace(configMap.Namespace)
+		k8sConfigMap.Data = configMap.Data
+		_, err = configMaps.Create(k8sConfigMap)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	k8sConfigMap.Data = configMap.Data
+	_, err = configMaps.Update(k8sConfigMap)
+	return err
+}
+
 func (client clientImpl) GetIngressControllers() ([]*model.IngressController, error) {
 	var controllers []*model.IngressController
 	controllerList, err := client.extensionsv1beta1Impl.DaemonSets("ingress").List(metav1.ListOptions{})
 	if err == nil {
 		for _, controller := range controllerList.Items {
 			if strings.Contains(controller.GetName(), "ingress-controller") {
-				ingressController, err := buildIngressController(controller)
+				service, err := client.findControllerService(controller)
+				if err != nil {
+					glog.Errorf("Failed to find service of ingress controller %s. error: %s", controller.GetName(), err)
+					continue
+				}
+				ingressController, err := buildIngressController(controller, service)
 				if err != nil {
 					glog.Errorf("Failed to build ingress controller %s. error: %s", controller.GetName(), err)
 					continue
@@ -90,3 +135,151 @@ func (client clientImpl) GetIngressControllers() ([]*model.IngressController, er
 	}
 	return controllers, err
 }
+
+//findControllerService looks up the NodePort service selected by the a10.service annotation, nil when the annotation is not present
+func (client clientImpl) findControllerService(controller v1beta1.DaemonSet) (*v1.Service, error) {
+	name, exists := controller.Annotations[serviceAnnotation]
+	if !exists {
+		return nil, nil
+	}
+	service, err := client.corev1Impl.Services(controller.GetNamespace()).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if service.Spec.Type != v1.ServiceTypeNodePort && service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return nil, fmt.Errorf("Service %s/%s is of type %s, node ports are not allocated", service.GetNamespace(), service.GetName(), service.Spec.Type)
+	}
+	return service, nil
+}
+
+//GetLoadBalancers finds services of type LoadBalancer in all namespaces
+func (client clientImpl) GetLoadBalancers() ([]*model.LoadBalancer, error) {
+	var loadBalancers []*model.LoadBalancer
+	serviceList, err := client.corev1Impl.Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range serviceList.Items {
+		if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+			loadBalancers = append(loadBalancers, buildLoadBalancer(service))
+		}
+	}
+	return loadBalancers, nil
+}
+
+//UpdateLoadBalancerStatus writes the allocated vip to the load balancer status of the service
+func (client clientImpl) UpdateLoadBalancerStatus(loadBalancer *model.LoadBalancer) error {
+	services := client.corev1Impl.Services(loadBalancer.Namespace)
+	service, err := services.Get(loadBalancer.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	service.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{
+		v1.LoadBalancerIngress{IP: loadBalancer.VIP},
+	}
+	_, err = services.UpdateStatus(service)
+	return err
+}
+
+//GetPodNodeNames finds names of the nodes running pods matching the selectors
+func (client clientImpl) GetPodNodeNames(namespace string, podSelectors map[string]string) ([]string, error) {
+	podList, err := client.corev1Impl.Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(podSelectors).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != v1.PodRunning || len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		if !util.Contains(nodeNames, pod.Spec.NodeName) {
+			nodeNames = append(nodeNames, pod.Spec.NodeName)
+		}
+	}
+	return nodeNames, nil
+}
+
+//GetReadyPodCounts counts ready pods matching the selectors by name of the node they run on
+func (client clientImpl) GetReadyPodCounts(namespace string, podSelectors map[string]string) (map[string]int, error) {
+	podList, err := client.corev1Impl.Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(podSelectors).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 || !isPodReady(pod) {
+			continue
+		}
+		counts[pod.Spec.NodeName]++
+	}
+	return counts, nil
+}
+
+func isPodReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+//GetA10ServiceGroups finds A10ServiceGroup custom resources in all namespaces
+func (client clientImpl) GetA10ServiceGroups() ([]*model.A10ServiceGroup, error) {
+	list, err := client.a10ServiceGroups.list()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceGroups := make([]*model.A10ServiceGroup, 0)
+	for _, resource := range list.Items {
+		serviceGroup := buildA10ServiceGroup(resource)
+		if len(serviceGroup.Error) > 0 {
+			glog.Errorf("Invalid A10ServiceGroup %s/%s. error: %s", serviceGroup.Namespace, serviceGroup.Name, serviceGroup.Error)
+		}
+		serviceGroups = append(serviceGroups, serviceGroup)
+	}
+	return serviceGroups, nil
+}
+
+//UpdateA10ServiceGroupStatus writes sync results of the service group to the status of the A10ServiceGroup custom resource
+func (client clientImpl) UpdateA10ServiceGroupStatus(serviceGroup *model.A10ServiceGroup, results []*model.SyncResult) error {
+	resource, err := client.a10ServiceGroups.get(serviceGroup.Namespace, serviceGroup.Name)
+	if err != nil {
+		return err
+	}
+
+	applyA10ServiceGroupStatus(resource, serviceGroup, results)
+	return client.a10ServiceGroups.updateStatus(resource)
+}
+
+//PublishSyncStatus creates events for the kubernetes object and stores sync statuses of a10 instances in its annotation
+func (client clientImpl) PublishSyncStatus(kind, namespace, name string, events []*model.SyncEvent, statuses map[string]*model.SyncStatus) error {
+	target, err := client.getSyncTarget(kind, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for idx, syncEvent := range events {
+		event := buildEvent(target.reference, syncEvent, now, idx)
+		_, err = client.corev1Impl.Events(event.Namespace).Create(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	patch, err := buildSyncStatusPatch(target.annotations, statuses)
+	if err != nil || patch == nil {
+		return err
+	}
+	return target.patch(patch)
+}
@@ -2,6 +2,7 @@ package apiserver_test
 
 import (
 	"a10bridge/apiserver"
+	"a10bridge/model"
 	"a10bridge/util"
 	"errors"
 	"strconv"
@@ -11,6 +12,8 @@ import (
 
 	k8stesting "k8s.io/client-go/testing"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
@@ -49,10 +52,13 @@ func (suite *ClientTestSuite) TestGetNodes() {
 		"another": "test label",
 	}
 	defaultA10Server := expectedName
-	defaultWeight := "1"
 	node1 := corev1.Node{}
 	node1.SetLabels(expectedLabels)
 	node1.SetName(expectedName)
+	node1.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("7500m"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+	}
 	nodeList := corev1.NodeList{
 		Items: []corev1.Node{node1},
 	}
@@ -69,7 +75,54 @@ func (suite *ClientTestSuite) TestGetNodes() {
 	suite.Assert().Equal(expectedName, nodes[0].Name)
 	suite.Assert().Equal("10.10.10.1", nodes[0].IPAddress)
 	suite.Assert().Equal(defaultA10Server, nodes[0].A10Server)
-	suite.Assert().Equal(defaultWeight, nodes[0].Weight)
+	suite.Assert().Equal("", nodes[0].Weight, "weight without annotation is computed by the weight strategy")
+	suite.Assert().Equal(int64(7500), nodes[0].AllocatableCPU)
+	suite.Assert().Equal(int64(16<<30), nodes[0].AllocatableMemory)
+}
+
+func (suite *ClientTestSuite) TestGetNodes_serverAttributesFromAnnotations() {
+	suite.resolver.AddRecord("node1", "10.10.10.1")
+	suite.resolver.AddRecord("node2", "10.10.10.2")
+
+	node1 := corev1.Node{}
+	node1.SetName("node1")
+	node1.SetAnnotations(map[string]string{
+		"a10.server.conn_limit":  "1000",
+		"a10.server.slow_start":  "1",
+		"a10.server.description": "edge \"node\"",
+		"a10.server.template":    "k8s-nodes",
+		"a10.server.state":       "disable",
+		"a10.member.priority":    "16",
+	})
+	node2 := corev1.Node{}
+	node2.SetName("node2")
+	node2.SetAnnotations(map[string]string{
+		"a10.server.conn_limit": "0",
+		"a10.server.slow_start": "maybe",
+		"a10.server.state":      "maintenance",
+		"a10.member.priority":   "17",
+	})
+	nodeList := corev1.NodeList{
+		Items: []corev1.Node{node1, node2},
+	}
+
+	clientset := fake.NewSimpleClientset(&nodeList)
+	client := suite.helper.BuildClient(clientset)
+
+	nodes, err := client.GetNodes()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(nodes, 2)
+	suite.Assert().Equal(1000, nodes[0].ConnLimit)
+	suite.Assert().Equal("true", nodes[0].SlowStart)
+	suite.Assert().Equal("edge node", nodes[0].Description)
+	suite.Assert().Equal("k8s-nodes", nodes[0].Template)
+	suite.Assert().Equal(model.ServerStateDisable, nodes[0].State)
+	suite.Assert().Equal(16, nodes[0].MemberPriority)
+	suite.Assert().Equal(0, nodes[1].ConnLimit)
+	suite.Assert().Equal("", nodes[1].SlowStart)
+	suite.Assert().Equal("", nodes[1].State)
+	suite.Assert().Equal(0, nodes[1].MemberPriority)
 }
 
 func (suite *ClientTestSuite) TestGetNodes_serverNameFromAnnotation() {
@@ -106,7 +159,7 @@ func (suite *ClientTestSuite) TestGetNodes_serverWeightFromAnnotation() {
 	expectedName := "node1"
 	suite.resolver.AddRecord(expectedName, "10.10.10.1")
 
-	expectedWeight := "152"
+	expectedWeight := "52"
 	annotations := map[string]string{
 		"a10.server.weight": expectedWeight,
 	}
@@ -132,6 +185,46 @@ func (suite *ClientTestSuite) TestGetNodes_serverWeightFromAnnotation() {
 	suite.Assert().Equal(expectedWeight, nodes[0].Weight)
 }
 
+func (suite *ClientTestSuite) TestGetNodes_invalidServerWeightIsIgnored() {
+	suite.resolver.AddRecord("node1", "10.10.10.1")
+	node1 := corev1.Node{}
+	node1.SetAnnotations(map[string]string{"a10.server.weight": "152"})
+	node1.SetName("node1")
+	nodeList := corev1.NodeList{
+		Items: []corev1.Node{node1},
+	}
+
+	clientset := fake.NewSimpleClientset(&nodeList)
+	client := suite.helper.BuildClient(clientset)
+
+	nodes, err := client.GetNodes()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("", nodes[0].Weight)
+}
+
+func (suite *ClientTestSuite) TestGetNodes_dualStack() {
+	expectedName := "node1"
+	suite.resolver.AddRecord(expectedName, "fd00::1")
+	suite.resolver.AddRecord(expectedName, "10.10.10.1")
+
+	node1 := corev1.Node{}
+	node1.SetName(expectedName)
+	nodeList := corev1.NodeList{
+		Items: []corev1.Node{node1},
+	}
+
+	clientset := fake.NewSimpleClientset(&nodeList)
+	client := suite.helper.BuildClient(clientset)
+
+	nodes, err := client.GetNodes()
+
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(nodes)
+	suite.Assert().Equal("10.10.10.1", nodes[0].IPAddress)
+	suite.Assert().Equal("fd00::1", nodes[0].IPv6Address)
+}
+
 func (suite *ClientTestSuite) TestGetNodes_ipResolutionFails() {
 	nodeList := corev1.NodeList{Items: []corev1.Node{corev1.Node{}}}
 	clientset := fake.NewSimpleClientset(&nodeList)
@@ -196,6 +289,51 @@ func (suite *ClientTestSuite) TestGetConfigMap_apiCallFails() {
 	suite.Assert().Nil(configMap)
 }
 
+func (suite *ClientTestSuite) TestSaveConfigMap_create() {
+	clientset := fake.NewSimpleClientset()
+	client := suite.helper.BuildClient(clientset)
+	err := client.SaveConfigMap(&model.ConfigMap{
+		Name:      "state",
+		Namespace: "ingress",
+		Data:      map[string]string{"key": "value"},
+	})
+	suite.Assert().Nil(err)
+
+	configMap, err := clientset.CoreV1().ConfigMaps("ingress").Get("state", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]string{"key": "value"}, configMap.Data)
+}
+
+func (suite *ClientTestSuite) TestSaveConfigMap_update() {
+	existing := corev1.ConfigMap{}
+	existing.SetName("state")
+	existing.SetNamespace("ingress")
+	existing.Data = map[string]string{"old": "value"}
+	clientset := fake.NewSimpleClientset(&existing)
+	client := suite.helper.BuildClient(clientset)
+	err := client.SaveConfigMap(&model.ConfigMap{
+		Name:      "state",
+		Namespace: "ingress",
+		Data:      map[string]string{"key": "value"},
+	})
+	suite.Assert().Nil(err)
+
+	configMap, err := clientset.CoreV1().ConfigMaps("ingress").Get("state", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]string{"key": "value"}, configMap.Data)
+}
+
+func (suite *ClientTestSuite) TestSaveConfigMap_apiCallFails() {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("*", "*", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, errors.New("fail")
+	})
+	client := suite.helper.BuildClient(clientset)
+	err := client.SaveConfigMap(&model.ConfigMap{Name: "state", Namespace: "ingress"})
+
+	suite.Assert().NotNil(err)
+}
+
 func (suite *ClientTestSuite) TestGetIngressControllers() {
 	expectedName := "test-ingress-controller-80"
 	expectedNodeSelector := map[string]string{
@@ -600,3 +738,432 @@ func (suite *ClientTestSuite) TestGetIngressControllers_noNameMatchesIngressCont
 	suite.Assert().Nil(err)
 	suite.Assert().Equal(0, len(controllers))
 }
+
+func (suite *ClientTestSuite) TestGetIngressControllers_additionalPorts() {
+	livenessProbe := corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/health",
+				Port: intstr.IntOrString{IntVal: 10254},
+			},
+		},
+		PeriodSeconds:    15,
+		FailureThreshold: 5,
+		SuccessThreshold: 3,
+		TimeoutSeconds:   10,
+	}
+
+	daemonSet1 := extensionsv1beta1.DaemonSet{}
+	daemonSet1.SetName("nginx-ingress-controller")
+	daemonSet1.SetNamespace("ingress")
+	daemonSet1.SetAnnotations(map[string]string{
+		"a10.service_group":              "web-{{.PortName}}",
+		"a10.service_group.https":        "web-{{.PortName}}",
+		"a10.service_group.proxy":        "proxy",
+		"a10.health.endpoint.proxy":      "/proxy-health",
+		"a10.health.port.proxy":          "10255",
+		"a10.health.endpoint.notdeclare": "/ignored",
+	})
+	daemonSet1.Spec.Template.Spec.Containers = append(daemonSet1.Spec.Template.Spec.Containers, corev1.Container{
+		Ports: []corev1.ContainerPort{
+			corev1.ContainerPort{Name: "http", HostPort: 80, ContainerPort: 80},
+			corev1.ContainerPort{Name: "https", HostPort: 443, ContainerPort: 443},
+			corev1.ContainerPort{Name: "proxy", HostPort: 8443, ContainerPort: 8443},
+			corev1.ContainerPort{Name: "metrics", ContainerPort: 10254},
+		},
+		LivenessProbe: &livenessProbe,
+	})
+	daemonSetList := extensionsv1beta1.DaemonSetList{
+		Items: []extensionsv1beta1.DaemonSet{daemonSet1},
+	}
+	clientset := fake.NewSimpleClientset(&daemonSetList)
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 1)
+	controller := controllers[0]
+	suite.Assert().Equal(80, controller.Port)
+	suite.Assert().Equal("http", controller.PortName)
+	suite.Assert().Len(controller.Ports, 2)
+
+	suite.Assert().Equal("https", controller.Ports[0].Name)
+	suite.Assert().Equal(443, controller.Ports[0].Port)
+	suite.Assert().Equal("web-{{.PortName}}", controller.Ports[0].ServiceGroupNameTemplate)
+	suite.Assert().Equal(controller.Health, controller.Ports[0].Health)
+
+	suite.Assert().Equal("proxy", controller.Ports[1].Name)
+	suite.Assert().Equal(8443, controller.Ports[1].Port)
+	suite.Assert().Equal("/proxy-health", controller.Ports[1].Health.Endpoint)
+	suite.Assert().Equal(10255, controller.Ports[1].Health.Port)
+	suite.Assert().Equal("/health", controller.Health.Endpoint)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_instances() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "web",
+		"a10.instances":     "lb01, lb02,",
+	})
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1}})
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 1)
+	suite.Assert().Equal([]string{"lb01", "lb02"}, controllers[0].A10Instances)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_serviceGroupOptions() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group":      "web",
+		"a10.lb_method":          "least-connection",
+		"a10.protocol":           "UDP",
+		"a10.min_active_members": "2",
+		"a10.member.priority":    "8",
+	})
+	daemonSet2 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "api",
+		"a10.protocol":      "sctp",
+	})
+	daemonSet2.SetName("api")
+	daemonSet3 := portSourceDaemonSet(map[string]string{
+		"a10.service_group":   "backup",
+		"a10.member.priority": "0",
+	})
+	daemonSet3.SetName("backup")
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1, daemonSet2, daemonSet3}})
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 1)
+	suite.Assert().Equal("least-connection", controllers[0].Method)
+	suite.Assert().Equal(model.ProtocolUDP, controllers[0].Protocol)
+	suite.Assert().Equal(2, controllers[0].MinActiveMembers)
+	suite.Assert().Equal(8, controllers[0].MemberPriority)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_hostNetwork() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group":       "web",
+		"a10.service_group.https": "web-https",
+	})
+	daemonSet1.Spec.Template.Spec.HostNetwork = true
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1}})
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 1)
+	suite.Assert().Equal(8080, controllers[0].Port)
+	suite.Assert().Equal(model.PortSourceHostNetwork, controllers[0].PortSource)
+	suite.Assert().Equal(8443, controllers[0].Ports[0].Port)
+	suite.Assert().Equal(model.PortSourceHostNetwork, controllers[0].Ports[0].Source)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_nodePortService() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group":       "web",
+		"a10.service_group.https": "web-https",
+		"a10.service":             "ingress-nodeport",
+	})
+	service := corev1.Service{}
+	service.SetName("ingress-nodeport")
+	service.SetNamespace("ingress")
+	service.Spec.Type = corev1.ServiceTypeNodePort
+	service.Spec.Ports = []corev1.ServicePort{
+		corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), NodePort: 30080},
+		corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromString("https"), NodePort: 30443},
+	}
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1}}, &service)
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 1)
+	suite.Assert().Equal(30080, controllers[0].Port)
+	suite.Assert().Equal(model.PortSourceNodePort, controllers[0].PortSource)
+	suite.Assert().Equal(30443, controllers[0].Ports[0].Port)
+	suite.Assert().Equal(model.PortSourceNodePort, controllers[0].Ports[0].Source)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_nodePortServiceIssues() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "web",
+		"a10.service":       "ingress-clusterip",
+	})
+	daemonSet2 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "web",
+		"a10.service":       "missing",
+	})
+	daemonSet2.SetName("other-ingress-controller")
+	daemonSet3 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "web",
+		"a10.service":       "ingress-nodeport",
+	})
+	daemonSet3.SetName("third-ingress-controller")
+	clusterIP := corev1.Service{}
+	clusterIP.SetName("ingress-clusterip")
+	clusterIP.SetNamespace("ingress")
+	clusterIP.Spec.Type = corev1.ServiceTypeClusterIP
+	nodePort := corev1.Service{}
+	nodePort.SetName("ingress-nodeport")
+	nodePort.SetNamespace("ingress")
+	nodePort.Spec.Type = corev1.ServiceTypeNodePort
+	nodePort.Spec.Ports = []corev1.ServicePort{
+		corev1.ServicePort{Name: "metrics", Port: 10254, TargetPort: intstr.FromInt(10254), NodePort: 30254},
+	}
+	daemonSets := &extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1, daemonSet2, daemonSet3}}
+	clientset := fake.NewSimpleClientset(daemonSets, &clusterIP, &nodePort)
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Empty(controllers)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_missingHostPort() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{
+		"a10.service_group": "web",
+	})
+	daemonSet1.Spec.Template.Spec.Containers[0].Ports[0].HostPort = 0
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1}})
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Empty(controllers)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_additionalPortNotFound() {
+	livenessProbe := corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/health",
+				Port: intstr.IntOrString{IntVal: 10254},
+			},
+		},
+	}
+
+	daemonSet1 := extensionsv1beta1.DaemonSet{}
+	daemonSet1.SetName("nginx-ingress-controller")
+	daemonSet1.SetNamespace("ingress")
+	daemonSet1.SetAnnotations(map[string]string{
+		"a10.service_group":       "web",
+		"a10.service_group.https": "web-https",
+	})
+	daemonSet1.Spec.Template.Spec.Containers = append(daemonSet1.Spec.Template.Spec.Containers, corev1.Container{
+		Ports: []corev1.ContainerPort{
+			corev1.ContainerPort{Name: "http", HostPort: 80, ContainerPort: 80},
+		},
+		LivenessProbe: &livenessProbe,
+	})
+	daemonSetList := extensionsv1beta1.DaemonSetList{
+		Items: []extensionsv1beta1.DaemonSet{daemonSet1},
+	}
+	clientset := fake.NewSimpleClientset(&daemonSetList)
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(0, len(controllers))
+}
+
+func (suite *ClientTestSuite) TestGetLoadBalancers() {
+	loadBalancerService := corev1.Service{}
+	loadBalancerService.SetName("web")
+	loadBalancerService.SetNamespace("default")
+	loadBalancerService.Spec.Type = corev1.ServiceTypeLoadBalancer
+	loadBalancerService.Spec.LoadBalancerIP = "10.0.0.5"
+	loadBalancerService.Spec.Ports = []corev1.ServicePort{
+		corev1.ServicePort{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+	}
+	loadBalancerService.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+		corev1.LoadBalancerIngress{IP: "10.0.0.5"},
+	}
+	clusterIPService := corev1.Service{}
+	clusterIPService.SetName("internal")
+	clusterIPService.SetNamespace("default")
+	clusterIPService.Spec.Type = corev1.ServiceTypeClusterIP
+	serviceList := corev1.ServiceList{
+		Items: []corev1.Service{loadBalancerService, clusterIPService},
+	}
+
+	clientset := fake.NewSimpleClientset(&serviceList)
+	client := suite.helper.BuildClient(clientset)
+	loadBalancers, err := client.GetLoadBalancers()
+	suite.Assert().Nil(err)
+	suite.Assert().Len(loadBalancers, 1)
+	suite.Assert().Equal("web", loadBalancers[0].Name)
+	suite.Assert().Equal("default", loadBalancers[0].Namespace)
+	suite.Assert().Equal("10.0.0.5", loadBalancers[0].RequestedIP)
+	suite.Assert().Equal("10.0.0.5", loadBalancers[0].VIP)
+	suite.Assert().Len(loadBalancers[0].Ports, 1)
+	suite.Assert().Equal("tcp", loadBalancers[0].Ports[0].Protocol)
+	suite.Assert().Equal(80, loadBalancers[0].Ports[0].Port)
+	suite.Assert().Equal(30080, loadBalancers[0].Ports[0].NodePort)
+}
+
+func (suite *ClientTestSuite) TestGetLoadBalancers_apiCallFails() {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("*", "*", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, errors.New("fail")
+	})
+	client := suite.helper.BuildClient(clientset)
+	loadBalancers, err := client.GetLoadBalancers()
+
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(loadBalancers)
+}
+
+func (suite *ClientTestSuite) TestUpdateLoadBalancerStatus() {
+	service := corev1.Service{}
+	service.SetName("web")
+	service.SetNamespace("default")
+	service.Spec.Type = corev1.ServiceTypeLoadBalancer
+
+	clientset := fake.NewSimpleClientset(&service)
+	client := suite.helper.BuildClient(clientset)
+	err := client.UpdateLoadBalancerStatus(&model.LoadBalancer{Name: "web", Namespace: "default", VIP: "10.0.0.7"})
+	suite.Assert().Nil(err)
+
+	loadBalancers, err := client.GetLoadBalancers()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("10.0.0.7", loadBalancers[0].VIP)
+}
+
+func (suite *ClientTestSuite) TestUpdateLoadBalancerStatus_serviceNotFound() {
+	clientset := fake.NewSimpleClientset()
+	client := suite.helper.BuildClient(clientset)
+	err := client.UpdateLoadBalancerStatus(&model.LoadBalancer{Name: "web", Namespace: "default", VIP: "10.0.0.7"})
+	suite.Assert().NotNil(err)
+}
+
+func (suite *ClientTestSuite) TestGetPodNodeNames() {
+	pod := func(name, nodeName string, phase corev1.PodPhase, labels map[string]string) corev1.Pod {
+		pod := corev1.Pod{}
+		pod.SetName(name)
+		pod.SetNamespace("ingress")
+		pod.SetLabels(labels)
+		pod.Spec.NodeName = nodeName
+		pod.Status.Phase = phase
+		return pod
+	}
+	podList := corev1.PodList{
+		Items: []corev1.Pod{
+			pod("web1", "node1", corev1.PodRunning, map[string]string{"app": "web"}),
+			pod("web2", "node1", corev1.PodRunning, map[string]string{"app": "web"}),
+			pod("web3", "node2", corev1.PodRunning, map[string]string{"app": "web"}),
+			pod("web4", "node3", corev1.PodPending, map[string]string{"app": "web"}),
+			pod("other", "node4", corev1.PodRunning, map[string]string{"app": "other"}),
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(&podList)
+	client := suite.helper.BuildClient(clientset)
+	nodeNames, err := client.GetPodNodeNames("ingress", map[string]string{"app": "web"})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]string{"node1", "node2"}, nodeNames)
+}
+
+func (suite *ClientTestSuite) TestGetPodNodeNames_apiCallFails() {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("*", "*", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, errors.New("fail")
+	})
+	client := suite.helper.BuildClient(clientset)
+	nodeNames, err := client.GetPodNodeNames("ingress", map[string]string{"app": "web"})
+
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(nodeNames)
+}
+
+func (suite *ClientTestSuite) TestGetReadyPodCounts() {
+	pod := func(name, nodeName string, ready corev1.ConditionStatus, labels map[string]string) corev1.Pod {
+		pod := corev1.Pod{}
+		pod.SetName(name)
+		pod.SetNamespace("ingress")
+		pod.SetLabels(labels)
+		pod.Spec.NodeName = nodeName
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{
+			corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			corev1.PodCondition{Type: corev1.PodReady, Status: ready},
+		}
+		return pod
+	}
+	pending := corev1.Pod{}
+	pending.SetName("web5")
+	pending.SetNamespace("ingress")
+	pending.SetLabels(map[string]string{"app": "web"})
+	podList := corev1.PodList{
+		Items: []corev1.Pod{
+			pod("web1", "node1", corev1.ConditionTrue, map[string]string{"app": "web"}),
+			pod("web2", "node1", corev1.ConditionTrue, map[string]string{"app": "web"}),
+			pod("web3", "node2", corev1.ConditionTrue, map[string]string{"app": "web"}),
+			pod("web4", "node3", corev1.ConditionFalse, map[string]string{"app": "web"}),
+			pod("other", "node4", corev1.ConditionTrue, map[string]string{"app": "other"}),
+			pending,
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(&podList)
+	client := suite.helper.BuildClient(clientset)
+	counts, err := client.GetReadyPodCounts("ingress", map[string]string{"app": "web"})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]int{"node1": 2, "node2": 1}, counts)
+}
+
+func (suite *ClientTestSuite) TestGetIngressControllers_podSelectors() {
+	daemonSet1 := portSourceDaemonSet(map[string]string{"a10.service_group": "web"})
+	daemonSet1.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	daemonSet2 := portSourceDaemonSet(map[string]string{"a10.service_group": "api"})
+	daemonSet2.SetName("api-ingress-controller")
+	daemonSet2.Spec.Template.SetLabels(map[string]string{"app": "api", "tier": "edge"})
+	clientset := fake.NewSimpleClientset(&extensionsv1beta1.DaemonSetList{Items: []extensionsv1beta1.DaemonSet{daemonSet1, daemonSet2}})
+	client := suite.helper.BuildClient(clientset)
+
+	controllers, err := client.GetIngressControllers()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(controllers, 2)
+	selectors := map[string]map[string]string{}
+	for _, controller := range controllers {
+		selectors[controller.Name] = controller.PodSelectors
+	}
+	suite.Assert().Equal(map[string]string{"app": "web"}, selectors["nginx-ingress-controller"])
+	suite.Assert().Equal(map[string]string{"app": "api", "tier": "edge"}, selectors["api-ingress-controller"])
+}
+
+func portSourceDaemonSet(annotations map[string]string) extensionsv1beta1.DaemonSet {
+	daemonSet := extensionsv1beta1.DaemonSet{}
+	daemonSet.SetName("nginx-ingress-controller")
+	daemonSet.SetNamespace("ingress")
+	daemonSet.SetAnnotations(annotations)
+	daemonSet.Spec.Template.Spec.Containers = []corev1.Container{
+		corev1.Container{
+			Ports: []corev1.ContainerPort{
+				corev1.ContainerPort{Name: "http", HostPort: 80, ContainerPort: 8080},
+				corev1.ContainerPort{Name: "https", ContainerPort: 8443},
+				corev1.ContainerPort{Name: "metrics", ContainerPort: 10254},
+			},
+			LivenessProbe: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/health",
+						Port: intstr.FromInt(10254),
+					},
+				},
+			},
+		},
+	}
+	return daemonSet
+}
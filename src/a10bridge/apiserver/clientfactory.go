@@ -15,30 +15,63 @@ import (
 var restInClusterConfig = rest.InClusterConfig
 var clientcmdBuildConfigFromFlags = clientcmd.BuildConfigFromFlags
 var kubernetesNewForConfig = kubernetes.NewForConfig
+var clientcmdBuildContextConfig = buildContextConfig
+
+//ClientConfig selects the apiserver the client talks to, without any setting the in-cluster config
+//is tried first and $HOME/.kube/config second
+type ClientConfig struct {
+	Kubeconfig string
+	Context    string
+	Master     string
+	//InCluster only the in-cluster config is used, there is no fallback to kubeconfig files
+	InCluster bool
+}
+
+func (clientConfig ClientConfig) explicit() bool {
+	return len(clientConfig.Kubeconfig) > 0 || len(clientConfig.Context) > 0 || len(clientConfig.Master) > 0
+}
 
 //CreateClient creates kubernetes apiserver client
-func CreateClient() (K8sClient, error) {
+func CreateClient(clientConfig ClientConfig) (K8sClient, error) {
 	if fakeClient != nil {
 		return fakeClient, nil
 	}
 
+	if clientConfig.InCluster {
+		client, err := createInClusterClient()
+		if err != nil {
+			glog.Errorf("Failed to create in cluster client. Error: %v", err)
+			return nil, err
+		}
+		return client, nil
+	}
+
+	if clientConfig.explicit() {
+		return createConfiguredClient(clientConfig.Kubeconfig, clientConfig.Context, clientConfig.Master)
+	}
+
 	//assume we are running inside the pod, if we fail lets try to build kubectl client
-	config, err := restInClusterConfig()
+	client, err := createInClusterClient()
 	if err != nil {
-		glog.Warningf("Failed to create in cluster config. Error: %v", err)
+		glog.Warningf("Failed to create in cluster client. Error: %v", err)
 		return createKubectlClient()
 	}
+	return client, nil
+}
+
+func createInClusterClient() (K8sClient, error) {
+	config, err := restInClusterConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	clientset, err := kubernetesNewForConfig(config)
 	if err != nil {
-		glog.Warningf("Failed to create in cluster client. Error: %v", err)
-		return createKubectlClient()
+		return nil, err
 	}
 
-	client := newClient(clientset)
 	glog.Info("Created in-cluser client")
-
-	return client, err
+	return newClient(clientset), nil
 }
 
 func createKubectlClient() (K8sClient, error) {
@@ -65,6 +98,41 @@ func createKubectlClient() (K8sClient, error) {
 	return client, nil
 }
 
+//CreateClusterClient creates kubernetes apiserver client for the context of the kubeconfig file,
+//the default kubeconfig loading rules and the current context are used when they are empty
+func CreateClusterClient(kubeconfigPath, context string) (K8sClient, error) {
+	if fakeClient != nil {
+		return fakeClient, nil
+	}
+	return createConfiguredClient(kubeconfigPath, context, "")
+}
+
+func createConfiguredClient(kubeconfigPath, context, master string) (K8sClient, error) {
+	config, err := clientcmdBuildContextConfig(kubeconfigPath, context, master)
+	if err != nil {
+		glog.Errorf("Failed to create client config for context '%s' of kubeconfig '%s'. Error: %v", context, kubeconfigPath, err)
+		return nil, err
+	}
+
+	clientset, err := kubernetesNewForConfig(config)
+	if err != nil {
+		glog.Errorf("Failed to create client for context '%s' of kubeconfig '%s'. Error: %v", context, kubeconfigPath, err)
+		return nil, err
+	}
+
+	glog.Infof("Created client for context '%s' of kubeconfig '%s'", context, kubeconfigPath)
+	return newClient(clientset), nil
+}
+
+//buildContextConfig loads client config of the kubeconfig context, the master overrides the server of the context when set
+func buildContextConfig(kubeconfigPath, context, master string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	overrides.ClusterInfo.Server = master
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
 var fakeClient K8sClient
 
 func InjectFakeClient(clientset *fake.Clientset) {
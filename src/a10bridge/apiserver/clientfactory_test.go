@@ -34,7 +34,7 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inCluster() {
 	})
 	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
 
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(client)
 }
@@ -53,7 +53,7 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterConfigCreationFai
 	})
 	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
 
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(client)
 }
@@ -77,7 +77,7 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterClientSetCreation
 	})
 	defer suite.helper.SetClientcmdBuildConfigFromFlagsFunc(originalClientcmdBuildConfigFromFlags)
 
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(client)
 }
@@ -92,7 +92,7 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterFailsKubectlClien
 	})
 	defer suite.helper.SetClientcmdBuildConfigFromFlagsFunc(originalClientcmdBuildConfigFromFlags)
 
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
 	suite.Assert().NotNil(err)
 	suite.Assert().Nil(client)
 }
@@ -111,7 +111,119 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterFailsKubectlConfi
 	})
 	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
 
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(client)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterOnlyFails() {
+	originalRestInClusterConfig := suite.helper.SetRestInClusterConfig(func() (*rest.Config, error) {
+		return nil, errors.New("not running in a pod")
+	})
+	defer suite.helper.SetRestInClusterConfig(originalRestInClusterConfig)
+	originalClientcmdBuildConfigFromFlags := suite.helper.SetClientcmdBuildConfigFromFlagsFunc(func(masterUrl, kubeconfigPath string) (*rest.Config, error) {
+		suite.Fail("kubectl config should not be tried in in-cluster only mode")
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetClientcmdBuildConfigFromFlagsFunc(originalClientcmdBuildConfigFromFlags)
+
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{InCluster: true})
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(client)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterOnly() {
+	originalRestInClusterConfig := suite.helper.SetRestInClusterConfig(func() (*rest.Config, error) {
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetRestInClusterConfig(originalRestInClusterConfig)
+	originalKubernetesNewForConfig := suite.helper.SetKubernetesNewForConfigFunc(func(c *rest.Config) (*kubernetes.Clientset, error) {
+		return &kubernetes.Clientset{}, nil
+	})
+	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
+
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{InCluster: true})
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(client)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClient_explicitKubeconfig() {
+	originalRestInClusterConfig := suite.helper.SetRestInClusterConfig(func() (*rest.Config, error) {
+		suite.Fail("in cluster config should not be tried when kubeconfig is set")
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetRestInClusterConfig(originalRestInClusterConfig)
+	var kubeconfigPath, context, master string
+	originalBuildContextConfig := suite.helper.SetClientcmdBuildContextConfigFunc(func(path, ctx, server string) (*rest.Config, error) {
+		kubeconfigPath, context, master = path, ctx, server
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetClientcmdBuildContextConfigFunc(originalBuildContextConfig)
+	originalKubernetesNewForConfig := suite.helper.SetKubernetesNewForConfigFunc(func(c *rest.Config) (*kubernetes.Clientset, error) {
+		return &kubernetes.Clientset{}, nil
+	})
+	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
+
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{Kubeconfig: "/tmp/kubeconfig", Context: "dev", Master: "https://10.0.0.1:6443"})
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(client)
+	suite.Assert().Equal("/tmp/kubeconfig", kubeconfigPath)
+	suite.Assert().Equal("dev", context)
+	suite.Assert().Equal("https://10.0.0.1:6443", master)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClient_explicitKubeconfigFails() {
+	originalBuildContextConfig := suite.helper.SetClientcmdBuildContextConfigFunc(func(path, ctx, server string) (*rest.Config, error) {
+		return nil, errors.New("context not found")
+	})
+	defer suite.helper.SetClientcmdBuildContextConfigFunc(originalBuildContextConfig)
+
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{Context: "missing"})
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(client)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClusterClient() {
+	var kubeconfigPath, context string
+	originalBuildContextConfig := suite.helper.SetClientcmdBuildContextConfigFunc(func(path, ctx, master string) (*rest.Config, error) {
+		kubeconfigPath, context = path, ctx
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetClientcmdBuildContextConfigFunc(originalBuildContextConfig)
+	originalKubernetesNewForConfig := suite.helper.SetKubernetesNewForConfigFunc(func(c *rest.Config) (*kubernetes.Clientset, error) {
+		return &kubernetes.Clientset{}, nil
+	})
+	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
+
+	client, err := apiserver.CreateClusterClient("/etc/kube/clusters", "lga-k8s2")
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(client)
+	suite.Assert().Equal("/etc/kube/clusters", kubeconfigPath)
+	suite.Assert().Equal("lga-k8s2", context)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClusterClient_configCreationFails() {
+	originalBuildContextConfig := suite.helper.SetClientcmdBuildContextConfigFunc(func(path, ctx, master string) (*rest.Config, error) {
+		return nil, errors.New("context not found")
+	})
+	defer suite.helper.SetClientcmdBuildContextConfigFunc(originalBuildContextConfig)
+
+	client, err := apiserver.CreateClusterClient("/etc/kube/clusters", "missing")
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(client)
+}
+
+func (suite *ClientFactoryTestSuite) TestCreateClusterClient_clientSetCreationFails() {
+	originalBuildContextConfig := suite.helper.SetClientcmdBuildContextConfigFunc(func(path, ctx, master string) (*rest.Config, error) {
+		return &rest.Config{}, nil
+	})
+	defer suite.helper.SetClientcmdBuildContextConfigFunc(originalBuildContextConfig)
+	originalKubernetesNewForConfig := suite.helper.SetKubernetesNewForConfigFunc(func(c *rest.Config) (*kubernetes.Clientset, error) {
+		return nil, errors.New("fail")
+	})
+	defer suite.helper.SetKubernetesNewForConfigFunc(originalKubernetesNewForConfig)
+
+	client, err := apiserver.CreateClusterClient("", "lga-k8s2")
 	suite.Assert().NotNil(err)
 	suite.Assert().Nil(client)
 }
@@ -119,7 +231,7 @@ func (suite *ClientFactoryTestSuite) TestCreateClient_inClusterFailsKubectlConfi
 func (suite *ClientFactoryTestSuite) TestFakeClientInjection() {
 	fakeClientSet := fake.NewSimpleClientset()
 	apiserver.InjectFakeClient(fakeClientSet)
-	client, err := apiserver.CreateClient()
+	client, err := apiserver.CreateClient(apiserver.ClientConfig{})
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(client)
 }
@@ -11,22 +11,16 @@ import (
 )
 
 func buildHealthCheck(controllerDaemonSet v1beta1.DaemonSet, mainContainer *v1.Container) (*model.HealthCheck, error) {
-	var port int
-	var err error
-
-	endpoint, endpointFound := controllerDaemonSet.Annotations["a10.health.endpoint"]
+	endpoint, endpointFound := controllerDaemonSet.Annotations[healthEndpointAnnotation]
 	if !endpointFound {
 		glog.Infof("health endpoint annotation not found for ingress controller %s, going to use liveness probe", controllerDaemonSet.GetName())
 	}
-	portStr, portFound := controllerDaemonSet.Annotations["a10.health.port"]
+	port, portFound, err := parseHealthPort(controllerDaemonSet.Annotations, healthPortAnnotation)
 	if !portFound {
 		glog.Infof("health port annotation not found for ingress controller %s, going to use liveness probe", controllerDaemonSet.GetName())
-	} else {
-		port, err = strconv.Atoi(portStr)
-		if err != nil {
-			glog.Warningf("Failed to convert port annotation %s to int value with error %s, going to use liveness probe", portStr, err)
-			portFound = false
-		}
+	} else if err != nil {
+		glog.Warningf("Failed to read port annotation with error %s, going to use liveness probe", err)
+		portFound = false
 	}
 
 	livenessProbe := mainContainer.LivenessProbe
@@ -51,3 +45,20 @@ func buildHealthCheck(controllerDaemonSet v1beta1.DaemonSet, mainContainer *v1.C
 		ExpectCode:                "200",
 	}, nil
 }
+
+//parseHealthPort reads the health port annotation, found is false when the annotation is not present
+func parseHealthPort(annotations map[string]string, key string) (port int, found bool, err error) {
+	portStr, found := annotations[key]
+	if !found {
+		return 0, false, nil
+	}
+
+	port, err = strconv.Atoi(portStr)
+	if err != nil {
+		return 0, true, fmt.Errorf("'%s' is not a number", portStr)
+	}
+	if port < 1 || port > 65535 {
+		return 0, true, fmt.Errorf("%d is not a valid port", port)
+	}
+	return port, true, nil
+}
@@ -10,6 +10,7 @@ type TestHelper struct{}
 type RestInClusterConfigFunc func() (*rest.Config, error)
 type ClientcmdBuildConfigFromFlagsFunc func(masterUrl, kubeconfigPath string) (*rest.Config, error)
 type KubernetesNewForConfigFunc func(c *rest.Config) (*kubernetes.Clientset, error)
+type ClientcmdBuildContextConfigFunc func(kubeconfigPath, context, master string) (*rest.Config, error)
 
 func (helper *TestHelper) BuildClient(clientset *fake.Clientset) K8sClient {
 	return clientImpl{
@@ -18,6 +19,16 @@ func (helper *TestHelper) BuildClient(clientset *fake.Clientset) K8sClient {
 	}
 }
 
+func (helper *TestHelper) BuildClientWithRestClient(clientset *fake.Clientset, restClient rest.Interface) K8sClient {
+	return clientImpl{
+		corev1Impl:            clientset.CoreV1(),
+		extensionsv1beta1Impl: clientset.ExtensionsV1beta1(),
+		a10ServiceGroups: a10ServiceGroupClient{
+			restClient: restClient,
+		},
+	}
+}
+
 func (helper TestHelper) SetRestInClusterConfig(inClusterConfigFunc RestInClusterConfigFunc) RestInClusterConfigFunc {
 	old := restInClusterConfig
 	restInClusterConfig = inClusterConfigFunc
@@ -35,3 +46,9 @@ func (helper TestHelper) SetKubernetesNewForConfigFunc(newForConfigFunc Kubernet
 	kubernetesNewForConfig = newForConfigFunc
 	return old
 }
+
+func (helper TestHelper) SetClientcmdBuildContextConfigFunc(buildContextConfigFunc ClientcmdBuildContextConfigFunc) ClientcmdBuildContextConfigFunc {
+	old := clientcmdBuildContextConfig
+	clientcmdBuildContextConfig = buildContextConfigFunc
+	return old
+}
@@ -2,15 +2,28 @@ package apiserver
 
 import (
 	"a10bridge/model"
+	"a10bridge/util"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 )
 
-func buildIngressController(controller v1beta1.DaemonSet) (*model.IngressController, error) {
-	serviceGroup, exists := controller.Annotations["a10.service_group"]
+const (
+	serviceGroupAnnotation     = "a10.service_group"
+	healthEndpointAnnotation   = "a10.health.endpoint"
+	healthPortAnnotation       = "a10.health.port"
+	instancesAnnotation        = "a10.instances"
+	lbMethodAnnotation         = "a10.lb_method"
+	protocolAnnotation         = "a10.protocol"
+	minActiveMembersAnnotation = "a10.min_active_members"
+)
+
+func buildIngressController(controller v1beta1.DaemonSet, service *v1.Service) (*model.IngressController, error) {
+	serviceGroup, exists := controller.Annotations[serviceGroupAnnotation]
 	if !exists {
 		return nil, fmt.Errorf("Missing service group name tamplate on ingress controller %s", controller.GetName())
 	}
@@ -25,16 +38,107 @@ func buildIngressController(controller v1beta1.DaemonSet) (*model.IngressControl
 		return nil, fmt.Errorf("Failed to build health check for ingress controller %s", controller.GetName())
 	}
 
+	resolver := newPortResolver(controller, service)
+	port, portSource, err := resolver.resolve(httpPort)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve port of ingress controller %s. error: %s", controller.GetName(), err)
+	}
+
+	ports, err := buildControllerPorts(controller, mainContainer, healthCheck, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := parseServiceGroupOptions(controller.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid service group options on ingress controller %s. error: %s", controller.GetName(), err)
+	}
+
 	return &model.IngressController{
-		Name:          controller.GetName(),
-		NodeSelectors: controller.Spec.Template.Spec.NodeSelector,
-		Health:        healthCheck,
-		Port:          httpPort,
+		Name:                     controller.GetName(),
+		Namespace:                controller.GetNamespace(),
+		Labels:                   controller.GetLabels(),
+		Annotations:              controller.GetAnnotations(),
+		NodeSelectors:            controller.Spec.Template.Spec.NodeSelector,
+		PodSelectors:             podSelectors(controller),
+		A10Instances:             parseInstances(controller.Annotations),
+		Health:                   healthCheck,
+		Port:                     port,
+		PortName:                 httpPort.Name,
+		PortSource:               portSource,
+		Ports:                    ports,
 		ServiceGroupNameTemplate: serviceGroup,
+		Method:                   options.Method,
+		Protocol:                 options.Protocol,
+		MinActiveMembers:         options.MinActiveMembers,
+		MemberPriority:           options.MemberPriority,
 	}, err
 }
 
-func findMainContainer(containers []v1.Container) (*v1.Container, int) {
+//podSelectors labels selecting pods of the daemon set, labels of the pod template when the selector isn't set
+func podSelectors(controller v1beta1.DaemonSet) map[string]string {
+	if controller.Spec.Selector != nil && len(controller.Spec.Selector.MatchLabels) > 0 {
+		return controller.Spec.Selector.MatchLabels
+	}
+	return controller.Spec.Template.GetLabels()
+}
+
+//serviceGroupOptions service group settings read from a10.lb_method, a10.protocol, a10.min_active_members
+//and a10.member.priority annotations
+type serviceGroupOptions struct {
+	Method           string
+	Protocol         string
+	MinActiveMembers int
+	MemberPriority   int
+}
+
+func parseServiceGroupOptions(annotations map[string]string) (serviceGroupOptions, error) {
+	options := serviceGroupOptions{
+		Method:   strings.TrimSpace(annotations[lbMethodAnnotation]),
+		Protocol: strings.ToLower(strings.TrimSpace(annotations[protocolAnnotation])),
+	}
+	if len(options.Method) > 0 && !util.Contains(model.LBMethods, options.Method) {
+		return options, fmt.Errorf("%s '%s' is not supported, use one of %s", lbMethodAnnotation, options.Method, strings.Join(model.LBMethods, ", "))
+	}
+	if len(options.Protocol) > 0 && !util.Contains(model.Protocols, options.Protocol) {
+		return options, fmt.Errorf("%s '%s' is not supported, use one of %s", protocolAnnotation, options.Protocol, strings.Join(model.Protocols, ", "))
+	}
+	value, exists := annotations[minActiveMembersAnnotation]
+	if exists {
+		minActiveMembers, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || minActiveMembers < 0 || minActiveMembers > 1024 {
+			return options, fmt.Errorf("%s '%s' has to be a number between 0 and 1024", minActiveMembersAnnotation, value)
+		}
+		options.MinActiveMembers = minActiveMembers
+	}
+	value, exists = annotations[memberPriorityAnnotation]
+	if exists {
+		priority, err := parseMemberPriority(value)
+		if err != nil {
+			return options, fmt.Errorf("%s is invalid: %s", memberPriorityAnnotation, err)
+		}
+		options.MemberPriority = priority
+	}
+	return options, nil
+}
+
+//parseInstances reads comma separated names of a10 instances the ingress controller should be synced into
+func parseInstances(annotations map[string]string) []string {
+	value, exists := annotations[instancesAnnotation]
+	if !exists {
+		return nil
+	}
+	instances := make([]string, 0)
+	for _, instance := range strings.Split(value, ",") {
+		instance = strings.TrimSpace(instance)
+		if len(instance) > 0 {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+func findMainContainer(containers []v1.Container) (*v1.Container, v1.ContainerPort) {
 	for _, container := range containers {
 		if container.Ports == nil || len(container.Ports) == 0 {
 			continue
@@ -42,10 +146,66 @@ func findMainContainer(containers []v1.Container) (*v1.Container, int) {
 
 		for _, port := range container.Ports {
 			if strings.HasSuffix(port.Name, "http") {
-				return &container, int(port.HostPort)
+				return &container, port
 			}
 		}
 	}
 
-	return nil, 0
+	return nil, v1.ContainerPort{}
+}
+
+//buildControllerPorts reads additional ports of the main container declared by a10.service_group.<port name> annotations,
+//health settings default to the ones of the main port and can be overridden by a10.health.endpoint.<port name> and a10.health.port.<port name>
+func buildControllerPorts(controller v1beta1.DaemonSet, mainContainer *v1.Container, healthCheck *model.HealthCheck, resolver portResolver) ([]*model.ControllerPort, error) {
+	ports := make([]*model.ControllerPort, 0)
+	for key, template := range controller.Annotations {
+		if !strings.HasPrefix(key, serviceGroupAnnotation+".") {
+			continue
+		}
+		portName := strings.TrimPrefix(key, serviceGroupAnnotation+".")
+
+		containerPort, found := findContainerPort(mainContainer, portName)
+		if !found {
+			return nil, fmt.Errorf("Port %s referenced by annotation %s not found on main container of ingress controller %s", portName, key, controller.GetName())
+		}
+		port, portSource, err := resolver.resolve(containerPort)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to resolve port %s of ingress controller %s. error: %s", portName, controller.GetName(), err)
+		}
+
+		portHealthCheck := *healthCheck
+		endpoint, exists := controller.Annotations[healthEndpointAnnotation+"."+portName]
+		if exists {
+			portHealthCheck.Endpoint = endpoint
+		}
+		healthPort, exists, err := parseHealthPort(controller.Annotations, healthPortAnnotation+"."+portName)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid health port for port %s of ingress controller %s. error: %s", portName, controller.GetName(), err)
+		}
+		if exists {
+			portHealthCheck.Port = healthPort
+		}
+
+		ports = append(ports, &model.ControllerPort{
+			Name:                     portName,
+			Port:                     port,
+			Source:                   portSource,
+			ServiceGroupNameTemplate: template,
+			Health:                   &portHealthCheck,
+		})
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Name < ports[j].Name
+	})
+	return ports, nil
+}
+
+func findContainerPort(container *v1.Container, name string) (v1.ContainerPort, bool) {
+	for _, port := range container.Ports {
+		if port.Name == name {
+			return port, true
+		}
+	}
+	return v1.ContainerPort{}, false
 }
@@ -0,0 +1,36 @@
+package apiserver
+
+import (
+	"a10bridge/model"
+	"strings"
+
+	"k8s.io/api/core/v1"
+)
+
+func buildLoadBalancer(service v1.Service) *model.LoadBalancer {
+	loadBalancer := model.LoadBalancer{
+		Name:        service.GetName(),
+		Namespace:   service.GetNamespace(),
+		RequestedIP: service.Spec.LoadBalancerIP,
+		Ports:       make([]*model.LoadBalancerPort, 0),
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if len(ingress.IP) > 0 {
+			loadBalancer.VIP = ingress.IP
+			loadBalancer.StatusVIP = ingress.IP
+			break
+		}
+	}
+
+	for _, port := range service.Spec.Ports {
+		loadBalancer.Ports = append(loadBalancer.Ports, &model.LoadBalancerPort{
+			Name:     port.Name,
+			Protocol: strings.ToLower(string(port.Protocol)),
+			Port:     int(port.Port),
+			NodePort: int(port.NodePort),
+		})
+	}
+
+	return &loadBalancer
+}
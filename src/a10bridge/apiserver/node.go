@@ -3,43 +3,146 @@ package apiserver
 import (
 	"a10bridge/model"
 	"a10bridge/util"
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/golang/glog"
 	"k8s.io/api/core/v1"
 )
 
-//BuildNode builds a apiserver node with relevant information
+const (
+	serverAnnotation            = "a10.server"
+	serverWeightAnnotation      = "a10.server.weight"
+	serverConnLimitAnnotation   = "a10.server.conn_limit"
+	serverSlowStartAnnotation   = "a10.server.slow_start"
+	serverDescriptionAnnotation = "a10.server.description"
+	serverTemplateAnnotation    = "a10.server.template"
+	serverStateAnnotation       = "a10.server.state"
+	memberPriorityAnnotation    = "a10.member.priority"
+)
+
+//maxServerConnLimit highest connection limit accepted by ACOS
+const maxServerConnLimit = 8000000
+
+//BuildNode builds a apiserver node with relevant information, the weight is left empty
+//without a10.server.weight annotation and computed by the configured weight strategy
 func buildNode(k8sNode v1.Node) (*model.Node, error) {
 	var node model.Node
 	name := k8sNode.GetName()
-	addr, err := util.LookupIP(name)
+	ipv4, ipv6, err := util.LookupIP(name)
 
 	if err == nil {
 		node = model.Node{
-			Name:      name,
-			IPAddress: addr,
-			A10Server: findA10ServerName(k8sNode),
-			Weight:    findNodeWeight(k8sNode, "1"),
-			Labels:    k8sNode.Labels,
+			Name:           name,
+			IPAddress:      ipv4,
+			IPv6Address:    ipv6,
+			A10Server:      findA10ServerName(k8sNode),
+			Weight:         findNodeWeight(k8sNode, ""),
+			Labels:         k8sNode.Labels,
+			ConnLimit:      findServerConnLimit(k8sNode),
+			SlowStart:      findServerSlowStart(k8sNode),
+			Description:    util.SanitizeA10Description(k8sNode.Annotations[serverDescriptionAnnotation]),
+			Template:       k8sNode.Annotations[serverTemplateAnnotation],
+			State:          findServerState(k8sNode),
+			MemberPriority: findMemberPriority(k8sNode),
+
+			AllocatableCPU:    k8sNode.Status.Allocatable.Cpu().MilliValue(),
+			AllocatableMemory: k8sNode.Status.Allocatable.Memory().Value(),
 		}
 	}
 
 	return &node, err
 }
 
+//findNodeWeight reads the weight the same way the webhook validates it, invalid weights are ignored
 func findNodeWeight(k8sNode v1.Node, defWeight string) string {
-	weight, exists := k8sNode.Annotations["a10.server.weight"]
+	value, exists := k8sNode.Annotations[serverWeightAnnotation]
 	if !exists {
-		weight = defWeight
+		return defWeight
 	}
-
-	return weight
+	weight, err := util.ParseA10Weight(value)
+	if err != nil {
+		glog.Warningf("Ignoring %s annotation of node %s. %s", serverWeightAnnotation, k8sNode.GetName(), err)
+		return defWeight
+	}
+	return strconv.Itoa(weight)
 }
 
+//findA10ServerName reads the server name, it is validated by util.ValidateA10Name like in the webhook before the server is synced
 func findA10ServerName(k8sNode v1.Node) string {
-	serverName, exists := k8sNode.Annotations["a10.server"]
+	serverName, exists := k8sNode.Annotations[serverAnnotation]
 	if !exists {
 		serverName = k8sNode.GetName()
 	}
 
 	return serverName
 }
+
+func findServerConnLimit(k8sNode v1.Node) int {
+	value, exists := k8sNode.Annotations[serverConnLimitAnnotation]
+	if !exists {
+		return 0
+	}
+	connLimit, err := parseServerConnLimit(value)
+	if err != nil {
+		glog.Warningf("Ignoring %s annotation of node %s. %s", serverConnLimitAnnotation, k8sNode.GetName(), err)
+		return 0
+	}
+	return connLimit
+}
+
+func parseServerConnLimit(value string) (int, error) {
+	connLimit, err := strconv.Atoi(value)
+	if err != nil || connLimit < 1 || connLimit > maxServerConnLimit {
+		return 0, fmt.Errorf("connection limit '%s' has to be a number between 1 and %d", value, maxServerConnLimit)
+	}
+	return connLimit, nil
+}
+
+func findServerSlowStart(k8sNode v1.Node) string {
+	value, exists := k8sNode.Annotations[serverSlowStartAnnotation]
+	if !exists {
+		return ""
+	}
+	slowStart, err := strconv.ParseBool(value)
+	if err != nil {
+		glog.Warningf("Ignoring %s annotation of node %s, '%s' is not a boolean", serverSlowStartAnnotation, k8sNode.GetName(), value)
+		return ""
+	}
+	return strconv.FormatBool(slowStart)
+}
+
+func findServerState(k8sNode v1.Node) string {
+	value, exists := k8sNode.Annotations[serverStateAnnotation]
+	if !exists {
+		return ""
+	}
+	if value != model.ServerStateEnable && value != model.ServerStateDisable {
+		glog.Warningf("Ignoring %s annotation of node %s, '%s' is neither %s nor %s", serverStateAnnotation, k8sNode.GetName(), value, model.ServerStateEnable, model.ServerStateDisable)
+		return ""
+	}
+	return value
+}
+
+func findMemberPriority(k8sNode v1.Node) int {
+	value, exists := k8sNode.Annotations[memberPriorityAnnotation]
+	if !exists {
+		return 0
+	}
+	priority, err := parseMemberPriority(value)
+	if err != nil {
+		glog.Warningf("Ignoring %s annotation of node %s. %s", memberPriorityAnnotation, k8sNode.GetName(), err)
+		return 0
+	}
+	return priority
+}
+
+//parseMemberPriority reads priority of service group members, shared by node and ingress controller annotations
+func parseMemberPriority(value string) (int, error) {
+	priority, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || priority < 1 || priority > model.MemberPriorityMax {
+		return 0, fmt.Errorf("member priority '%s' has to be a number between 1 and %d", value, model.MemberPriorityMax)
+	}
+	return priority, nil
+}
@@ -0,0 +1,56 @@
+package apiserver
+
+import (
+	"a10bridge/model"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const serviceAnnotation = "a10.service"
+
+//portResolver finds the port a10 should send traffic to for a container port of ingress controller
+type portResolver struct {
+	hostNetwork bool
+	service     *v1.Service
+}
+
+func newPortResolver(controller v1beta1.DaemonSet, service *v1.Service) portResolver {
+	return portResolver{
+		hostNetwork: controller.Spec.Template.Spec.HostNetwork,
+		service:     service,
+	}
+}
+
+//resolve prefers node port of the selected service, pods on host network are reached on the container port, host port is used otherwise
+func (resolver portResolver) resolve(port v1.ContainerPort) (int, string, error) {
+	if resolver.service != nil {
+		for _, servicePort := range resolver.service.Spec.Ports {
+			if !targetsContainerPort(servicePort.TargetPort, port) {
+				continue
+			}
+			if servicePort.NodePort == 0 {
+				return 0, "", fmt.Errorf("Port %s of service %s/%s has no node port", servicePort.Name, resolver.service.GetNamespace(), resolver.service.GetName())
+			}
+			return int(servicePort.NodePort), model.PortSourceNodePort, nil
+		}
+		return 0, "", fmt.Errorf("Service %s/%s doesn't expose container port %s", resolver.service.GetNamespace(), resolver.service.GetName(), port.Name)
+	}
+
+	if resolver.hostNetwork {
+		return int(port.ContainerPort), model.PortSourceHostNetwork, nil
+	}
+	if port.HostPort == 0 {
+		return 0, "", fmt.Errorf("Container port %s has no host port", port.Name)
+	}
+	return int(port.HostPort), model.PortSourceHostPort, nil
+}
+
+func targetsContainerPort(targetPort intstr.IntOrString, port v1.ContainerPort) bool {
+	if targetPort.Type == intstr.String {
+		return len(targetPort.StrVal) > 0 && targetPort.StrVal == port.Name
+	}
+	return targetPort.IntVal == port.ContainerPort
+}
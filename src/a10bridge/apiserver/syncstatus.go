@@ -0,0 +1,155 @@
+package apiserver
+
+import (
+	"a10bridge/model"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	syncStatusAnnotation = "a10bridge.io/sync-status"
+	eventSourceComponent = "a10bridge"
+)
+
+//syncStatusRefresh how often last sync of unchanged statuses is refreshed, patching every object on every run would load the api server
+const syncStatusRefresh = 10 * time.Minute
+
+//syncTarget kubernetes object sync events and status are published for
+type syncTarget struct {
+	reference   v1.ObjectReference
+	annotations map[string]string
+	patch       func(data []byte) error
+}
+
+func (client clientImpl) getSyncTarget(kind, namespace, name string) (*syncTarget, error) {
+	switch kind {
+	case model.KindNode:
+		nodes := client.corev1Impl.Nodes()
+		node, err := nodes.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &syncTarget{
+			reference: v1.ObjectReference{
+				Kind:       kind,
+				APIVersion: "v1",
+				Name:       name,
+				UID:        node.GetUID(),
+			},
+			annotations: node.GetAnnotations(),
+			patch: func(data []byte) error {
+				_, err := nodes.Patch(name, types.MergePatchType, data)
+				return err
+			},
+		}, nil
+	case model.KindDaemonSet:
+		daemonSets := client.extensionsv1beta1Impl.DaemonSets(namespace)
+		daemonSet, err := daemonSets.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &syncTarget{
+			reference: v1.ObjectReference{
+				Kind:       kind,
+				APIVersion: "extensions/v1beta1",
+				Namespace:  namespace,
+				Name:       name,
+				UID:        daemonSet.GetUID(),
+			},
+			annotations: daemonSet.GetAnnotations(),
+			patch: func(data []byte) error {
+				_, err := daemonSets.Patch(name, types.MergePatchType, data)
+				return err
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("Publishing sync status of %s objects is not supported", kind)
+}
+
+func buildEvent(reference v1.ObjectReference, syncEvent *model.SyncEvent, now time.Time, sequence int) *v1.Event {
+	namespace := reference.Namespace
+	if len(namespace) == 0 {
+		//events of cluster scoped objects live in the default namespace
+		namespace = metav1.NamespaceDefault
+	}
+	timestamp := metav1.NewTime(now)
+
+	return &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", reference.Name, now.UnixNano()+int64(sequence)),
+			Namespace: namespace,
+		},
+		InvolvedObject: reference,
+		Reason:         syncEvent.Reason,
+		Message:        syncEvent.Message,
+		Type:           syncEvent.Type,
+		Source:         v1.EventSource{Component: eventSourceComponent},
+		FirstTimestamp: timestamp,
+		LastTimestamp:  timestamp,
+		Count:          1,
+	}
+}
+
+//buildSyncStatusPatch merges sync statuses of a10 instances into the statuses already stored in the annotation. Statuses with
+//the same result and message keep their last transition and their last sync is refreshed once it is older than syncStatusRefresh,
+//no patch is built when nothing needs to be refreshed
+func buildSyncStatusPatch(annotations map[string]string, statuses map[string]*model.SyncStatus) ([]byte, error) {
+	merged := make(map[string]*model.SyncStatus)
+	if existing, exists := annotations[syncStatusAnnotation]; exists {
+		//unparsable annotation gets overwritten
+		json.Unmarshal([]byte(existing), &merged)
+	}
+	changed := false
+	for instance, status := range statuses {
+		updated := *status
+		updated.LastTransition = status.LastSync
+		previous, exists := merged[instance]
+		if exists && previous != nil && previous.Result == status.Result && previous.Message == status.Message {
+			if !syncStatusOutdated(previous.LastSync, status.LastSync) {
+				continue
+			}
+			//statuses stored before transitions were tracked only know their last sync
+			if len(previous.LastTransition) > 0 {
+				updated.LastTransition = previous.LastTransition
+			} else {
+				updated.LastTransition = previous.LastSync
+			}
+		}
+		merged[instance] = &updated
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+
+	value, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				syncStatusAnnotation: string(value),
+			},
+		},
+	})
+}
+
+//syncStatusOutdated checks last sync of a status is due for refresh, unparsable times are always refreshed
+func syncStatusOutdated(lastSync, now string) bool {
+	previous, err := time.Parse(time.RFC3339, lastSync)
+	if err != nil {
+		return true
+	}
+	current, err := time.Parse(time.RFC3339, now)
+	if err != nil {
+		return true
+	}
+	return current.Sub(previous) >= syncStatusRefresh
+}
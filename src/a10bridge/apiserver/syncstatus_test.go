@@ -0,0 +1,151 @@
+package apiserver_test
+
+import (
+	"a10bridge/apiserver"
+	"a10bridge/model"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type SyncStatusTestSuite struct {
+	suite.Suite
+	helper *apiserver.TestHelper
+}
+
+func TestSyncStatus(t *testing.T) {
+	tests := new(SyncStatusTestSuite)
+	tests.helper = new(apiserver.TestHelper)
+	suite.Run(t, tests)
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_node() {
+	node := corev1.Node{}
+	node.SetName("node1")
+	node.SetUID(types.UID("node1-uid"))
+	node.SetAnnotations(map[string]string{
+		"a10bridge.io/sync-status": `{"lb02":{"lastSync":"2018-04-30T10:00:00Z","result":"Synced"}}`,
+	})
+	clientset := fake.NewSimpleClientset(&node)
+	client := suite.helper.BuildClient(clientset)
+
+	events := []*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindNode, Name: "node1", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonCreated, Message: "a10 lb01: server node1 created"},
+	}
+	statuses := map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+	}
+	err := client.PublishSyncStatus(model.KindNode, "", "node1", events, statuses)
+	suite.Assert().Nil(err)
+
+	eventList, err := clientset.CoreV1().Events(metav1.NamespaceDefault).List(metav1.ListOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Len(eventList.Items, 1)
+	event := eventList.Items[0]
+	suite.Assert().Equal("Node", event.InvolvedObject.Kind)
+	suite.Assert().Equal("node1", event.InvolvedObject.Name)
+	suite.Assert().Equal(types.UID("node1-uid"), event.InvolvedObject.UID)
+	suite.Assert().Equal("Created", event.Reason)
+	suite.Assert().Equal("Normal", event.Type)
+	suite.Assert().Equal("a10 lb01: server node1 created", event.Message)
+	suite.Assert().Equal("a10bridge", event.Source.Component)
+
+	updated, err := clientset.CoreV1().Nodes().Get("node1", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	syncStatus := make(map[string]*model.SyncStatus)
+	err = json.Unmarshal([]byte(updated.GetAnnotations()["a10bridge.io/sync-status"]), &syncStatus)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", LastTransition: "2018-05-01T10:00:00Z", Result: "Synced"},
+		"lb02": &model.SyncStatus{LastSync: "2018-04-30T10:00:00Z", Result: "Synced"},
+	}, syncStatus)
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_daemonSet() {
+	daemonSet := extensionsv1beta1.DaemonSet{}
+	daemonSet.SetName("ingress-controller")
+	daemonSet.SetNamespace("ingress")
+	clientset := fake.NewSimpleClientset(&daemonSet)
+	client := suite.helper.BuildClient(clientset)
+
+	events := []*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress-controller", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonSyncFailed, Message: "a10 lb01: failure"},
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress-controller", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonDeleted, Message: "a10 lb01: member removed"},
+	}
+	statuses := map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultFailed, Message: "a10 lb01: failure"},
+	}
+	err := client.PublishSyncStatus(model.KindDaemonSet, "ingress", "ingress-controller", events, statuses)
+	suite.Assert().Nil(err)
+
+	eventList, err := clientset.CoreV1().Events("ingress").List(metav1.ListOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Len(eventList.Items, 2)
+
+	updated, err := clientset.ExtensionsV1beta1().DaemonSets("ingress").Get("ingress-controller", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(`{"lb01":{"lastSync":"2018-05-01T10:00:00Z","lastTransition":"2018-05-01T10:00:00Z","result":"Failed","message":"a10 lb01: failure"}}`, updated.GetAnnotations()["a10bridge.io/sync-status"])
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_recentlyRefreshedStatusIsNotPatched() {
+	node := corev1.Node{}
+	node.SetName("node1")
+	node.SetAnnotations(map[string]string{
+		"a10bridge.io/sync-status": `{"lb01":{"lastSync":"2018-05-01T09:55:00Z","lastTransition":"2018-04-30T10:00:00Z","result":"Synced"}}`,
+	})
+	clientset := fake.NewSimpleClientset(&node)
+	client := suite.helper.BuildClient(clientset)
+
+	statuses := map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+	}
+	err := client.PublishSyncStatus(model.KindNode, "", "node1", []*model.SyncEvent{}, statuses)
+	suite.Assert().Nil(err)
+
+	for _, action := range clientset.Actions() {
+		suite.Assert().NotEqual("patch", action.GetVerb())
+	}
+	updated, err := clientset.CoreV1().Nodes().Get("node1", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(`{"lb01":{"lastSync":"2018-05-01T09:55:00Z","lastTransition":"2018-04-30T10:00:00Z","result":"Synced"}}`, updated.GetAnnotations()["a10bridge.io/sync-status"])
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_unchangedStatusKeepsLastTransition() {
+	node := corev1.Node{}
+	node.SetName("node1")
+	node.SetAnnotations(map[string]string{
+		"a10bridge.io/sync-status": `{"lb01":{"lastSync":"2018-05-01T09:00:00Z","lastTransition":"2018-04-30T10:00:00Z","result":"Synced"}}`,
+	})
+	clientset := fake.NewSimpleClientset(&node)
+	client := suite.helper.BuildClient(clientset)
+
+	statuses := map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+	}
+	err := client.PublishSyncStatus(model.KindNode, "", "node1", []*model.SyncEvent{}, statuses)
+	suite.Assert().Nil(err)
+
+	updated, err := clientset.CoreV1().Nodes().Get("node1", metav1.GetOptions{})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(`{"lb01":{"lastSync":"2018-05-01T10:00:00Z","lastTransition":"2018-04-30T10:00:00Z","result":"Synced"}}`, updated.GetAnnotations()["a10bridge.io/sync-status"])
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_objectNotFound() {
+	client := suite.helper.BuildClient(fake.NewSimpleClientset())
+
+	err := client.PublishSyncStatus(model.KindNode, "", "node1", []*model.SyncEvent{}, map[string]*model.SyncStatus{})
+	suite.Assert().NotNil(err)
+}
+
+func (suite *SyncStatusTestSuite) TestPublishSyncStatus_unsupportedKind() {
+	client := suite.helper.BuildClient(fake.NewSimpleClientset())
+
+	err := client.PublishSyncStatus("Pod", "default", "pod", []*model.SyncEvent{}, map[string]*model.SyncStatus{})
+	suite.Assert().NotNil(err)
+}
@@ -0,0 +1,169 @@
+package apiserver
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+)
+
+const a10AnnotationPrefix = "a10."
+
+//ValidateIngressController checks a10 annotations of the daemon set the same way they are read during reconciliation
+func ValidateIngressController(daemonSet v1beta1.DaemonSet, environment *model.Environment) []string {
+	violations := make([]string, 0)
+	if !HasA10Annotations(daemonSet.Annotations) {
+		return violations
+	}
+
+	templateData := &model.TemplateData{
+		Environment: environment,
+		Name:        daemonSet.GetName(),
+		Namespace:   daemonSet.GetNamespace(),
+		Labels:      daemonSet.GetLabels(),
+		Annotations: daemonSet.GetAnnotations(),
+	}
+	template, exists := daemonSet.Annotations[serviceGroupAnnotation]
+	if !exists || len(strings.TrimSpace(template)) == 0 {
+		violations = append(violations, "a10.service_group annotation is required")
+	}
+
+	keys := make([]string, 0)
+	for key := range daemonSet.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := daemonSet.Annotations[key]
+		switch {
+		case key == serviceGroupAnnotation || strings.HasPrefix(key, serviceGroupAnnotation+"."):
+			if len(strings.TrimSpace(value)) == 0 {
+				if key != serviceGroupAnnotation {
+					violations = append(violations, fmt.Sprintf("%s annotation can't be empty", key))
+				}
+				continue
+			}
+			serviceGroupName, err := util.ApplyTemplate(templateData, value)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%s template '%s' can't be rendered: %s", key, value, err))
+			} else if len(strings.TrimSpace(serviceGroupName)) == 0 {
+				violations = append(violations, fmt.Sprintf("%s template '%s' renders to an empty service group name", key, value))
+			}
+		case key == healthPortAnnotation || strings.HasPrefix(key, healthPortAnnotation+"."):
+			_, _, err := parseHealthPort(daemonSet.Annotations, key)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", key, err))
+			}
+		case key == healthEndpointAnnotation || strings.HasPrefix(key, healthEndpointAnnotation+"."):
+			if !strings.HasPrefix(value, "/") {
+				violations = append(violations, fmt.Sprintf("%s annotation '%s' has to start with /", key, value))
+			}
+		}
+	}
+
+	_, exists = daemonSet.Annotations[instancesAnnotation]
+	if exists && len(parseInstances(daemonSet.Annotations)) == 0 {
+		violations = append(violations, fmt.Sprintf("%s annotation has to list at least one a10 instance", instancesAnnotation))
+	}
+
+	_, err := parseServiceGroupOptions(daemonSet.Annotations)
+	if err != nil {
+		violations = append(violations, err.Error())
+	}
+
+	service, exists := daemonSet.Annotations[serviceAnnotation]
+	if exists && len(strings.TrimSpace(service)) == 0 {
+		violations = append(violations, fmt.Sprintf("%s annotation can't be empty", serviceAnnotation))
+	}
+
+	//with valid annotations the only remaining failures come from the pod template,
+	//ports exposed through a service can only be checked during reconciliation
+	if len(violations) == 0 && !exists {
+		_, err := buildIngressController(daemonSet, nil)
+		if err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	return violations
+}
+
+//ValidateNode checks a10 annotations of the node the same way they are read during reconciliation
+func ValidateNode(node v1.Node) []string {
+	violations := make([]string, 0)
+
+	serverName, exists := node.Annotations[serverAnnotation]
+	if exists {
+		err := util.ValidateA10Name(serverName)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", serverAnnotation, err))
+		}
+	}
+
+	weight, exists := node.Annotations[serverWeightAnnotation]
+	if exists {
+		_, err := util.ParseA10Weight(weight)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", serverWeightAnnotation, err))
+		}
+	}
+
+	connLimit, exists := node.Annotations[serverConnLimitAnnotation]
+	if exists {
+		_, err := parseServerConnLimit(connLimit)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", serverConnLimitAnnotation, err))
+		}
+	}
+
+	slowStart, exists := node.Annotations[serverSlowStartAnnotation]
+	if exists {
+		_, err := strconv.ParseBool(slowStart)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation '%s' has to be true or false", serverSlowStartAnnotation, slowStart))
+		}
+	}
+
+	description, exists := node.Annotations[serverDescriptionAnnotation]
+	if exists && util.SanitizeA10Description(description) != description {
+		violations = append(violations, fmt.Sprintf("%s annotation can't be longer than %d characters or contain quotes, backslashes and control characters", serverDescriptionAnnotation, util.A10DescriptionMaxLength))
+	}
+
+	template, exists := node.Annotations[serverTemplateAnnotation]
+	if exists {
+		err := util.ValidateA10Name(template)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", serverTemplateAnnotation, err))
+		}
+	}
+
+	state, exists := node.Annotations[serverStateAnnotation]
+	if exists && state != model.ServerStateEnable && state != model.ServerStateDisable {
+		violations = append(violations, fmt.Sprintf("%s annotation '%s' has to be %s or %s", serverStateAnnotation, state, model.ServerStateEnable, model.ServerStateDisable))
+	}
+
+	priority, exists := node.Annotations[memberPriorityAnnotation]
+	if exists {
+		_, err := parseMemberPriority(priority)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s annotation is invalid: %s", memberPriorityAnnotation, err))
+		}
+	}
+
+	return violations
+}
+
+//HasA10Annotations checks if any of the annotations is an a10 annotation
+func HasA10Annotations(annotations map[string]string) bool {
+	for key := range annotations {
+		if strings.HasPrefix(key, a10AnnotationPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package apiserver_test
+
+import (
+	"a10bridge/apiserver"
+	"a10bridge/model"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+func TestValidation(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":   "{{.Cluster}}-web",
+		"a10.health.endpoint": "/healthz",
+		"a10.health.port":     "10254",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{Cluster: "dc-prod"})
+	suite.Assert().Empty(violations)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_templateData() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group": "{{.Data.region}}-{{.Namespace}}-{{.Name}}-{{.Labels.team}}",
+	})
+	daemonSet.SetLabels(map[string]string{"team": "web"})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{Data: map[string]string{"region": "east"}})
+	suite.Assert().Empty(violations)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_invalidAnnotations() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":   "{{.Clustr}}",
+		"a10.health.endpoint": "healthz",
+		"a10.health.port":     "70000",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{Cluster: "dc-prod"})
+	suite.Assert().Len(violations, 3)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_additionalPorts() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":          "web",
+		"a10.service_group.https":    "{{.Clustr}}",
+		"a10.service_group.proxy":    "proxy",
+		"a10.health.port.https":      "abc",
+		"a10.health.endpoint.https":  "health",
+		"a10.health.endpoint.metric": "/metrics",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 3)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_additionalPortNotFound() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":       "web",
+		"a10.service_group.https": "web-https",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 1)
+	suite.Assert().Contains(violations[0], "https")
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_nodePortService() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":       "web",
+		"a10.service_group.https": "web-https",
+		"a10.service":             "ingress-nodeport",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Empty(violations)
+
+	daemonSet.Annotations["a10.service"] = ""
+	violations = apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 1)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_emptyInstances() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group": "web",
+		"a10.instances":     " , ",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Equal([]string{"a10.instances annotation has to list at least one a10 instance"}, violations)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_serviceGroupOptions() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group":      "web",
+		"a10.lb_method":          "weighted-rr",
+		"a10.protocol":           "tcp",
+		"a10.min_active_members": "1",
+		"a10.member.priority":    "2",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Empty(violations)
+
+	daemonSet.Annotations["a10.member.priority"] = "primary"
+	violations = apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 1)
+	suite.Assert().Contains(violations[0], "a10.member.priority")
+	daemonSet.Annotations["a10.member.priority"] = "2"
+
+	daemonSet.Annotations["a10.min_active_members"] = "many"
+	violations = apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 1)
+	suite.Assert().Contains(violations[0], "a10.min_active_members")
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_emptyServiceGroupName() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group": "{{.Type}}",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{Cluster: "dc"})
+	suite.Assert().Len(violations, 1)
+	suite.Assert().Contains(violations[0], "empty service group name")
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_missingServiceGroup() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.health.port": "10254",
+	})
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Equal([]string{"a10.service_group annotation is required"}, violations)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_missingMainContainer() {
+	daemonSet := ingressControllerDaemonSet(map[string]string{
+		"a10.service_group": "web",
+	})
+	daemonSet.Spec.Template.Spec.Containers[0].Ports[0].Name = "metrics"
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Len(violations, 1)
+}
+
+func (suite *ValidationTestSuite) TestValidateIngressController_notManaged() {
+	daemonSet := extensionsv1beta1.DaemonSet{}
+	daemonSet.SetName("fluentd")
+
+	violations := apiserver.ValidateIngressController(daemonSet, &model.Environment{})
+	suite.Assert().Empty(violations)
+}
+
+func (suite *ValidationTestSuite) TestValidateNode() {
+	node := corev1.Node{}
+	node.SetAnnotations(map[string]string{
+		"a10.server":             "node1",
+		"a10.server.weight":      "10",
+		"a10.server.conn_limit":  "1000",
+		"a10.server.slow_start":  "true",
+		"a10.server.description": "edge node",
+		"a10.server.template":    "k8s-nodes",
+		"a10.server.state":       "disable",
+		"a10.member.priority":    "1",
+	})
+
+	suite.Assert().Empty(apiserver.ValidateNode(node))
+}
+
+func (suite *ValidationTestSuite) TestValidateNode_invalidAnnotations() {
+	node := corev1.Node{}
+	node.SetAnnotations(map[string]string{
+		"a10.server":             "node 1",
+		"a10.server.weight":      "0",
+		"a10.server.conn_limit":  "unlimited",
+		"a10.server.slow_start":  "sometimes",
+		"a10.server.description": `"quoted"`,
+		"a10.server.template":    "k8s nodes",
+		"a10.server.state":       "maintenance",
+		"a10.member.priority":    "17",
+	})
+
+	suite.Assert().Len(apiserver.ValidateNode(node), 8)
+}
+
+func ingressControllerDaemonSet(annotations map[string]string) extensionsv1beta1.DaemonSet {
+	daemonSet := extensionsv1beta1.DaemonSet{}
+	daemonSet.SetName("ingress-controller")
+	daemonSet.SetNamespace("ingress")
+	daemonSet.SetAnnotations(annotations)
+	daemonSet.Spec.Template.Spec.Containers = []corev1.Container{
+		corev1.Container{
+			Name: "controller",
+			Ports: []corev1.ContainerPort{
+				corev1.ContainerPort{Name: "http", HostPort: 80, ContainerPort: 80},
+			},
+			LivenessProbe: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(10254)},
+				},
+			},
+		},
+	}
+	return daemonSet
+}
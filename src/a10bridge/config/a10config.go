@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 
 	"gopkg.in/yaml.v2"
@@ -8,7 +9,17 @@ import (
 
 //A10Config configuration of a10 instances to work with
 type A10Config struct {
-	Instances []A10Instance `yaml:"instances"`
+	Instances     []A10Instance        `yaml:"instances"`
+	LoadBalancer  LoadBalancerConfig   `yaml:"loadBalancer"`
+	Environment   EnvironmentConfig    `yaml:"environment"`
+	ServiceGroups ServiceGroupDefaults `yaml:"serviceGroups"`
+	Servers       ServerDefaults       `yaml:"servers"`
+	TrafficShifts TrafficShiftsConfig  `yaml:"trafficShifts"`
+	Clusters      []ClusterConfig      `yaml:"clusters"`
+	Sources       []SourceConfig       `yaml:"sources"`
+	AppliedState  AppliedStateConfig   `yaml:"appliedState"`
+	RemovalLimits RemovalLimits        `yaml:"removalLimits"`
+	Canary        CanaryConfig         `yaml:"canary"`
 }
 
 type A10Instances []A10Instance
@@ -23,13 +34,48 @@ func (s A10Instances) Less(i, j int) bool {
 	return s[i].Name < s[j].Name
 }
 
+//Contains checks if there is an instance with the given name
+func (s A10Instances) Contains(name string) bool {
+	for _, instance := range s {
+		if instance.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+//Address families which can be used when creating servers in a10
+const (
+	AddressFamilyIPv4      = "ipv4"
+	AddressFamilyIPv6      = "ipv6"
+	AddressFamilyDualStack = "dual"
+)
+
 //A10Instance a10 instance configuration
 type A10Instance struct {
-	Name       string `yaml:"name"`
-	APIUrl     string `yaml:"apiUrl"`
-	APIVersion int    `yaml:"apiVersion"`
-	UserName   string `yaml:"userName"`
-	Password   string `yaml:"password"`
+	Name          string            `yaml:"name"`
+	APIUrl        string            `yaml:"apiUrl"`
+	APIVersion    int               `yaml:"apiVersion"`
+	UserName      string            `yaml:"userName"`
+	Password      string            `yaml:"password"`
+	AddressFamily string            `yaml:"addressFamily"`
+	Selector      *InstanceSelector `yaml:"selector"`
+	//Zone home zone of the instance, members in other zones become backup members
+	Zone string `yaml:"zone"`
+}
+
+//validateAddressFamily checks the address family is known and supported by the api version of the instance
+func (instance A10Instance) validateAddressFamily() error {
+	switch instance.AddressFamily {
+	case AddressFamilyIPv4, AddressFamilyIPv6:
+		return nil
+	case AddressFamilyDualStack:
+		if instance.APIVersion == 2 {
+			return fmt.Errorf("a10 instance %s uses api version 2 which doesn't support dual stack servers", instance.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("a10 instance %s has unsupported address family '%s'", instance.Name, instance.AddressFamily)
 }
 
 func readA10Configuration(configFilePath string) (*A10Config, error) {
@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"a10bridge/config"
+	"a10bridge/model"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -38,3 +40,166 @@ func (suite *A10ConfigTestSuite) TestSort() {
 	suite.Assert().Equal("a", a10Instances[2].Name)
 	suite.Assert().Equal("z", a10Instances[3].Name)
 }
+
+func (suite *A10ConfigTestSuite) TestVIPs() {
+	loadBalancer := config.LoadBalancerConfig{
+		VIPPool: []string{"10.0.0.8/31", "10.0.1.1", "fd00::1-fd00::2", "10.0.2.0/32"},
+	}
+
+	vips, err := loadBalancer.VIPs()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]string{"10.0.0.8", "10.0.0.9", "10.0.1.1", "fd00::1", "fd00::2", "10.0.2.0"}, vips)
+}
+
+func (suite *A10ConfigTestSuite) TestVIPs_invalidEntries() {
+	for _, entry := range []string{"blah", "10.0.0.0/33", "10.0.0.2-10.0.0.1", "10.0.0.1-fd00::1", "10.0.0.1-10.0.0.2-10.0.0.3"} {
+		loadBalancer := config.LoadBalancerConfig{VIPPool: []string{entry}}
+		_, err := loadBalancer.VIPs()
+		suite.Assert().NotNil(err, entry)
+	}
+}
+
+func (suite *A10ConfigTestSuite) TestVIPs_poolTooLarge() {
+	loadBalancer := config.LoadBalancerConfig{VIPPool: []string{"fd00::/64"}}
+	_, err := loadBalancer.VIPs()
+	suite.Assert().NotNil(err)
+}
+
+func (suite *A10ConfigTestSuite) TestSelectorMatches() {
+	selector := config.InstanceSelector{
+		ServiceGroups: []string{"^web-", "^api$"},
+		Labels:        map[string]string{"team": "web"},
+		Environment:   map[string]string{"dataCenter": "lga"},
+	}
+	labels := []map[string]string{
+		map[string]string{"app": "ingress"},
+		map[string]string{"app": "ingress", "team": "web"},
+	}
+	environment := map[string]string{"dataCenter": "lga", "type": "prod"}
+
+	suite.Assert().True(selector.Matches("web-80", labels, environment))
+	suite.Assert().True(selector.Matches("api", labels, environment))
+	suite.Assert().False(selector.Matches("api-80", labels, environment))
+	suite.Assert().False(selector.Matches("web-80", labels[:1], environment))
+	suite.Assert().False(selector.Matches("web-80", labels, map[string]string{"dataCenter": "ewr"}))
+}
+
+func (suite *A10ConfigTestSuite) TestSelectorMatches_emptySelector() {
+	suite.Assert().True(config.InstanceSelector{}.Matches("anything", nil, nil))
+}
+
+func trafficShift() config.TrafficShiftConfig {
+	return config.TrafficShiftConfig{
+		Name:         "migrate",
+		ServiceGroup: "sg",
+		Source:       config.ShiftSide{Cluster: "blue"},
+		Target:       config.ShiftSide{Cluster: "green"},
+		Steps:        []int{10, 50, 100},
+		Hold:         10,
+		OnFailure:    config.ShiftOnFailureRollback,
+	}
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftAdvance() {
+	shift := trafficShift()
+	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	state := shift.Advance(nil, start)
+	suite.Assert().Equal(model.ShiftStatusRunning, state.Status)
+	suite.Assert().Equal(10, shift.Percentage(state))
+
+	state = shift.Advance(state, start.Add(9*time.Minute))
+	suite.Assert().Equal(10, shift.Percentage(state))
+
+	state.DownMembers = map[string]int{"lb": 1}
+	state = shift.Advance(state, start.Add(10*time.Minute))
+	suite.Assert().Equal(50, shift.Percentage(state))
+	suite.Assert().Equal("2018-01-01T10:10:00Z", state.StepStarted)
+	suite.Assert().Nil(state.DownMembers)
+
+	state = shift.Advance(state, start.Add(20*time.Minute))
+	suite.Assert().Equal(100, shift.Percentage(state))
+	suite.Assert().True(state.Running())
+
+	state = shift.Advance(state, start.Add(30*time.Minute))
+	suite.Assert().Equal(model.ShiftStatusCompleted, state.Status)
+	suite.Assert().Equal(100, shift.Percentage(state))
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftCheckDownMembers() {
+	shift := trafficShift()
+	shift.MaxDownMembers = 1
+	state := &model.TrafficShiftState{Status: model.ShiftStatusRunning, Step: 1}
+
+	shift.CheckDownMembers(state, "lb", 2)
+	shift.CheckDownMembers(state, "lb", 3)
+	suite.Assert().True(state.Running())
+	suite.Assert().Equal(map[string]int{"lb": 2}, state.DownMembers)
+
+	shift.CheckDownMembers(state, "lb", 4)
+	suite.Assert().Equal(model.ShiftStatusRolledBack, state.Status)
+	suite.Assert().Equal(0, shift.Percentage(state))
+	suite.Assert().NotEmpty(state.Message)
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftCheckDownMembers_stop() {
+	shift := trafficShift()
+	shift.OnFailure = config.ShiftOnFailureStop
+	state := &model.TrafficShiftState{Status: model.ShiftStatusRunning, Step: 1, DownMembers: map[string]int{"lb": 0}}
+
+	shift.CheckDownMembers(state, "lb", 1)
+	suite.Assert().Equal(model.ShiftStatusStopped, state.Status)
+	suite.Assert().Equal(50, shift.Percentage(state))
+}
+
+func shiftedServiceGroup() (*model.ServiceGroup, map[string]*model.Node) {
+	nodes := map[string]*model.Node{
+		"blue1":  &model.Node{Name: "blue1", A10Server: "blue1", Weight: "1"},
+		"blue2":  &model.Node{Name: "blue2", A10Server: "blue2", Weight: "1"},
+		"green1": &model.Node{Name: "green1", A10Server: "green1", Weight: "1"},
+	}
+	serviceGroup := &model.ServiceGroup{
+		Name: "sg",
+		IngressControllers: []*model.IngressController{
+			&model.IngressController{Name: "ic", Cluster: "blue", Nodes: []*model.Node{nodes["blue1"], nodes["blue2"]}},
+			&model.IngressController{Name: "ic", Cluster: "green", Nodes: []*model.Node{nodes["green1"]}},
+		},
+	}
+	return serviceGroup, nodes
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftApply() {
+	serviceGroup, nodes := shiftedServiceGroup()
+
+	trafficShift().Apply(20, []*model.ServiceGroup{serviceGroup}, nodes)
+
+	//two source nodes at 100 and one target node at 50 send 20% to the target
+	suite.Assert().Equal("100", nodes["blue1"].Weight)
+	suite.Assert().Equal("100", nodes["blue2"].Weight)
+	suite.Assert().Equal("50", nodes["green1"].Weight)
+	suite.Assert().Equal(map[string]int{"blue1": 2, "blue2": 2, "green1": 2}, serviceGroup.MemberPriorities)
+
+	trafficShift().Apply(80, []*model.ServiceGroup{serviceGroup}, nodes)
+	suite.Assert().Equal("13", nodes["blue1"].Weight)
+	suite.Assert().Equal("100", nodes["green1"].Weight)
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftApply_endsKeepBackup() {
+	serviceGroup, nodes := shiftedServiceGroup()
+
+	trafficShift().Apply(0, []*model.ServiceGroup{serviceGroup}, nodes)
+	suite.Assert().Equal(map[string]int{"blue1": 2, "blue2": 2, "green1": 1}, serviceGroup.MemberPriorities)
+	suite.Assert().Equal("1", nodes["green1"].Weight)
+
+	trafficShift().Apply(100, []*model.ServiceGroup{serviceGroup}, nodes)
+	suite.Assert().Equal(map[string]int{"blue1": 1, "blue2": 1, "green1": 2}, serviceGroup.MemberPriorities)
+}
+
+func (suite *A10ConfigTestSuite) TestTrafficShiftApply_otherServiceGroup() {
+	serviceGroup, nodes := shiftedServiceGroup()
+	serviceGroup.Name = "other"
+
+	trafficShift().Apply(20, []*model.ServiceGroup{serviceGroup}, nodes)
+	suite.Assert().Nil(serviceGroup.MemberPriorities)
+	suite.Assert().Equal("1", nodes["blue1"].Weight)
+}
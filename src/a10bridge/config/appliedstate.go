@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+//AppliedStateConfig where state a10bridge applied to a10 instances is kept, in a local file or a config map in the namespace of the first cluster.
+//Without it changes made in a10 by others can't be told from changes of the desired state
+type AppliedStateConfig struct {
+	File      string `yaml:"file"`
+	ConfigMap string `yaml:"configMap"`
+	//KeepManualChanges leaves a10 objects changed out of band alone until their desired state changes
+	KeepManualChanges bool `yaml:"keepManualChanges"`
+}
+
+//Enabled checks the applied state is kept
+func (applied AppliedStateConfig) Enabled() bool {
+	return len(applied.File) > 0 || len(applied.ConfigMap) > 0
+}
+
+func (applied AppliedStateConfig) validate() error {
+	if len(applied.File) > 0 && len(applied.ConfigMap) > 0 {
+		return fmt.Errorf("applied state can be kept either in a file or in a config map, not both")
+	}
+	if applied.KeepManualChanges && !applied.Enabled() {
+		return fmt.Errorf("keepManualChanges needs applied state kept in a file or in a config map")
+	}
+	return nil
+}
@@ -11,22 +11,45 @@ import (
 
 //Args arguments
 type Args struct {
-	A10Pwd    *string
-	A10Config *string
-	Interval  *int
-	Debug     *bool
-	Daemon    *bool
-	Sort      *bool
+	A10Pwd       *string
+	A10Config    *string
+	Interval     *int
+	Debug        *bool
+	Daemon       *bool
+	Sort         *bool
+	LoadBalancer *bool
+	CRD          *bool
+	Events       *bool
+	WebhookAddr  *string
+	WebhookCert  *string
+	WebhookKey   *string
+	Kubeconfig   *string
+	Context      *string
+	Master       *string
+	InCluster    *bool
+	//AllowMassRemoval ignores removal limits, meant for single runs making intentionally large changes
+	AllowMassRemoval *bool
 }
 
 func buildArguments() (*Args, error) {
 	args := Args{
-		A10Config: addStringFlag("a10-config", "path to a10 config yaml file"),
-		A10Pwd:    addStringFlag("a10-pwd", "a10 password"),
-		Interval:  addIntFlag("interval", "invocation interval in seconds"),
-		Debug:     addBoolFlag("debug", "run in debug mode"),
-		Daemon:    addBoolFlag("daemon", "run in daemon mode"),
-		Sort:      addBoolFlag("sort", "run in sorted mode"),
+		A10Config:        addStringFlag("a10-config", "path to a10 config yaml file"),
+		A10Pwd:           addStringFlag("a10-pwd", "a10 password"),
+		Interval:         addIntFlag("interval", "invocation interval in seconds"),
+		Debug:            addBoolFlag("debug", "run in debug mode"),
+		Daemon:           addBoolFlag("daemon", "run in daemon mode"),
+		Sort:             addBoolFlag("sort", "run in sorted mode"),
+		LoadBalancer:     addBoolFlag("load-balancer", "expose kubernetes services of type LoadBalancer through a10 virtual servers"),
+		CRD:              addBoolFlag("crd", "read service groups from A10ServiceGroup custom resources"),
+		Events:           addBoolFlag("events", "publish sync results as kubernetes events and annotations on nodes and ingress controllers"),
+		WebhookAddr:      addStringFlag("webhook-addr", "listen address of the validating admission webhook, the webhook is disabled when empty"),
+		WebhookCert:      addStringFlag("webhook-cert", "path to tls certificate of the validating admission webhook"),
+		WebhookKey:       addStringFlag("webhook-key", "path to tls key of the validating admission webhook"),
+		Kubeconfig:       addStringFlag("kubeconfig", "path to kubeconfig file, the in-cluster config is used when neither kubeconfig, context nor master is set"),
+		Context:          addStringFlag("context", "kubeconfig context to use, the current context when empty"),
+		Master:           addStringFlag("master", "address of the kubernetes apiserver, overrides the server of the kubeconfig context"),
+		InCluster:        addBoolFlag("in-cluster", "use only the in-cluster config and fail instead of falling back to $HOME/.kube/config"),
+		AllowMassRemoval: addBoolFlag("allow-mass-removal", "ignore removal limits of the a10 config, for runs removing many members on purpose"),
 	}
 
 	flag.Parse()
@@ -52,6 +75,18 @@ func (toValidate Args) validate() error {
 		return errors.New("a10-config parameter points to notexistent file")
 	}
 
+	if len(strings.TrimSpace(*toValidate.WebhookAddr)) > 0 {
+		if len(strings.TrimSpace(*toValidate.WebhookCert)) == 0 || len(strings.TrimSpace(*toValidate.WebhookKey)) == 0 {
+			return errors.New("webhook-cert and webhook-key parameters are required when webhook-addr is set")
+		}
+	}
+
+	if *toValidate.InCluster {
+		if len(strings.TrimSpace(*toValidate.Kubeconfig)) > 0 || len(strings.TrimSpace(*toValidate.Context)) > 0 || len(strings.TrimSpace(*toValidate.Master)) > 0 {
+			return errors.New("kubeconfig, context and master parameters can't be used with in-cluster")
+		}
+	}
+
 	return nil
 }
 
@@ -63,6 +98,17 @@ func (args Args) printArgs() {
 	fmt.Println("interval:", *args.Interval)
 	fmt.Println("daemon:", *args.Daemon)
 	fmt.Println("sort:", *args.Sort)
+	fmt.Println("load-balancer:", *args.LoadBalancer)
+	fmt.Println("crd:", *args.CRD)
+	fmt.Println("events:", *args.Events)
+	fmt.Println("webhook-addr:", *args.WebhookAddr)
+	fmt.Println("webhook-cert:", *args.WebhookCert)
+	fmt.Println("webhook-key:", *args.WebhookKey)
+	fmt.Println("kubeconfig:", *args.Kubeconfig)
+	fmt.Println("context:", *args.Context)
+	fmt.Println("master:", *args.Master)
+	fmt.Println("in-cluster:", *args.InCluster)
+	fmt.Println("allow-mass-removal:", *args.AllowMassRemoval)
 	fmt.Println()
 }
 
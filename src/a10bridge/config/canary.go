@@ -0,0 +1,58 @@
+package config
+
+import "fmt"
+
+const defaultCanaryWait = 60
+
+//CanaryConfig a10 instance receiving changes first, the remaining instances are synced only when members of service groups
+//changed in the canary are up according to its health monitors once the wait is over
+type CanaryConfig struct {
+	Instance string `yaml:"instance"`
+	//Wait seconds given to the health monitors of the canary before its members are checked, at most half of the run interval
+	//leaving the other half to sync the remaining instances
+	Wait int `yaml:"wait"`
+	//MaxDownMembers members of the changed service groups allowed to be down before the rollout is halted
+	MaxDownMembers int `yaml:"maxDownMembers"`
+	//Revert brings changed servers, health monitors and service groups of the canary back to the state they had before the run when
+	//the rollout is halted. It needs applied state, which keeps the reverted change from being retried until the desired state changes
+	Revert bool `yaml:"revert"`
+}
+
+//Enabled checks changes roll out through a canary instance
+func (canary CanaryConfig) Enabled() bool {
+	return len(canary.Instance) > 0
+}
+
+//applyDefaults waits a minute by default, or half of the run interval when it is shorter than two minutes
+func (canary *CanaryConfig) applyDefaults(interval int) {
+	if canary.Enabled() && canary.Wait == 0 {
+		canary.Wait = defaultCanaryWait
+		if interval < 2*defaultCanaryWait {
+			canary.Wait = interval / 2
+		}
+	}
+}
+
+func (canary CanaryConfig) validate(instances A10Instances, interval int, applied AppliedStateConfig) error {
+	if !canary.Enabled() {
+		return nil
+	}
+	if !instances.Contains(canary.Instance) {
+		return fmt.Errorf("canary a10 instance %s is not configured", canary.Instance)
+	}
+	if canary.Wait < 0 {
+		return fmt.Errorf("canary wait can't be negative, got %d", canary.Wait)
+	}
+	//runs taking longer than the interval time out, the remaining instances are synced after the wait
+	if canary.Wait > interval/2 {
+		return fmt.Errorf("canary wait can be at most half of the interval of %d seconds, got %d", interval, canary.Wait)
+	}
+	if canary.MaxDownMembers < 0 {
+		return fmt.Errorf("canary maxDownMembers can't be negative, got %d", canary.MaxDownMembers)
+	}
+	//without applied state the halted rollout would be applied and reverted again in every run
+	if canary.Revert && !applied.Enabled() {
+		return fmt.Errorf("canary revert needs applied state kept in a file or in a config map")
+	}
+	return nil
+}
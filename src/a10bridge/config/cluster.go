@@ -0,0 +1,32 @@
+package config
+
+import "fmt"
+
+//ClusterConfig kubernetes cluster desired state is read from, selected by context of a kubeconfig file
+type ClusterConfig struct {
+	Kubeconfig  string            `yaml:"kubeconfig"`
+	Context     string            `yaml:"context"`
+	Environment EnvironmentConfig `yaml:"environment"`
+}
+
+func (cluster ClusterConfig) String() string {
+	return fmt.Sprintf("context '%s' of kubeconfig '%s'", cluster.Context, cluster.Kubeconfig)
+}
+
+func validateClusters(clusters []ClusterConfig) error {
+	for idx, cluster := range clusters {
+		if len(cluster.Context) == 0 {
+			return fmt.Errorf("cluster %d has no context, every cluster needs one", idx)
+		}
+		err := cluster.Environment.validate()
+		if err != nil {
+			return fmt.Errorf("cluster %s has invalid environment. %s", cluster, err)
+		}
+		for _, other := range clusters[:idx] {
+			if other.Kubeconfig == cluster.Kubeconfig && other.Context == cluster.Context {
+				return fmt.Errorf("cluster %s is configured more than once", cluster)
+			}
+		}
+	}
+	return nil
+}
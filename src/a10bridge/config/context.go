@@ -1,8 +1,24 @@
 package config
 
+import "fmt"
+
 type RunContext struct {
-	Arguments    *Args
-	A10Instances A10Instances
+	Arguments     *Args
+	A10Instances  A10Instances
+	LoadBalancer  *LoadBalancerConfig
+	Environment   *EnvironmentConfig
+	ServiceGroups ServiceGroupDefaults
+	Servers       ServerDefaults
+	TrafficShifts TrafficShiftsConfig
+	//Clusters kubernetes clusters merged into shared service groups, the cluster a10bridge runs in is used when empty.
+	//Load balancer mode supports a single cluster
+	Clusters []ClusterConfig
+	//Sources static files with servers and service groups merged with state of the clusters
+	Sources      []SourceConfig
+	AppliedState AppliedStateConfig
+	//RemovalLimits limits of member removals, runs with the allow-mass-removal argument ignore them
+	RemovalLimits RemovalLimits
+	Canary        CanaryConfig
 }
 
 func BuildConfig() (*RunContext, error) {
@@ -27,11 +43,107 @@ func BuildConfig() (*RunContext, error) {
 		if len(instance.Password) == 0 {
 			instance.Password = *args.A10Pwd
 		}
+		if len(instance.AddressFamily) == 0 {
+			instance.AddressFamily = AddressFamilyIPv4
+		}
+		err = instance.validateAddressFamily()
+		if err != nil {
+			return context, err
+		}
+		if instance.Selector != nil {
+			err = instance.Selector.validate()
+			if err != nil {
+				return context, fmt.Errorf("a10 instance %s has invalid selector. %s", instance.Name, err)
+			}
+		}
 		instances = append(instances, instance)
 	}
 
+	var loadBalancer *LoadBalancerConfig
+	if *args.LoadBalancer {
+		loadBalancer = &a10Config.LoadBalancer
+		if len(loadBalancer.NamePrefix) == 0 {
+			loadBalancer.NamePrefix = "k8s-"
+		}
+		err = loadBalancer.validate()
+		if err != nil {
+			return context, err
+		}
+	}
+
+	environment := &a10Config.Environment
+	environment.applyDefaults()
+	err = environment.validate()
+	if err != nil {
+		return context, err
+	}
+
+	err = a10Config.ServiceGroups.validate()
+	if err != nil {
+		return context, err
+	}
+
+	servers := a10Config.Servers
+	servers.applyDefaults()
+	err = servers.validate()
+	if err != nil {
+		return context, err
+	}
+
+	clusters := a10Config.Clusters
+	for idx := range clusters {
+		clusters[idx].Environment.applyDefaults()
+	}
+	err = validateClusters(clusters)
+	if err != nil {
+		return context, err
+	}
+	//vips are assigned and virtual servers named per cluster, services of different clusters would take over each other's
+	if loadBalancer != nil && len(clusters) > 1 {
+		return context, fmt.Errorf("load balancer mode supports a single cluster, %d clusters are configured", len(clusters))
+	}
+
+	err = validateSources(a10Config.Sources)
+	if err != nil {
+		return context, err
+	}
+
+	err = a10Config.AppliedState.validate()
+	if err != nil {
+		return context, err
+	}
+
+	err = a10Config.RemovalLimits.validate()
+	if err != nil {
+		return context, err
+	}
+
+	canary := a10Config.Canary
+	canary.applyDefaults(*args.Interval)
+	err = canary.validate(instances, *args.Interval, a10Config.AppliedState)
+	if err != nil {
+		return context, err
+	}
+
+	trafficShifts := a10Config.TrafficShifts
+	trafficShifts.applyDefaults()
+	err = trafficShifts.validate()
+	if err != nil {
+		return context, err
+	}
+
 	return &RunContext{
-		Arguments:    args,
-		A10Instances: instances,
+		Arguments:     args,
+		A10Instances:  instances,
+		LoadBalancer:  loadBalancer,
+		Environment:   environment,
+		ServiceGroups: a10Config.ServiceGroups,
+		Servers:       servers,
+		TrafficShifts: trafficShifts,
+		Clusters:      clusters,
+		Sources:       a10Config.Sources,
+		AppliedState:  a10Config.AppliedState,
+		RemovalLimits: a10Config.RemovalLimits,
+		Canary:        canary,
 	}, err
 }
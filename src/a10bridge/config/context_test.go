@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"a10bridge/config"
+	"a10bridge/model"
 	"flag"
 	"os"
 	"testing"
@@ -69,6 +70,9 @@ func (suite *TestSuite) TestBuildConfig_withRequiredFlags() {
 
 	suite.Assert().Equal(expectedPassword, conf.A10Instances[0].Password)
 	suite.Assert().Equal(expectedPassword, conf.A10Instances[1].Password)
+
+	suite.Assert().Equal(config.AddressFamilyIPv4, conf.A10Instances[0].AddressFamily)
+	suite.Assert().Equal(config.AddressFamilyIPv4, conf.A10Instances[1].AddressFamily)
 }
 
 func (suite *TestSuite) TestBuildConfig_withRequiredFlagsFromEnvironment() {
@@ -144,6 +148,710 @@ func (suite *TestSuite) TestBuildConfig_nameFromUrl() {
 	suite.Assert().Equal(conf.A10Instances[1].APIUrl, conf.A10Instances[1].Name)
 }
 
+func (suite *TestSuite) TestBuildConfig_addressFamilies() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config5.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(conf)
+
+	suite.Assert().Equal(config.AddressFamilyIPv6, conf.A10Instances[0].AddressFamily)
+	suite.Assert().Equal(config.AddressFamilyDualStack, conf.A10Instances[1].AddressFamily)
+}
+
+func (suite *TestSuite) TestBuildConfig_instanceZone() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config16.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(conf)
+
+	suite.Assert().Equal("hall-a", conf.A10Instances[0].Zone)
+	suite.Assert().Equal("", conf.A10Instances[1].Zone)
+}
+
+func (suite *TestSuite) TestBuildConfig_dualStackWithV2Api() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config6.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_unknownAddressFamily() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config7.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_loadBalancer() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-load-balancer")
+	os.Args = append(os.Args, "-a10-config=testdata/config8.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(conf.LoadBalancer)
+	suite.Assert().Equal("k8s-", conf.LoadBalancer.NamePrefix)
+
+	vips, err := conf.LoadBalancer.VIPs()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]string{"10.10.0.1", "10.10.0.2", "10.10.1.10", "10.10.1.11"}, vips)
+}
+
+func (suite *TestSuite) TestBuildConfig_defaultEnvironment() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config8.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(config.DefaultEnvironmentConfig(), conf.Environment)
+}
+
+func (suite *TestSuite) TestBuildConfig_customEnvironment() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config9.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(&config.EnvironmentConfig{
+		Namespace: "kube-system",
+		ConfigMap: "cluster-info",
+		NameKey:   "cluster",
+		Pattern:   "^(?P<dataCenter>.*)-(?P<type>[^-]*)$",
+	}, conf.Environment)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidEnvironmentPattern() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config10.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidInstanceSelector() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config11.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+	suite.Assert().Contains(err.Error(), "lga-lb02")
+}
+
+func (suite *TestSuite) TestBuildConfig_serviceGroupDefaults() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config12.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(config.ServiceGroupDefaults{
+		Method:           "least-connection",
+		Protocol:         "tcp",
+		MinActiveMembers: 1,
+	}, conf.ServiceGroups)
+
+	serviceGroup := &model.ServiceGroup{Name: "sg", Method: "round-robin"}
+	conf.ServiceGroups.Apply(serviceGroup)
+	suite.Assert().Equal("round-robin", serviceGroup.Method)
+	suite.Assert().Equal("tcp", serviceGroup.Protocol)
+	suite.Assert().Equal(1, serviceGroup.MinActiveMembers)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidServiceGroupDefaults() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config13.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+	suite.Assert().Contains(err.Error(), "sctp")
+}
+
+func (suite *TestSuite) TestBuildConfig_serverDefaults() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config14.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(100000, conf.Servers.ConnLimit)
+	suite.Assert().Equal(config.ServerOnFailureKeep, conf.Servers.OnFailure)
+
+	node := &model.Node{Name: "node1", ConnLimit: 10, SlowStart: "false"}
+	err = conf.Servers.Apply(node, &model.Environment{Cluster: "dc-prod"})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(10, node.ConnLimit)
+	suite.Assert().Equal("false", node.SlowStart)
+	suite.Assert().Equal("k8s-nodes", node.Template)
+	suite.Assert().Equal("dc-prod/node1", node.Description)
+}
+
+func (suite *TestSuite) TestBuildConfig_defaultServerDescription() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	node := &model.Node{Name: "node1"}
+	err = conf.Servers.Apply(node, &model.Environment{Cluster: "dc-prod"})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("k8s dc-prod node node1", node.Description)
+	suite.Assert().Equal(0, node.ConnLimit)
+	suite.Assert().Equal("", node.SlowStart)
+	suite.Assert().Equal("1", node.Weight)
+	suite.Assert().False(conf.Servers.Weight.NeedsControllerPods())
+}
+
+func (suite *TestSuite) TestBuildConfig_cpuWeights() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config17.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	nodes := []*model.Node{
+		&model.Node{Name: "small", AllocatableCPU: 500},
+		&model.Node{Name: "medium", AllocatableCPU: 15600},
+		&model.Node{Name: "large", AllocatableCPU: 96000},
+		&model.Node{Name: "pinned", AllocatableCPU: 96000, Weight: "7"},
+	}
+	for _, node := range nodes {
+		suite.Assert().Nil(conf.Servers.Apply(node, &model.Environment{}))
+	}
+	suite.Assert().Equal("2", nodes[0].Weight)
+	suite.Assert().Equal("16", nodes[1].Weight)
+	suite.Assert().Equal(5, nodes[1].WeightThreshold)
+	suite.Assert().Equal("50", nodes[2].Weight)
+	suite.Assert().Equal("7", nodes[3].Weight)
+	suite.Assert().Equal(0, nodes[3].WeightThreshold)
+}
+
+func (suite *TestSuite) TestBuildConfig_formulaWeights() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config18.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().True(conf.Servers.Weight.NeedsControllerPods())
+	node := &model.Node{Name: "node1", AllocatableCPU: 8000, ControllerPods: 2}
+	suite.Assert().Nil(conf.Servers.Apply(node, &model.Environment{}))
+	suite.Assert().Equal("20", node.Weight)
+}
+
+func (suite *TestSuite) TestBuildConfig_formulaErrorLeavesWeightUnset() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config38.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	node := &model.Node{Name: "node1", Labels: map[string]string{"weight": "heavy"}}
+	suite.Assert().EqualError(conf.Servers.Apply(node, &model.Environment{}), "failed to compute weight. formula result 'heavy' is not a number")
+	suite.Assert().Empty(node.Weight)
+	node = &model.Node{Name: "node2"}
+	suite.Assert().Nil(conf.Servers.Apply(node, &model.Environment{}))
+	suite.Assert().Equal("10", node.Weight)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidWeights() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	for _, file := range []string{"testdata/config19.yaml", "testdata/config20.yaml"} {
+		os.Args = original[0:1]
+		os.Args = append(os.Args, "-interval=10")
+		os.Args = append(os.Args, "-a10-config="+file)
+		flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+		_, err := config.BuildConfig()
+
+		suite.Assert().NotNil(err, file)
+	}
+}
+
+func (suite *TestSuite) TestBuildConfig_trafficShifts() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config21.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("a10bridge-traffic-shifts", conf.TrafficShifts.ConfigMap)
+	suite.Assert().Len(conf.TrafficShifts.Shifts, 1)
+	shift := conf.TrafficShifts.Shifts[0]
+	suite.Assert().Equal("web-80", shift.ServiceGroup)
+	suite.Assert().Equal(config.ShiftSide{Cluster: "lga-k8s1"}, shift.Source)
+	suite.Assert().Equal([]int{10, 25, 50, 100}, shift.Steps)
+	suite.Assert().Equal(config.ShiftOnFailureRollback, shift.OnFailure)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidTrafficShift() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config22.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_clusters() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config23.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(conf.Clusters, 2)
+	suite.Assert().Equal("lga-k8s1", conf.Clusters[0].Context)
+	suite.Assert().Equal(*config.DefaultEnvironmentConfig(), conf.Clusters[0].Environment)
+	suite.Assert().Equal("/etc/a10bridge/kubeconfig", conf.Clusters[1].Kubeconfig)
+	suite.Assert().Equal("kube-system", conf.Clusters[1].Environment.Namespace)
+	suite.Assert().Equal("cluster-info", conf.Clusters[1].Environment.ConfigMap)
+	suite.Assert().Equal("name", conf.Clusters[1].Environment.NameKey)
+}
+
+func (suite *TestSuite) TestBuildConfig_loadBalancerWithClusters() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-load-balancer")
+	os.Args = append(os.Args, "-a10-config=testdata/config35.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_duplicateCluster() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config24.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_sources() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config25.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]config.SourceConfig{{Name: "lga-metal", File: "/etc/a10bridge/static.yaml"}}, conf.Sources)
+}
+
+func (suite *TestSuite) TestBuildConfig_duplicateSource() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config26.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_appliedState() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config27.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().True(conf.AppliedState.Enabled())
+	suite.Assert().Equal(config.AppliedStateConfig{ConfigMap: "a10bridge-applied-state", KeepManualChanges: true}, conf.AppliedState)
+}
+
+func (suite *TestSuite) TestBuildConfig_appliedStateInFileAndConfigMap() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config28.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_removalLimits() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-allow-mass-removal")
+	os.Args = append(os.Args, "-a10-config=testdata/config29.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(config.RemovalLimits{
+		MaxRemovedPercent:       30,
+		MinRemainingMembers:     2,
+		MaxRemovedMembersPerRun: 10,
+		ServiceGroups: map[string]config.ServiceGroupLimits{
+			"big-group": config.ServiceGroupLimits{MaxRemovedPercent: 50, MinRemainingMembers: 10},
+		},
+	}, conf.RemovalLimits)
+	suite.Assert().Equal(config.ServiceGroupLimits{MaxRemovedPercent: 50, MinRemainingMembers: 10}, conf.RemovalLimits.ServiceGroup("big-group"))
+	suite.Assert().Equal(config.ServiceGroupLimits{MaxRemovedPercent: 30, MinRemainingMembers: 2}, conf.RemovalLimits.ServiceGroup("small-group"))
+	suite.Assert().True(*conf.Arguments.AllowMassRemoval)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidRemovalLimits() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config30.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidServiceGroupRemovalLimits() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config36.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().EqualError(err, "invalid removal limits of service group big-group, removal limits can't be negative")
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidServerOnFailure() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config31.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_canary() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config32.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().True(conf.Canary.Enabled())
+	suite.Assert().Equal(config.CanaryConfig{Instance: "lga-lb01", Wait: 5, MaxDownMembers: 1, Revert: true}, conf.Canary)
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryDefaultWait() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=300")
+	os.Args = append(os.Args, "-a10-config=testdata/config32.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(60, conf.Canary.Wait)
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryWaitLongerThanInterval() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=60")
+	os.Args = append(os.Args, "-a10-config=testdata/config34.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryWaitLongerThanHalfOfInterval() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=100")
+	os.Args = append(os.Args, "-a10-config=testdata/config34.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().EqualError(err, "canary wait can be at most half of the interval of 100 seconds, got 60")
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryWaitOfHalfOfInterval() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=120")
+	os.Args = append(os.Args, "-a10-config=testdata/config34.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(60, conf.Canary.Wait)
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryRevertWithoutAppliedState() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config37.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().EqualError(err, "canary revert needs applied state kept in a file or in a config map")
+}
+
+func (suite *TestSuite) TestBuildConfig_canaryInstanceNotConfigured() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config33.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_invalidServerDescription() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config15.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_loadBalancerDisabled() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-a10-config=testdata/config8.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Nil(conf.LoadBalancer)
+}
+
+func (suite *TestSuite) TestBuildConfig_loadBalancerWithoutPool() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-load-balancer")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_webhookWithoutCertificate() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-webhook-addr=:8443")
+	os.Args = append(os.Args, "-webhook-key=tls.key")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuite) TestBuildConfig_webhook() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-webhook-addr=:8443")
+	os.Args = append(os.Args, "-webhook-cert=tls.crt")
+	os.Args = append(os.Args, "-webhook-key=tls.key")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(":8443", *conf.Arguments.WebhookAddr)
+}
+
+func (suite *TestSuite) TestBuildConfig_kubeconfig() {
+	os.Setenv("KUBECONFIG", "/etc/a10bridge/kubeconfig")
+	defer os.Unsetenv("KUBECONFIG")
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-context=lga-k8s1")
+	os.Args = append(os.Args, "-master=https://10.0.0.1:6443")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	conf, err := config.BuildConfig()
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("/etc/a10bridge/kubeconfig", *conf.Arguments.Kubeconfig)
+	suite.Assert().Equal("lga-k8s1", *conf.Arguments.Context)
+	suite.Assert().Equal("https://10.0.0.1:6443", *conf.Arguments.Master)
+	suite.Assert().False(*conf.Arguments.InCluster)
+}
+
+func (suite *TestSuite) TestBuildConfig_inClusterWithKubeconfig() {
+	original := os.Args
+	defer func() { os.Args = original }()
+
+	os.Args = original[0:1]
+	os.Args = append(os.Args, "-interval=10")
+	os.Args = append(os.Args, "-in-cluster")
+	os.Args = append(os.Args, "-kubeconfig=/etc/a10bridge/kubeconfig")
+	os.Args = append(os.Args, "-a10-config=testdata/config1.yaml")
+	flag.CommandLine = flag.NewFlagSet("", flag.PanicOnError)
+	_, err := config.BuildConfig()
+
+	suite.Assert().NotNil(err)
+}
+
 func (suite *TestSuite) TestBuildConfig_debugMode() {
 	original := os.Args
 	defer func() { os.Args = original }()
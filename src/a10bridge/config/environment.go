@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+//defaults matching the original cluster-configs convention, cluster name in form datacenter-type
+const (
+	defaultEnvironmentNamespace = "ingress"
+	defaultEnvironmentConfigMap = "cluster-configs"
+	defaultEnvironmentNameKey   = "name"
+	defaultEnvironmentPattern   = "^(?P<dataCenter>[^-]*)(-(?P<type>[^-]*))?"
+)
+
+//EnvironmentConfig location of the config map describing the cluster and rules for parsing the cluster name
+type EnvironmentConfig struct {
+	Namespace string `yaml:"namespace"`
+	ConfigMap string `yaml:"configMap"`
+	NameKey   string `yaml:"nameKey"`
+	Pattern   string `yaml:"pattern"`
+}
+
+//NamePattern compiles the pattern used for splitting the cluster name, named groups dataCenter and type are recognized
+func (config EnvironmentConfig) NamePattern() (*regexp.Regexp, error) {
+	pattern, err := regexp.Compile(config.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment pattern '%s'. error: %s", config.Pattern, err)
+	}
+	return pattern, nil
+}
+
+func (config *EnvironmentConfig) applyDefaults() {
+	if len(config.Namespace) == 0 {
+		config.Namespace = defaultEnvironmentNamespace
+	}
+	if len(config.ConfigMap) == 0 {
+		config.ConfigMap = defaultEnvironmentConfigMap
+	}
+	if len(config.NameKey) == 0 {
+		config.NameKey = defaultEnvironmentNameKey
+	}
+	if len(config.Pattern) == 0 {
+		config.Pattern = defaultEnvironmentPattern
+	}
+}
+
+func (config EnvironmentConfig) validate() error {
+	_, err := config.NamePattern()
+	return err
+}
+
+//DefaultEnvironmentConfig builds environment configuration with default values
+func DefaultEnvironmentConfig() *EnvironmentConfig {
+	config := &EnvironmentConfig{}
+	config.applyDefaults()
+	return config
+}
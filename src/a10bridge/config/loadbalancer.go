@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+)
+
+const maxVIPPoolSize = 65536
+
+//LoadBalancerConfig configuration of the controller for kubernetes services of type LoadBalancer
+type LoadBalancerConfig struct {
+	NamePrefix string   `yaml:"namePrefix"`
+	VIPPool    []string `yaml:"vipPool"`
+}
+
+//VIPs expands the configured pool entries, either cidrs or ranges in form first-last, into a list of addresses
+func (config LoadBalancerConfig) VIPs() ([]string, error) {
+	vips := make([]string, 0)
+	for _, entry := range config.VIPPool {
+		first, last, err := parsePoolEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		current := new(big.Int).SetBytes(first)
+		end := new(big.Int).SetBytes(last)
+		for current.Cmp(end) <= 0 {
+			if len(vips) >= maxVIPPoolSize {
+				return nil, fmt.Errorf("vip pool can't have more than %d addresses", maxVIPPoolSize)
+			}
+			vips = append(vips, toIP(current, len(first)).String())
+			current.Add(current, big.NewInt(1))
+		}
+	}
+	return vips, nil
+}
+
+func (config LoadBalancerConfig) validate() error {
+	if len(config.VIPPool) == 0 {
+		return fmt.Errorf("load balancer vip pool is required")
+	}
+	_, err := config.VIPs()
+	return err
+}
+
+//parsePoolEntry returns the first and the last usable address of a pool entry
+func parsePoolEntry(entry string) (net.IP, net.IP, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		first := normalizeIP(network.IP)
+		last := make(net.IP, len(first))
+		for idx := range first {
+			last[idx] = first[idx] | ^network.Mask[idx]
+		}
+		ones, bits := network.Mask.Size()
+		if bits == 32 && bits-ones > 1 {
+			//skip network and broadcast addresses
+			first = toIP(new(big.Int).Add(new(big.Int).SetBytes(first), big.NewInt(1)), len(first))
+			last = toIP(new(big.Int).Sub(new(big.Int).SetBytes(last), big.NewInt(1)), len(last))
+		}
+		return first, last, nil
+	}
+
+	parts := strings.Split(entry, "-")
+	if len(parts) > 2 {
+		return nil, nil, fmt.Errorf("invalid vip pool entry '%s'", entry)
+	}
+	first := normalizeIP(net.ParseIP(strings.TrimSpace(parts[0])))
+	last := first
+	if len(parts) == 2 {
+		last = normalizeIP(net.ParseIP(strings.TrimSpace(parts[1])))
+	}
+	if first == nil || last == nil || len(first) != len(last) {
+		return nil, nil, fmt.Errorf("invalid vip pool entry '%s'", entry)
+	}
+	if new(big.Int).SetBytes(first).Cmp(new(big.Int).SetBytes(last)) > 0 {
+		return nil, nil, fmt.Errorf("invalid vip pool entry '%s', first address is greater than the last one", entry)
+	}
+	return first, last, nil
+}
+
+func normalizeIP(ip net.IP) net.IP {
+	if ip == nil {
+		return nil
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4
+	}
+	return ip.To16()
+}
+
+func toIP(value *big.Int, length int) net.IP {
+	binary := value.Bytes()
+	ip := make(net.IP, length)
+	copy(ip[length-len(binary):], binary)
+	return ip
+}
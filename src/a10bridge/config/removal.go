@@ -0,0 +1,64 @@
+package config
+
+import "fmt"
+
+//RemovalLimits safety limits of member removals guarding against truncated node lists or selectors matching nothing, zero means no limit.
+//Service group limits apply to each service group, the run limit to all service groups of all a10 instances in a single run.
+//ServiceGroups overrides the service group limits of the named service groups, the overridden service group keeps no global service group limit
+type RemovalLimits struct {
+	MaxRemovedPercent       int                           `yaml:"maxRemovedPercent"`
+	MaxRemovedMembers       int                           `yaml:"maxRemovedMembers"`
+	MinRemainingMembers     int                           `yaml:"minRemainingMembers"`
+	MaxRemovedMembersPerRun int                           `yaml:"maxRemovedMembersPerRun"`
+	ServiceGroups           map[string]ServiceGroupLimits `yaml:"serviceGroups"`
+}
+
+//ServiceGroupLimits removal limits of a single service group, zero means no limit
+type ServiceGroupLimits struct {
+	MaxRemovedPercent   int `yaml:"maxRemovedPercent"`
+	MaxRemovedMembers   int `yaml:"maxRemovedMembers"`
+	MinRemainingMembers int `yaml:"minRemainingMembers"`
+}
+
+//ServiceGroup removal limits of the service group, its override or the global service group limits
+func (limits RemovalLimits) ServiceGroup(name string) ServiceGroupLimits {
+	if override, found := limits.ServiceGroups[name]; found {
+		return override
+	}
+	return limits.serviceGroupDefaults()
+}
+
+func (limits RemovalLimits) serviceGroupDefaults() ServiceGroupLimits {
+	return ServiceGroupLimits{
+		MaxRemovedPercent:   limits.MaxRemovedPercent,
+		MaxRemovedMembers:   limits.MaxRemovedMembers,
+		MinRemainingMembers: limits.MinRemainingMembers,
+	}
+}
+
+func (limits RemovalLimits) validate() error {
+	if limits.MaxRemovedMembersPerRun < 0 {
+		return fmt.Errorf("removal limits can't be negative")
+	}
+	err := limits.serviceGroupDefaults().validate()
+	if err != nil {
+		return err
+	}
+	for name, override := range limits.ServiceGroups {
+		err = override.validate()
+		if err != nil {
+			return fmt.Errorf("invalid removal limits of service group %s, %s", name, err)
+		}
+	}
+	return nil
+}
+
+func (limits ServiceGroupLimits) validate() error {
+	if limits.MaxRemovedPercent < 0 || limits.MaxRemovedPercent > 100 {
+		return fmt.Errorf("removal limit maxRemovedPercent has to be between 0 and 100, got %d", limits.MaxRemovedPercent)
+	}
+	if limits.MaxRemovedMembers < 0 || limits.MinRemainingMembers < 0 {
+		return fmt.Errorf("removal limits can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+//InstanceSelector limits service groups synced into a10 instance, every configured condition has to match
+type InstanceSelector struct {
+	//ServiceGroups patterns of service group names, one of them has to match
+	ServiceGroups []string `yaml:"serviceGroups"`
+	//Labels labels one of the ingress controllers of the service group has to have
+	Labels map[string]string `yaml:"labels"`
+	//Environment expected values of environment fields dataCenter, cluster, type or keys of the environment config map
+	Environment map[string]string `yaml:"environment"`
+}
+
+func (selector InstanceSelector) validate() error {
+	for _, pattern := range selector.ServiceGroups {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid service group pattern '%s'. error: %s", pattern, err)
+		}
+	}
+	return nil
+}
+
+//Matches checks the service group with the name, labels of its ingress controllers and environment it is built in satisfies the selector
+func (selector InstanceSelector) Matches(serviceGroupName string, labelSets []map[string]string, environment map[string]string) bool {
+	if len(selector.ServiceGroups) > 0 && !matchesAnyPattern(selector.ServiceGroups, serviceGroupName) {
+		return false
+	}
+	if len(selector.Labels) > 0 && !anyContainsAll(labelSets, selector.Labels) {
+		return false
+	}
+	if len(selector.Environment) > 0 && !containsAll(environment, selector.Environment) {
+		return false
+	}
+	return true
+}
+
+func matchesAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		matched, err := regexp.MatchString(pattern, value)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func anyContainsAll(sets []map[string]string, expected map[string]string) bool {
+	for _, set := range sets {
+		if containsAll(set, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(set map[string]string, expected map[string]string) bool {
+	for key, value := range expected {
+		if actual, exists := set[key]; !exists || actual != value {
+			return false
+		}
+	}
+	return true
+}
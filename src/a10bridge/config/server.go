@@ -0,0 +1,96 @@
+package config
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const defaultServerDescription = "k8s {{.Cluster}} node {{.Name}}"
+
+//what happens to service group members of nodes whose servers fail to sync
+const (
+	ServerOnFailureRemove = "remove"
+	ServerOnFailureKeep   = "keep"
+)
+
+//ServerOnFailureActions all supported failure actions
+var ServerOnFailureActions = []string{
+	ServerOnFailureRemove,
+	ServerOnFailureKeep,
+}
+
+//ServerDefaults settings applied to a10 servers of nodes which don't set them through annotations
+type ServerDefaults struct {
+	ConnLimit   int    `yaml:"connLimit"`
+	SlowStart   *bool  `yaml:"slowStart"`
+	Description string `yaml:"description"`
+	Template    string `yaml:"template"`
+	//Weight strategy computing weights of nodes without a10.server.weight annotation
+	Weight WeightConfig `yaml:"weight"`
+	//OnFailure policy for members of nodes whose servers fail to sync, they are either removed or kept as they are
+	OnFailure string `yaml:"onFailure"`
+}
+
+func (defaults *ServerDefaults) applyDefaults() {
+	if len(defaults.Description) == 0 {
+		defaults.Description = defaultServerDescription
+	}
+	if len(defaults.OnFailure) == 0 {
+		defaults.OnFailure = ServerOnFailureRemove
+	}
+	defaults.Weight.applyDefaults()
+}
+
+func (defaults ServerDefaults) validate() error {
+	if defaults.ConnLimit < 0 || defaults.ConnLimit > 8000000 {
+		return fmt.Errorf("server connLimit has to be between 1 and 8000000, got %d", defaults.ConnLimit)
+	}
+	if len(defaults.Template) > 0 {
+		err := util.ValidateA10Name(defaults.Template)
+		if err != nil {
+			return fmt.Errorf("server template is invalid. %s", err)
+		}
+	}
+	_, err := util.ApplyTemplate(&model.TemplateData{Environment: &model.Environment{}}, defaults.Description)
+	if err != nil {
+		return fmt.Errorf("server description template '%s' can't be rendered. %s", defaults.Description, err)
+	}
+	if !util.Contains(ServerOnFailureActions, defaults.OnFailure) {
+		return fmt.Errorf("server onFailure '%s' is not supported, use one of %s", defaults.OnFailure, strings.Join(ServerOnFailureActions, ", "))
+	}
+	return defaults.Weight.validate()
+}
+
+//Apply fills server attributes the node doesn't have with the configured defaults, the description is rendered as a template
+//and the weight is computed by the weight strategy
+func (defaults ServerDefaults) Apply(node *model.Node, environment *model.Environment) error {
+	//the weight is left unset when it can't be computed, the other defaults are applied anyway
+	weightErr := defaults.Weight.Apply(node)
+	if node.ConnLimit == 0 {
+		node.ConnLimit = defaults.ConnLimit
+	}
+	if len(node.SlowStart) == 0 && defaults.SlowStart != nil {
+		node.SlowStart = strconv.FormatBool(*defaults.SlowStart)
+	}
+	if len(node.Template) == 0 {
+		node.Template = defaults.Template
+	}
+	if len(node.Description) == 0 && len(defaults.Description) > 0 {
+		description, err := util.ApplyTemplate(&model.TemplateData{
+			Environment: environment,
+			Name:        node.Name,
+			Labels:      node.Labels,
+		}, defaults.Description)
+		if err != nil {
+			return err
+		}
+		node.Description = util.SanitizeA10Description(description)
+	}
+	if weightErr != nil {
+		return fmt.Errorf("failed to compute weight. %s", weightErr)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"strings"
+)
+
+//ServiceGroupDefaults settings applied to service groups which don't set them through annotations or resources
+type ServiceGroupDefaults struct {
+	Method           string `yaml:"method"`
+	Protocol         string `yaml:"protocol"`
+	MinActiveMembers int    `yaml:"minActiveMembers"`
+}
+
+func (defaults ServiceGroupDefaults) validate() error {
+	if len(defaults.Method) > 0 && !util.Contains(model.LBMethods, defaults.Method) {
+		return fmt.Errorf("service group method '%s' is not supported, use one of %s", defaults.Method, strings.Join(model.LBMethods, ", "))
+	}
+	if len(defaults.Protocol) > 0 && !util.Contains(model.Protocols, defaults.Protocol) {
+		return fmt.Errorf("service group protocol '%s' is not supported, use one of %s", defaults.Protocol, strings.Join(model.Protocols, ", "))
+	}
+	if defaults.MinActiveMembers < 0 || defaults.MinActiveMembers > 1024 {
+		return fmt.Errorf("service group minActiveMembers has to be between 0 and 1024, got %d", defaults.MinActiveMembers)
+	}
+	return nil
+}
+
+//Apply fills settings the service group doesn't have with the configured defaults
+func (defaults ServiceGroupDefaults) Apply(serviceGroup *model.ServiceGroup) {
+	if len(serviceGroup.Method) == 0 {
+		serviceGroup.Method = defaults.Method
+	}
+	if len(serviceGroup.Protocol) == 0 {
+		serviceGroup.Protocol = defaults.Protocol
+	}
+	if serviceGroup.MinActiveMembers == 0 {
+		serviceGroup.MinActiveMembers = defaults.MinActiveMembers
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+//SourceConfig static file declaring servers and service groups which don't run in kubernetes, the name stands in for the cluster of its objects
+type SourceConfig struct {
+	Name string `yaml:"name"`
+	File string `yaml:"file"`
+}
+
+func (source SourceConfig) String() string {
+	return fmt.Sprintf("'%s' from file '%s'", source.Name, source.File)
+}
+
+func validateSources(sources []SourceConfig) error {
+	for idx, source := range sources {
+		if len(source.Name) == 0 || len(source.File) == 0 {
+			return fmt.Errorf("source %d needs both name and file", idx)
+		}
+		for _, other := range sources[:idx] {
+			if other.Name == source.Name {
+				return fmt.Errorf("source name '%s' is used more than once", source.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//what happens when members go down during a traffic shift
+const (
+	ShiftOnFailureStop     = "stop"
+	ShiftOnFailureRollback = "rollback"
+)
+
+//ShiftOnFailureActions all supported failure actions
+var ShiftOnFailureActions = []string{
+	ShiftOnFailureStop,
+	ShiftOnFailureRollback,
+}
+
+//priorities of members of a shifted service group, members of the side which doesn't take traffic become backup members
+const (
+	shiftActiveMemberPriority = model.MemberPriorityDefault + 1
+	shiftBackupMemberPriority = model.MemberPriorityDefault
+)
+
+//TrafficShiftsConfig traffic shifts and the config map their progress is persisted in
+type TrafficShiftsConfig struct {
+	ConfigMap string               `yaml:"configMap"`
+	Shifts    []TrafficShiftConfig `yaml:"shifts"`
+}
+
+//TrafficShiftConfig gradual move of traffic of a service group from members of the source ingress controllers to members of the target ones.
+//Traffic is split by server weights, so the service group should use a weighted load balancing method
+type TrafficShiftConfig struct {
+	Name         string    `yaml:"name"`
+	ServiceGroup string    `yaml:"serviceGroup"`
+	Source       ShiftSide `yaml:"source"`
+	Target       ShiftSide `yaml:"target"`
+	//Steps percentages of traffic sent to the target, the last one has to be 100
+	Steps []int `yaml:"steps"`
+	//Hold minutes each step is held before moving to the next one
+	Hold int `yaml:"hold"`
+	//MaxDownMembers members allowed to go down during a step before the shift fails
+	MaxDownMembers int    `yaml:"maxDownMembers"`
+	OnFailure      string `yaml:"onFailure"`
+}
+
+//ShiftSide ingress controllers on one side of a traffic shift, selected by cluster, name or both
+type ShiftSide struct {
+	Cluster    string `yaml:"cluster"`
+	Controller string `yaml:"controller"`
+}
+
+//Matches checks the ingress controller is on this side of the shift
+func (side ShiftSide) Matches(controller *model.IngressController) bool {
+	if len(side.Cluster) > 0 && side.Cluster != controller.Cluster {
+		return false
+	}
+	if len(side.Controller) > 0 && side.Controller != controller.Name {
+		return false
+	}
+	return true
+}
+
+func (side ShiftSide) String() string {
+	return fmt.Sprintf("cluster '%s' controller '%s'", side.Cluster, side.Controller)
+}
+
+func (shifts *TrafficShiftsConfig) applyDefaults() {
+	if len(shifts.ConfigMap) == 0 {
+		shifts.ConfigMap = "a10bridge-traffic-shifts"
+	}
+	for idx := range shifts.Shifts {
+		if len(shifts.Shifts[idx].OnFailure) == 0 {
+			shifts.Shifts[idx].OnFailure = ShiftOnFailureStop
+		}
+	}
+}
+
+func (shifts TrafficShiftsConfig) validate() error {
+	names := make([]string, 0)
+	for _, shift := range shifts.Shifts {
+		if util.Contains(names, shift.Name) {
+			return fmt.Errorf("traffic shift name '%s' is used more than once", shift.Name)
+		}
+		names = append(names, shift.Name)
+		err := shift.validate()
+		if err != nil {
+			return fmt.Errorf("traffic shift '%s' is invalid. %s", shift.Name, err)
+		}
+	}
+	return nil
+}
+
+func (shift TrafficShiftConfig) validate() error {
+	if len(shift.Name) == 0 {
+		return fmt.Errorf("name is required")
+	}
+	if len(shift.ServiceGroup) == 0 {
+		return fmt.Errorf("serviceGroup is required")
+	}
+	if shift.Source == (ShiftSide{}) || shift.Target == (ShiftSide{}) {
+		return fmt.Errorf("both source and target need a cluster or a controller")
+	}
+	if shift.Source == shift.Target {
+		return fmt.Errorf("source and target are the same")
+	}
+	if len(shift.Steps) == 0 || shift.Steps[len(shift.Steps)-1] != 100 {
+		return fmt.Errorf("steps have to end with 100, got %v", shift.Steps)
+	}
+	previous := 0
+	for _, step := range shift.Steps {
+		if step <= previous {
+			return fmt.Errorf("steps have to grow between 1 and 100, got %v", shift.Steps)
+		}
+		previous = step
+	}
+	if shift.Hold <= 0 {
+		return fmt.Errorf("hold has to be positive, got %d", shift.Hold)
+	}
+	if shift.MaxDownMembers < 0 {
+		return fmt.Errorf("maxDownMembers can't be negative, got %d", shift.MaxDownMembers)
+	}
+	if !util.Contains(ShiftOnFailureActions, shift.OnFailure) {
+		return fmt.Errorf("onFailure '%s' is not supported, use one of %s", shift.OnFailure, strings.Join(ShiftOnFailureActions, ", "))
+	}
+	return nil
+}
+
+//Advance starts the shift when there is no state and moves a running shift to the next step once the current one was held long enough
+func (shift TrafficShiftConfig) Advance(state *model.TrafficShiftState, now time.Time) *model.TrafficShiftState {
+	if state == nil {
+		glog.Infof("Starting traffic shift %s at %d%%", shift.Name, shift.Steps[0])
+		return &model.TrafficShiftState{
+			Status:      model.ShiftStatusRunning,
+			StepStarted: now.UTC().Format(time.RFC3339),
+		}
+	}
+	if !state.Running() {
+		return state
+	}
+
+	started, err := time.Parse(time.RFC3339, state.StepStarted)
+	if err == nil && now.Sub(started) < time.Duration(shift.Hold)*time.Minute {
+		return state
+	}
+	if state.Step >= len(shift.Steps)-1 {
+		glog.Infof("Traffic shift %s has completed", shift.Name)
+		state.Status = model.ShiftStatusCompleted
+		state.DownMembers = nil
+		return state
+	}
+	state.Step++
+	state.StepStarted = now.UTC().Format(time.RFC3339)
+	state.DownMembers = nil
+	glog.Infof("Traffic shift %s moves to %d%%", shift.Name, shift.Steps[state.Step])
+	return state
+}
+
+//CheckDownMembers fails a running shift when more members are down in the a10 instance than when the step started
+func (shift TrafficShiftConfig) CheckDownMembers(state *model.TrafficShiftState, instance string, down int) {
+	if !state.Running() {
+		return
+	}
+	if state.DownMembers == nil {
+		state.DownMembers = make(map[string]int)
+	}
+	baseline, exists := state.DownMembers[instance]
+	if !exists {
+		state.DownMembers[instance] = down
+		return
+	}
+	if down-baseline <= shift.MaxDownMembers {
+		return
+	}
+
+	state.Message = fmt.Sprintf("%d members of service group %s are down in a10 %s at %d%%, %d were down when the step started", down, shift.ServiceGroup, instance, shift.Steps[state.Step], baseline)
+	if shift.OnFailure == ShiftOnFailureRollback {
+		state.Status = model.ShiftStatusRolledBack
+	} else {
+		state.Status = model.ShiftStatusStopped
+	}
+	glog.Warningf("Traffic shift %s is %s. %s", shift.Name, state.Status, state.Message)
+}
+
+//Percentage share of traffic sent to the target in the state
+func (shift TrafficShiftConfig) Percentage(state *model.TrafficShiftState) int {
+	switch state.Status {
+	case model.ShiftStatusCompleted:
+		return 100
+	case model.ShiftStatusRolledBack:
+		return 0
+	}
+	return shift.Steps[state.Step]
+}
+
+//Apply splits traffic of the service group between the sides by weights of their nodes, at 0 and 100 percent
+//the side without traffic is kept as backup. Weights are set on servers so they affect all service groups of the nodes, nodes are keyed by a10 server
+func (shift TrafficShiftConfig) Apply(percentage int, serviceGroups []*model.ServiceGroup, nodes map[string]*model.Node) {
+	sourceNodes := make(map[string]*model.Node)
+	targetNodes := make(map[string]*model.Node)
+	for _, serviceGroup := range serviceGroups {
+		if serviceGroup.Name != shift.ServiceGroup {
+			continue
+		}
+		for _, controller := range serviceGroup.IngressControllers {
+			for _, node := range controller.Nodes {
+				if shift.Source.Matches(controller) {
+					sourceNodes[node.A10Server] = node
+				}
+				if shift.Target.Matches(controller) {
+					targetNodes[node.A10Server] = node
+				}
+			}
+		}
+	}
+	for serverName := range sourceNodes {
+		if _, exists := targetNodes[serverName]; exists {
+			glog.Warningf("A10 server %s is on both sides of traffic shift %s, leaving it out", serverName, shift.Name)
+			delete(sourceNodes, serverName)
+			delete(targetNodes, serverName)
+		}
+	}
+	if len(sourceNodes) == 0 || len(targetNodes) == 0 {
+		glog.Warningf("Traffic shift %s found %d source and %d target nodes in service group %s, nothing to shift", shift.Name, len(sourceNodes), len(targetNodes), shift.ServiceGroup)
+		return
+	}
+
+	sourcePriority, targetPriority := shiftActiveMemberPriority, shiftActiveMemberPriority
+	switch percentage {
+	case 0:
+		targetPriority = shiftBackupMemberPriority
+	case 100:
+		sourcePriority = shiftBackupMemberPriority
+	default:
+		sourceWeight, targetWeight := shiftWeights(percentage, len(sourceNodes), len(targetNodes))
+		setShiftWeight(sourceNodes, sourceWeight, nodes)
+		setShiftWeight(targetNodes, targetWeight, nodes)
+	}
+
+	for _, serviceGroup := range serviceGroups {
+		if serviceGroup.Name != shift.ServiceGroup {
+			continue
+		}
+		if serviceGroup.MemberPriorities == nil {
+			serviceGroup.MemberPriorities = make(map[string]int)
+		}
+		for _, node := range sourceNodes {
+			serviceGroup.MemberPriorities[node.A10Server] = sourcePriority
+		}
+		for _, node := range targetNodes {
+			serviceGroup.MemberPriorities[node.A10Server] = targetPriority
+		}
+	}
+}
+
+//shiftWeights server weights of both sides sending the percentage of traffic to the target, the side taking more traffic gets the max weight
+func shiftWeights(percentage, sourceCount, targetCount int) (int, int) {
+	if percentage <= 50 {
+		return maxServerWeight, boundWeight(float64(maxServerWeight*percentage*sourceCount) / float64((100-percentage)*targetCount))
+	}
+	return boundWeight(float64(maxServerWeight*(100-percentage)*targetCount) / float64(percentage*sourceCount)), maxServerWeight
+}
+
+func boundWeight(value float64) int {
+	weight := int(math.Round(value))
+	if weight < minServerWeight {
+		return minServerWeight
+	}
+	if weight > maxServerWeight {
+		return maxServerWeight
+	}
+	return weight
+}
+
+//setShiftWeight sets the weight on the nodes synced into a10, which may be other objects than the ones of ingress controllers
+func setShiftWeight(sideNodes map[string]*model.Node, weight int, nodes map[string]*model.Node) {
+	for serverName, node := range sideNodes {
+		for _, target := range []*model.Node{node, nodes[serverName]} {
+			if target != nil {
+				target.Weight = strconv.Itoa(weight)
+				target.WeightThreshold = 0
+			}
+		}
+	}
+}
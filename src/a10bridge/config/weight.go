@@ -0,0 +1,144 @@
+package config
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//weight strategies, how weights of nodes without a10.server.weight annotation are computed
+const (
+	WeightStrategyStatic  = "static"
+	WeightStrategyCPU     = "cpu"
+	WeightStrategyPods    = "pods"
+	WeightStrategyFormula = "formula"
+)
+
+//WeightStrategies all supported weight strategies
+var WeightStrategies = []string{
+	WeightStrategyStatic,
+	WeightStrategyCPU,
+	WeightStrategyPods,
+	WeightStrategyFormula,
+}
+
+const (
+	minServerWeight = util.A10WeightMin
+	maxServerWeight = util.A10WeightMax
+)
+
+//WeightConfig strategy computing server weights, computed weights are kept within min and max
+//and a10 servers are only updated when their weight differs by at least the threshold
+type WeightConfig struct {
+	Strategy  string `yaml:"strategy"`
+	Formula   string `yaml:"formula"`
+	Min       int    `yaml:"min"`
+	Max       int    `yaml:"max"`
+	Threshold int    `yaml:"threshold"`
+}
+
+//WeightData data available to weight formulas
+type WeightData struct {
+	Name      string
+	Labels    map[string]string
+	CPU       float64
+	MemoryGiB float64
+	Pods      int
+}
+
+func (weights *WeightConfig) applyDefaults() {
+	if len(weights.Strategy) == 0 {
+		weights.Strategy = WeightStrategyStatic
+	}
+	if weights.Min == 0 {
+		weights.Min = minServerWeight
+	}
+	if weights.Max == 0 {
+		weights.Max = maxServerWeight
+	}
+}
+
+func (weights WeightConfig) validate() error {
+	if !util.Contains(WeightStrategies, weights.Strategy) {
+		return fmt.Errorf("weight strategy '%s' is not supported, use one of %s", weights.Strategy, strings.Join(WeightStrategies, ", "))
+	}
+	if weights.Min < minServerWeight || weights.Max > maxServerWeight || weights.Min > weights.Max {
+		return fmt.Errorf("weight min %d and max %d have to be between %d and %d", weights.Min, weights.Max, minServerWeight, maxServerWeight)
+	}
+	if weights.Threshold < 0 {
+		return fmt.Errorf("weight threshold can't be negative, got %d", weights.Threshold)
+	}
+	if weights.Strategy == WeightStrategyFormula {
+		if len(strings.TrimSpace(weights.Formula)) == 0 {
+			return fmt.Errorf("weight formula is required by the %s strategy", WeightStrategyFormula)
+		}
+		_, err := weights.evaluate(&WeightData{})
+		if err != nil {
+			return fmt.Errorf("weight formula '%s' can't be evaluated. %s", weights.Formula, err)
+		}
+	}
+	return nil
+}
+
+//NeedsControllerPods checks the strategy uses numbers of ready ingress controller pods
+func (weights WeightConfig) NeedsControllerPods() bool {
+	return weights.Strategy == WeightStrategyPods || weights.Strategy == WeightStrategyFormula
+}
+
+//Apply computes the weight of the node unless it is set by annotation. The weight is left unset when it can't be computed,
+//so its server keeps the weight it has in a10
+func (weights WeightConfig) Apply(node *model.Node) error {
+	if len(node.Weight) > 0 {
+		return nil
+	}
+	weights.applyDefaults()
+	node.WeightThreshold = weights.Threshold
+
+	data := &WeightData{
+		Name:      node.Name,
+		Labels:    node.Labels,
+		CPU:       float64(node.AllocatableCPU) / 1000,
+		MemoryGiB: float64(node.AllocatableMemory) / (1 << 30),
+		Pods:      node.ControllerPods,
+	}
+	var value float64
+	var err error
+	switch weights.Strategy {
+	case WeightStrategyCPU:
+		value = data.CPU
+	case WeightStrategyPods:
+		value = float64(data.Pods)
+	case WeightStrategyFormula:
+		value, err = weights.evaluate(data)
+	default:
+		value = minServerWeight
+	}
+	if err != nil {
+		return err
+	}
+
+	weight := int(math.Round(value))
+	if weight < weights.Min {
+		weight = weights.Min
+	}
+	if weight > weights.Max {
+		weight = weights.Max
+	}
+	node.Weight = strconv.Itoa(weight)
+	return nil
+}
+
+func (weights WeightConfig) evaluate(data *WeightData) (float64, error) {
+	result, err := util.ApplyTemplate(data, weights.Formula)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(result), 64)
+	if err != nil {
+		return 0, fmt.Errorf("formula result '%s' is not a number", result)
+	}
+	return value, nil
+}
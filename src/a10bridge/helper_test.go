@@ -1,18 +1,24 @@
 package main
 
 import (
+	"a10bridge/apiserver"
 	"a10bridge/config"
 	"a10bridge/processor"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 )
 
 type TestHelper struct{}
 
-type BuildK8sProcessorFunc func() (processor.K8sProcessor, error)
+type BuildK8sProcessorFunc func(clientConfig apiserver.ClientConfig) (processor.K8sProcessor, error)
+type BuildClusterK8sProcessorFunc func(cluster config.ClusterConfig) (processor.K8sProcessor, error)
+type BuildStateSourcesFunc func(sources []config.SourceConfig) []processor.StateSource
 type BuildConfigFunc func() (*config.RunContext, error)
 type BuildA10ProcessorsFunc func(a10instance *config.A10Instance) (*processor.A10Processors, error)
+type SleepFunc func(duration time.Duration)
+type WebhookStartFunc func(address, certFile, keyFile string, clientConfig apiserver.ClientConfig, environmentConfig *config.EnvironmentConfig) error
 
 var syncMutex = new(sync.Mutex)
 
@@ -24,6 +30,22 @@ func (helper TestHelper) SetBuildK8sProcessorFunc(replacement BuildK8sProcessorF
 	return old
 }
 
+func (helper TestHelper) SetBuildStateSourcesFunc(replacement BuildStateSourcesFunc) BuildStateSourcesFunc {
+	syncMutex.Lock()
+	old := processorBuildStateSources
+	processorBuildStateSources = replacement
+	syncMutex.Unlock()
+	return old
+}
+
+func (helper TestHelper) SetBuildClusterK8sProcessorFunc(replacement BuildClusterK8sProcessorFunc) BuildClusterK8sProcessorFunc {
+	syncMutex.Lock()
+	old := processorBuildClusterK8sProcessor
+	processorBuildClusterK8sProcessor = replacement
+	syncMutex.Unlock()
+	return old
+}
+
 func (helper TestHelper) SetBuildConfigFunc(replacement BuildConfigFunc) BuildConfigFunc {
 	old := configBuildConfig
 	configBuildConfig = replacement
@@ -36,3 +58,15 @@ func (helper TestHelper) SetBuildA10ProcessorsFunc(replacement BuildA10Processor
 	processorBuildA10Processors = replacement
 	return old
 }
+
+func (helper TestHelper) SetWebhookStartFunc(replacement WebhookStartFunc) WebhookStartFunc {
+	old := webhookStart
+	webhookStart = replacement
+	return old
+}
+
+func (helper TestHelper) SetSleepFunc(replacement SleepFunc) SleepFunc {
+	old := timeSleep
+	timeSleep = replacement
+	return old
+}
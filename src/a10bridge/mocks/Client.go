@@ -90,6 +90,38 @@ func (_m *Client) CreateServiceGroup(serviceGroup *model.ServiceGroup) api.A10Er
 	return r0
 }
 
+// CreateVirtualServer provides a mock function with given fields: virtualServer
+func (_m *Client) CreateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	ret := _m.Called(virtualServer)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(*model.VirtualServer) api.A10Error); ok {
+		r0 = rf(virtualServer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteHealthMonitor provides a mock function with given fields: monitorName
+func (_m *Client) DeleteHealthMonitor(monitorName string) api.A10Error {
+	ret := _m.Called(monitorName)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(string) api.A10Error); ok {
+		r0 = rf(monitorName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
 // DeleteMember provides a mock function with given fields: member
 func (_m *Client) DeleteMember(member *model.Member) api.A10Error {
 	ret := _m.Called(member)
@@ -106,6 +138,54 @@ func (_m *Client) DeleteMember(member *model.Member) api.A10Error {
 	return r0
 }
 
+// DeleteServer provides a mock function with given fields: serverName
+func (_m *Client) DeleteServer(serverName string) api.A10Error {
+	ret := _m.Called(serverName)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(string) api.A10Error); ok {
+		r0 = rf(serverName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteServiceGroup provides a mock function with given fields: serviceGroupName
+func (_m *Client) DeleteServiceGroup(serviceGroupName string) api.A10Error {
+	ret := _m.Called(serviceGroupName)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(string) api.A10Error); ok {
+		r0 = rf(serviceGroupName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteVirtualServer provides a mock function with given fields: virtualServerName
+func (_m *Client) DeleteVirtualServer(virtualServerName string) api.A10Error {
+	ret := _m.Called(virtualServerName)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(string) api.A10Error); ok {
+		r0 = rf(virtualServerName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
 // GetHealthMonitor provides a mock function with given fields: monitorName
 func (_m *Client) GetHealthMonitor(monitorName string) (*model.HealthCheck, api.A10Error) {
 	ret := _m.Called(monitorName)
@@ -131,6 +211,31 @@ func (_m *Client) GetHealthMonitor(monitorName string) (*model.HealthCheck, api.
 	return r0, r1
 }
 
+// GetMemberStatuses provides a mock function with given fields: serviceGroupName
+func (_m *Client) GetMemberStatuses(serviceGroupName string) ([]*model.MemberStatus, api.A10Error) {
+	ret := _m.Called(serviceGroupName)
+
+	var r0 []*model.MemberStatus
+	if rf, ok := ret.Get(0).(func(string) []*model.MemberStatus); ok {
+		r0 = rf(serviceGroupName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.MemberStatus)
+		}
+	}
+
+	var r1 api.A10Error
+	if rf, ok := ret.Get(1).(func(string) api.A10Error); ok {
+		r1 = rf(serviceGroupName)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(api.A10Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetServer provides a mock function with given fields: serverName
 func (_m *Client) GetServer(serverName string) (*model.Node, api.A10Error) {
 	ret := _m.Called(serverName)
@@ -181,6 +286,56 @@ func (_m *Client) GetServiceGroup(serviceGroupName string) (*model.ServiceGroup,
 	return r0, r1
 }
 
+// GetVirtualServer provides a mock function with given fields: virtualServerName
+func (_m *Client) GetVirtualServer(virtualServerName string) (*model.VirtualServer, api.A10Error) {
+	ret := _m.Called(virtualServerName)
+
+	var r0 *model.VirtualServer
+	if rf, ok := ret.Get(0).(func(string) *model.VirtualServer); ok {
+		r0 = rf(virtualServerName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.VirtualServer)
+		}
+	}
+
+	var r1 api.A10Error
+	if rf, ok := ret.Get(1).(func(string) api.A10Error); ok {
+		r1 = rf(virtualServerName)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(api.A10Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetVirtualServers provides a mock function with given fields:
+func (_m *Client) GetVirtualServers() ([]*model.VirtualServer, api.A10Error) {
+	ret := _m.Called()
+
+	var r0 []*model.VirtualServer
+	if rf, ok := ret.Get(0).(func() []*model.VirtualServer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.VirtualServer)
+		}
+	}
+
+	var r1 api.A10Error
+	if rf, ok := ret.Get(1).(func() api.A10Error); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(api.A10Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // IsHealthMonitorNotFound provides a mock function with given fields: err
 func (_m *Client) IsHealthMonitorNotFound(err api.A10Error) bool {
 	ret := _m.Called(err)
@@ -237,6 +392,20 @@ func (_m *Client) IsServiceGroupNotFound(err api.A10Error) bool {
 	return r0
 }
 
+// IsVirtualServerNotFound provides a mock function with given fields: err
+func (_m *Client) IsVirtualServerNotFound(err api.A10Error) bool {
+	ret := _m.Called(err)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(api.A10Error) bool); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // UpdateHealthMonitor provides a mock function with given fields: monitor
 func (_m *Client) UpdateHealthMonitor(monitor *model.HealthCheck) api.A10Error {
 	ret := _m.Called(monitor)
@@ -253,6 +422,22 @@ func (_m *Client) UpdateHealthMonitor(monitor *model.HealthCheck) api.A10Error {
 	return r0
 }
 
+// UpdateMember provides a mock function with given fields: member
+func (_m *Client) UpdateMember(member *model.Member) api.A10Error {
+	ret := _m.Called(member)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(*model.Member) api.A10Error); ok {
+		r0 = rf(member)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
+
 // UpdateServer provides a mock function with given fields: server
 func (_m *Client) UpdateServer(server *model.Node) api.A10Error {
 	ret := _m.Called(server)
@@ -284,3 +469,19 @@ func (_m *Client) UpdateServiceGroup(serviceGroup *model.ServiceGroup) api.A10Er
 
 	return r0
 }
+
+// UpdateVirtualServer provides a mock function with given fields: virtualServer
+func (_m *Client) UpdateVirtualServer(virtualServer *model.VirtualServer) api.A10Error {
+	ret := _m.Called(virtualServer)
+
+	var r0 api.A10Error
+	if rf, ok := ret.Get(0).(func(*model.VirtualServer) api.A10Error); ok {
+		r0 = rf(virtualServer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.A10Error)
+		}
+	}
+
+	return r0
+}
@@ -9,13 +9,27 @@ type HealthCheckProcessor struct {
 	mock.Mock
 }
 
-// ProcessHealthCheck provides a mock function with given fields: healthCheck
-func (_m *HealthCheckProcessor) ProcessHealthCheck(healthCheck *model.HealthCheck) error {
-	ret := _m.Called(healthCheck)
+// ProcessHealthCheck provides a mock function with given fields: healthCheck, serviceGroup
+func (_m *HealthCheckProcessor) ProcessHealthCheck(healthCheck *model.HealthCheck, serviceGroup *model.ServiceGroup) error {
+	ret := _m.Called(healthCheck, serviceGroup)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.HealthCheck) error); ok {
-		r0 = rf(healthCheck)
+	if rf, ok := ret.Get(0).(func(*model.HealthCheck, *model.ServiceGroup) error); ok {
+		r0 = rf(healthCheck, serviceGroup)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevertHealthCheck provides a mock function with given fields: healthCheckName, previous
+func (_m *HealthCheckProcessor) RevertHealthCheck(healthCheckName string, previous *model.HealthCheck) error {
+	ret := _m.Called(healthCheckName, previous)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *model.HealthCheck) error); ok {
+		r0 = rf(healthCheckName, previous)
 	} else {
 		r0 = ret.Error(0)
 	}
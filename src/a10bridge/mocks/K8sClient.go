@@ -9,6 +9,29 @@ type K8sClient struct {
 	mock.Mock
 }
 
+// GetA10ServiceGroups provides a mock function with given fields:
+func (_m *K8sClient) GetA10ServiceGroups() ([]*model.A10ServiceGroup, error) {
+	ret := _m.Called()
+
+	var r0 []*model.A10ServiceGroup
+	if rf, ok := ret.Get(0).(func() []*model.A10ServiceGroup); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.A10ServiceGroup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetConfigMap provides a mock function with given fields: namespace, name
 func (_m *K8sClient) GetConfigMap(namespace string, name string) (*model.ConfigMap, error) {
 	ret := _m.Called(namespace, name)
@@ -55,6 +78,29 @@ func (_m *K8sClient) GetIngressControllers() ([]*model.IngressController, error)
 	return r0, r1
 }
 
+// GetLoadBalancers provides a mock function with given fields:
+func (_m *K8sClient) GetLoadBalancers() ([]*model.LoadBalancer, error) {
+	ret := _m.Called()
+
+	var r0 []*model.LoadBalancer
+	if rf, ok := ret.Get(0).(func() []*model.LoadBalancer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoadBalancer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNodes provides a mock function with given fields:
 func (_m *K8sClient) GetNodes() ([]*model.Node, error) {
 	ret := _m.Called()
@@ -77,3 +123,105 @@ func (_m *K8sClient) GetNodes() ([]*model.Node, error) {
 
 	return r0, r1
 }
+
+// GetPodNodeNames provides a mock function with given fields: namespace, podSelectors
+func (_m *K8sClient) GetPodNodeNames(namespace string, podSelectors map[string]string) ([]string, error) {
+	ret := _m.Called(namespace, podSelectors)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, map[string]string) []string); ok {
+		r0 = rf(namespace, podSelectors)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, map[string]string) error); ok {
+		r1 = rf(namespace, podSelectors)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadyPodCounts provides a mock function with given fields: namespace, podSelectors
+func (_m *K8sClient) GetReadyPodCounts(namespace string, podSelectors map[string]string) (map[string]int, error) {
+	ret := _m.Called(namespace, podSelectors)
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func(string, map[string]string) map[string]int); ok {
+		r0 = rf(namespace, podSelectors)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, map[string]string) error); ok {
+		r1 = rf(namespace, podSelectors)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishSyncStatus provides a mock function with given fields: kind, namespace, name, events, statuses
+func (_m *K8sClient) PublishSyncStatus(kind string, namespace string, name string, events []*model.SyncEvent, statuses map[string]*model.SyncStatus) error {
+	ret := _m.Called(kind, namespace, name, events, statuses)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, []*model.SyncEvent, map[string]*model.SyncStatus) error); ok {
+		r0 = rf(kind, namespace, name, events, statuses)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveConfigMap provides a mock function with given fields: configMap
+func (_m *K8sClient) SaveConfigMap(configMap *model.ConfigMap) error {
+	ret := _m.Called(configMap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.ConfigMap) error); ok {
+		r0 = rf(configMap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateA10ServiceGroupStatus provides a mock function with given fields: serviceGroup, results
+func (_m *K8sClient) UpdateA10ServiceGroupStatus(serviceGroup *model.A10ServiceGroup, results []*model.SyncResult) error {
+	ret := _m.Called(serviceGroup, results)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.A10ServiceGroup, []*model.SyncResult) error); ok {
+		r0 = rf(serviceGroup, results)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLoadBalancerStatus provides a mock function with given fields: loadBalancer
+func (_m *K8sClient) UpdateLoadBalancerStatus(loadBalancer *model.LoadBalancer) error {
+	ret := _m.Called(loadBalancer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.LoadBalancer) error); ok {
+		r0 = rf(loadBalancer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
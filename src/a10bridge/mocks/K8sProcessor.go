@@ -1,6 +1,7 @@
 // Code generated by mockery v1.0.0
 package mocks
 
+import config "a10bridge/config"
 import mock "github.com/stretchr/testify/mock"
 import model "a10bridge/model"
 
@@ -9,13 +10,18 @@ type K8sProcessor struct {
 	mock.Mock
 }
 
-// BuildEnvironment provides a mock function with given fields:
-func (_m *K8sProcessor) BuildEnvironment() (*model.Environment, error) {
-	ret := _m.Called()
+// AssignVIPs provides a mock function with given fields: loadBalancers, vips
+func (_m *K8sProcessor) AssignVIPs(loadBalancers []*model.LoadBalancer, vips []string) {
+	_m.Called(loadBalancers, vips)
+}
+
+// BuildEnvironment provides a mock function with given fields: environmentConfig
+func (_m *K8sProcessor) BuildEnvironment(environmentConfig *config.EnvironmentConfig) (*model.Environment, error) {
+	ret := _m.Called(environmentConfig)
 
 	var r0 *model.Environment
-	if rf, ok := ret.Get(0).(func() *model.Environment); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(*config.EnvironmentConfig) *model.Environment); ok {
+		r0 = rf(environmentConfig)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Environment)
@@ -23,8 +29,8 @@ func (_m *K8sProcessor) BuildEnvironment() (*model.Environment, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(*config.EnvironmentConfig) error); ok {
+		r1 = rf(environmentConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -32,13 +38,45 @@ func (_m *K8sProcessor) BuildEnvironment() (*model.Environment, error) {
 	return r0, r1
 }
 
-// BuildServiceGroups provides a mock function with given fields: controllers, environment
-func (_m *K8sProcessor) BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment) map[string]*model.ServiceGroup {
-	ret := _m.Called(controllers, environment)
+// BuildLoadBalancerServiceGroups provides a mock function with given fields: loadBalancers, nodes, namePrefix
+func (_m *K8sProcessor) BuildLoadBalancerServiceGroups(loadBalancers []*model.LoadBalancer, nodes []*model.Node, namePrefix string) map[string]*model.ServiceGroup {
+	ret := _m.Called(loadBalancers, nodes, namePrefix)
+
+	var r0 map[string]*model.ServiceGroup
+	if rf, ok := ret.Get(0).(func([]*model.LoadBalancer, []*model.Node, string) map[string]*model.ServiceGroup); ok {
+		r0 = rf(loadBalancers, nodes, namePrefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*model.ServiceGroup)
+		}
+	}
+
+	return r0
+}
+
+// BuildResourceServiceGroups provides a mock function with given fields: resources, environment
+func (_m *K8sProcessor) BuildResourceServiceGroups(resources []*model.A10ServiceGroup, environment *model.Environment) map[string]*model.ServiceGroup {
+	ret := _m.Called(resources, environment)
+
+	var r0 map[string]*model.ServiceGroup
+	if rf, ok := ret.Get(0).(func([]*model.A10ServiceGroup, *model.Environment) map[string]*model.ServiceGroup); ok {
+		r0 = rf(resources, environment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*model.ServiceGroup)
+		}
+	}
+
+	return r0
+}
+
+// BuildServiceGroups provides a mock function with given fields: controllers, environment, instance
+func (_m *K8sProcessor) BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment, instance string) map[string]*model.ServiceGroup {
+	ret := _m.Called(controllers, environment, instance)
 
 	var r0 map[string]*model.ServiceGroup
-	if rf, ok := ret.Get(0).(func([]*model.IngressController, *model.Environment) map[string]*model.ServiceGroup); ok {
-		r0 = rf(controllers, environment)
+	if rf, ok := ret.Get(0).(func([]*model.IngressController, *model.Environment, string) map[string]*model.ServiceGroup); ok {
+		r0 = rf(controllers, environment, instance)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]*model.ServiceGroup)
@@ -48,6 +86,29 @@ func (_m *K8sProcessor) BuildServiceGroups(controllers []*model.IngressControlle
 	return r0
 }
 
+// CountControllerPods provides a mock function with given fields: controllers
+func (_m *K8sProcessor) CountControllerPods(controllers []*model.IngressController) (map[string]int, error) {
+	ret := _m.Called(controllers)
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func([]*model.IngressController) map[string]int); ok {
+		r0 = rf(controllers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*model.IngressController) error); ok {
+		r1 = rf(controllers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindIngressControllers provides a mock function with given fields:
 func (_m *K8sProcessor) FindIngressControllers() ([]*model.IngressController, error) {
 	ret := _m.Called()
@@ -71,6 +132,29 @@ func (_m *K8sProcessor) FindIngressControllers() ([]*model.IngressController, er
 	return r0, r1
 }
 
+// FindLoadBalancers provides a mock function with given fields:
+func (_m *K8sProcessor) FindLoadBalancers() ([]*model.LoadBalancer, error) {
+	ret := _m.Called()
+
+	var r0 []*model.LoadBalancer
+	if rf, ok := ret.Get(0).(func() []*model.LoadBalancer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoadBalancer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindNodes provides a mock function with given fields: nodeSelectors
 func (_m *K8sProcessor) FindNodes(nodeSelectors map[string]string) ([]*model.Node, error) {
 	ret := _m.Called(nodeSelectors)
@@ -93,3 +177,142 @@ func (_m *K8sProcessor) FindNodes(nodeSelectors map[string]string) ([]*model.Nod
 
 	return r0, r1
 }
+
+// FindServiceGroupResources provides a mock function with given fields:
+func (_m *K8sProcessor) FindServiceGroupResources() ([]*model.A10ServiceGroup, error) {
+	ret := _m.Called()
+
+	var r0 []*model.A10ServiceGroup
+	if rf, ok := ret.Get(0).(func() []*model.A10ServiceGroup); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.A10ServiceGroup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAppliedStates provides a mock function with given fields: namespace, configMapName
+func (_m *K8sProcessor) GetAppliedStates(namespace string, configMapName string) (map[string]*model.AppliedState, error) {
+	ret := _m.Called(namespace, configMapName)
+
+	var r0 map[string]*model.AppliedState
+	if rf, ok := ret.Get(0).(func(string, string) map[string]*model.AppliedState); ok {
+		r0 = rf(namespace, configMapName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*model.AppliedState)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, configMapName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrafficShiftStates provides a mock function with given fields: namespace, configMapName
+func (_m *K8sProcessor) GetTrafficShiftStates(namespace string, configMapName string) (map[string]*model.TrafficShiftState, error) {
+	ret := _m.Called(namespace, configMapName)
+
+	var r0 map[string]*model.TrafficShiftState
+	if rf, ok := ret.Get(0).(func(string, string) map[string]*model.TrafficShiftState); ok {
+		r0 = rf(namespace, configMapName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*model.TrafficShiftState)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, configMapName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishLoadBalancerStatus provides a mock function with given fields: loadBalancer
+func (_m *K8sProcessor) PublishLoadBalancerStatus(loadBalancer *model.LoadBalancer) error {
+	ret := _m.Called(loadBalancer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.LoadBalancer) error); ok {
+		r0 = rf(loadBalancer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishSyncEvents provides a mock function with given fields: events
+func (_m *K8sProcessor) PublishSyncEvents(events []*model.SyncEvent) error {
+	ret := _m.Called(events)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.SyncEvent) error); ok {
+		r0 = rf(events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAppliedStates provides a mock function with given fields: namespace, configMapName, states
+func (_m *K8sProcessor) SaveAppliedStates(namespace string, configMapName string, states map[string]*model.AppliedState) error {
+	ret := _m.Called(namespace, configMapName, states)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, map[string]*model.AppliedState) error); ok {
+		r0 = rf(namespace, configMapName, states)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTrafficShiftStates provides a mock function with given fields: namespace, configMapName, states
+func (_m *K8sProcessor) SaveTrafficShiftStates(namespace string, configMapName string, states map[string]*model.TrafficShiftState) error {
+	ret := _m.Called(namespace, configMapName, states)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, map[string]*model.TrafficShiftState) error); ok {
+		r0 = rf(namespace, configMapName, states)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateServiceGroupResourceStatus provides a mock function with given fields: resource, results
+func (_m *K8sProcessor) UpdateServiceGroupResourceStatus(resource *model.A10ServiceGroup, results []*model.SyncResult) error {
+	ret := _m.Called(resource, results)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.A10ServiceGroup, []*model.SyncResult) error); ok {
+		r0 = rf(resource, results)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
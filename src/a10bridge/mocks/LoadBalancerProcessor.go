@@ -0,0 +1,38 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+import model "a10bridge/model"
+
+// LoadBalancerProcessor is an autogenerated mock type for the LoadBalancerProcessor type
+type LoadBalancerProcessor struct {
+	mock.Mock
+}
+
+// DeleteStaleLoadBalancers provides a mock function with given fields: loadBalancers, namePrefix
+func (_m *LoadBalancerProcessor) DeleteStaleLoadBalancers(loadBalancers []*model.LoadBalancer, namePrefix string) error {
+	ret := _m.Called(loadBalancers, namePrefix)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.LoadBalancer, string) error); ok {
+		r0 = rf(loadBalancers, namePrefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProcessLoadBalancer provides a mock function with given fields: loadBalancer, failedServiceGroupNames
+func (_m *LoadBalancerProcessor) ProcessLoadBalancer(loadBalancer *model.LoadBalancer, failedServiceGroupNames []string) error {
+	ret := _m.Called(loadBalancer, failedServiceGroupNames)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.LoadBalancer, []string) error); ok {
+		r0 = rf(loadBalancer, failedServiceGroupNames)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -22,3 +22,17 @@ func (_m *NodeProcessor) ProcessNode(node *model.Node) error {
 
 	return r0
 }
+
+// RevertServer provides a mock function with given fields: serverName, previous
+func (_m *NodeProcessor) RevertServer(serverName string, previous *model.Node) error {
+	ret := _m.Called(serverName, previous)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *model.Node) error); ok {
+		r0 = rf(serverName, previous)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -9,13 +9,48 @@ type ServiceGroupProcessor struct {
 	mock.Mock
 }
 
-// ProcessServiceGroup provides a mock function with given fields: serviceGroup, failedNodeNames
-func (_m *ServiceGroupProcessor) ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedNodeNames []string) error {
-	ret := _m.Called(serviceGroup, failedNodeNames)
+// CountDownMembers provides a mock function with given fields: serviceGroupName
+func (_m *ServiceGroupProcessor) CountDownMembers(serviceGroupName string) (int, error) {
+	ret := _m.Called(serviceGroupName)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(serviceGroupName)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceGroupName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessServiceGroup provides a mock function with given fields: serviceGroup, failedServers
+func (_m *ServiceGroupProcessor) ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedServers []string) error {
+	ret := _m.Called(serviceGroup, failedServers)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(*model.ServiceGroup, []string) error); ok {
-		r0 = rf(serviceGroup, failedNodeNames)
+		r0 = rf(serviceGroup, failedServers)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevertServiceGroup provides a mock function with given fields: serviceGroupName, previous
+func (_m *ServiceGroupProcessor) RevertServiceGroup(serviceGroupName string, previous *model.ServiceGroup) error {
+	ret := _m.Called(serviceGroupName, previous)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *model.ServiceGroup) error); ok {
+		r0 = rf(serviceGroupName, previous)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,47 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+import model "a10bridge/model"
+
+// StateSource is an autogenerated mock type for the StateSource type
+type StateSource struct {
+	mock.Mock
+}
+
+// Load provides a mock function with given fields:
+func (_m *StateSource) Load() (*model.SourceState, error) {
+	ret := _m.Called()
+
+	var r0 *model.SourceState
+	if rf, ok := ret.Get(0).(func() *model.SourceState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SourceState)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with given fields:
+func (_m *StateSource) Name() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
@@ -0,0 +1,25 @@
+package model
+
+//A10ServiceGroup service group declared through the A10ServiceGroup custom resource
+type A10ServiceGroup struct {
+	Name                     string
+	Namespace                string
+	Generation               int64
+	ServiceGroupNameTemplate string
+	ServiceGroupName         string
+	A10Instances             []string
+	NodeSelectors            map[string]string
+	PodSelectors             map[string]string
+	Ports                    []int
+	Health                   *HealthCheck
+	Method                   string
+	Protocol                 string
+	MinActiveMembers         int
+	Error                    string
+}
+
+//SyncResult result of syncing a service group into a single a10 instance
+type SyncResult struct {
+	Instance string
+	Error    string
+}
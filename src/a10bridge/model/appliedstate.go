@@ -0,0 +1,35 @@
+package model
+
+//AppliedState objects a10bridge last applied to an a10 instance keyed by their a10 names, a10 objects differing from it were changed by someone else
+type AppliedState struct {
+	Servers       map[string]*Node         `json:"servers"`
+	HealthChecks  map[string]*HealthCheck  `json:"healthChecks"`
+	ServiceGroups map[string]*ServiceGroup `json:"serviceGroups"`
+	//HaltedRollout fingerprint of the desired state whose rollout failed canary verification and was reverted, empty when there is none
+	HaltedRollout string `json:"haltedRollout,omitempty"`
+}
+
+//NewAppliedState builds applied state without any objects
+func NewAppliedState() *AppliedState {
+	return &AppliedState{
+		Servers:       make(map[string]*Node),
+		HealthChecks:  make(map[string]*HealthCheck),
+		ServiceGroups: make(map[string]*ServiceGroup),
+	}
+}
+
+//Copy copies the state, objects are shared as they are replaced rather than modified
+func (state *AppliedState) Copy() *AppliedState {
+	copied := NewAppliedState()
+	for name, server := range state.Servers {
+		copied.Servers[name] = server
+	}
+	for name, healthCheck := range state.HealthChecks {
+		copied.HealthChecks[name] = healthCheck
+	}
+	for name, serviceGroup := range state.ServiceGroups {
+		copied.ServiceGroups[name] = serviceGroup
+	}
+	copied.HaltedRollout = state.HaltedRollout
+	return copied
+}
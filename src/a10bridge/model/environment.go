@@ -5,4 +5,20 @@ type Environment struct {
 	DataCenter string
 	Cluster    string
 	Type       string
+	Data       map[string]string
+}
+
+//Fields environment as flat map, the config map data is overridden by the parsed cluster name fields
+func (environment *Environment) Fields() map[string]string {
+	fields := make(map[string]string)
+	if environment == nil {
+		return fields
+	}
+	for key, value := range environment.Data {
+		fields[key] = value
+	}
+	fields["dataCenter"] = environment.DataCenter
+	fields["cluster"] = environment.Cluster
+	fields["type"] = environment.Type
+	return fields
 }
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+//FieldChange difference of a single field of an a10 object, usually from its current to its desired value
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (change FieldChange) String() string {
+	return fmt.Sprintf("%s '%s' -> '%s'", change.Field, change.Old, change.New)
+}
+
+//FieldChanges differences of fields of an a10 object, empty when the object is in sync
+type FieldChanges []FieldChange
+
+//Fields names of the changed fields
+func (changes FieldChanges) Fields() []string {
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+//Reversed swaps old and new values of the changes
+func (changes FieldChanges) Reversed() FieldChanges {
+	reversed := make(FieldChanges, 0, len(changes))
+	for _, change := range changes {
+		reversed = append(reversed, FieldChange{Field: change.Field, Old: change.New, New: change.Old})
+	}
+	return reversed
+}
+
+func (changes FieldChanges) String() string {
+	described := make([]string, 0, len(changes))
+	for _, change := range changes {
+		described = append(described, change.String())
+	}
+	return strings.Join(described, ", ")
+}
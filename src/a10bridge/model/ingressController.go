@@ -3,9 +3,54 @@ package model
 //IngressController ingress controller data structure
 type IngressController struct {
 	Name                     string
+	Cluster                  string
+	Namespace                string
+	Labels                   map[string]string
+	Annotations              map[string]string
 	NodeSelectors            map[string]string
+	PodSelectors             map[string]string
+	A10Instances             []string
 	Nodes                    []*Node
 	ServiceGroupNameTemplate string
 	Health                   *HealthCheck
 	Port                     int
+	PortName                 string
+	PortSource               string
+	Ports                    []*ControllerPort
+	Method                   string
+	Protocol                 string
+	MinActiveMembers         int
+	MemberPriority           int
 }
+
+//ControllerPort additional port of ingress controller load balanced through its own service group
+type ControllerPort struct {
+	Name                     string
+	Port                     int
+	Source                   string
+	ServiceGroupNameTemplate string
+	Health                   *HealthCheck
+}
+
+//PerPort splits ingress controller into one ingress controller per served port, the main port goes first
+func (controller *IngressController) PerPort() []*IngressController {
+	controllers := []*IngressController{controller}
+	for _, port := range controller.Ports {
+		portController := *controller
+		portController.Port = port.Port
+		portController.PortName = port.Name
+		portController.PortSource = port.Source
+		portController.ServiceGroupNameTemplate = port.ServiceGroupNameTemplate
+		portController.Health = port.Health
+		portController.Ports = nil
+		controllers = append(controllers, &portController)
+	}
+	return controllers
+}
+
+//port sources, where the member port of ingress controller comes from
+const (
+	PortSourceHostPort    = "hostPort"
+	PortSourceHostNetwork = "hostNetwork"
+	PortSourceNodePort    = "nodePort"
+)
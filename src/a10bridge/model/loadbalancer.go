@@ -0,0 +1,37 @@
+package model
+
+//LoadBalancer data structure for kubernetes service of type LoadBalancer
+type LoadBalancer struct {
+	Name        string
+	Namespace   string
+	RequestedIP string
+	VIP         string
+	//StatusVIP vip published in the load balancer status of the service
+	StatusVIP         string
+	VirtualServerName string
+	Ports             []*LoadBalancerPort
+}
+
+//LoadBalancerPort port exposed by kubernetes service of type LoadBalancer
+type LoadBalancerPort struct {
+	Name             string
+	Protocol         string
+	Port             int
+	NodePort         int
+	ServiceGroupName string
+}
+
+type LoadBalancers []*LoadBalancer
+
+func (s LoadBalancers) Len() int {
+	return len(s)
+}
+func (s LoadBalancers) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s LoadBalancers) Less(i, j int) bool {
+	if s[i].Namespace == s[j].Namespace {
+		return s[i].Name < s[j].Name
+	}
+	return s[i].Namespace < s[j].Namespace
+}
@@ -0,0 +1,124 @@
+package model
+
+import "strconv"
+
+//ManagedField field of an a10 object managed by a10bridge. Optional fields are managed only while the desired object sets them,
+//a10 keeps its own value of the field otherwise
+type ManagedField struct {
+	Name     string
+	Optional bool
+}
+
+//ManagedFields names of fields managed for a desired object, update requests send only these fields
+type ManagedFields map[string]bool
+
+//FieldValuer a10 object exposing values of its managed fields, set tells whether the object sets an optional field
+type FieldValuer interface {
+	FieldValue(name string) (value string, set bool)
+}
+
+//ServerFields fields of a10 servers managed by a10bridge, compared in this order
+var ServerFields = []ManagedField{
+	{Name: "ip"},
+	{Name: "ipv6"},
+	{Name: "weight", Optional: true},
+	{Name: "connLimit", Optional: true},
+	{Name: "slowStart", Optional: true},
+	{Name: "description", Optional: true},
+	{Name: "template", Optional: true},
+	{Name: "state", Optional: true},
+}
+
+//HealthCheckFields fields of a10 health monitors managed by a10bridge, compared in this order
+var HealthCheckFields = []ManagedField{
+	{Name: "endpoint"},
+	{Name: "expectCode"},
+	{Name: "interval"},
+	{Name: "port"},
+	{Name: "requiredConsecutivePasses"},
+	{Name: "retryCount"},
+	{Name: "timeout"},
+}
+
+//ServiceGroupFields settings of a10 service groups managed by a10bridge, compared in this order. Members are synced separately
+var ServiceGroupFields = []ManagedField{
+	{Name: "healthMonitor", Optional: true},
+	{Name: "method", Optional: true},
+	{Name: "protocol"},
+	{Name: "minActiveMembers", Optional: true},
+}
+
+//Managed fields managed for the desired object, the mandatory ones and the optional ones it sets
+func Managed(fields []ManagedField, desired FieldValuer) ManagedFields {
+	managed := make(ManagedFields)
+	for _, field := range fields {
+		_, set := desired.FieldValue(field.Name)
+		managed[field.Name] = !field.Optional || set
+	}
+	return managed
+}
+
+//FieldValue value of the managed server field
+func (node *Node) FieldValue(name string) (string, bool) {
+	switch name {
+	case "ip":
+		return node.IPAddress, len(node.IPAddress) > 0
+	case "ipv6":
+		return node.IPv6Address, len(node.IPv6Address) > 0
+	case "weight":
+		return node.Weight, len(node.Weight) > 0
+	case "connLimit":
+		return strconv.Itoa(node.ConnLimit), node.ConnLimit > 0
+	case "slowStart":
+		return node.SlowStart, len(node.SlowStart) > 0
+	case "description":
+		return node.Description, len(node.Description) > 0
+	case "template":
+		return node.Template, len(node.Template) > 0
+	case "state":
+		return node.State, len(node.State) > 0
+	}
+	return "", false
+}
+
+//FieldValue value of the managed health monitor field
+func (healthCheck *HealthCheck) FieldValue(name string) (string, bool) {
+	switch name {
+	case "endpoint":
+		return healthCheck.Endpoint, true
+	case "expectCode":
+		return healthCheck.ExpectCode, true
+	case "interval":
+		return strconv.Itoa(healthCheck.Interval), true
+	case "port":
+		return strconv.Itoa(healthCheck.Port), true
+	case "requiredConsecutivePasses":
+		return strconv.Itoa(healthCheck.RequiredConsecutivePasses), true
+	case "retryCount":
+		return strconv.Itoa(healthCheck.RetryCount), true
+	case "timeout":
+		return strconv.Itoa(healthCheck.Timeout), true
+	}
+	return "", false
+}
+
+//FieldValue value of the managed service group setting, min active members removed after being applied are set back to 0
+func (serviceGroup *ServiceGroup) FieldValue(name string) (string, bool) {
+	switch name {
+	case "healthMonitor":
+		if serviceGroup.Health == nil {
+			return "", false
+		}
+		return serviceGroup.Health.Name, true
+	case "method":
+		return serviceGroup.Method, len(serviceGroup.Method) > 0
+	case "protocol":
+		return serviceGroup.ProtocolOrDefault(), true
+	case "minActiveMembers":
+		if serviceGroup.ResetMinActiveMembers {
+			return "0", true
+		}
+		return strconv.Itoa(serviceGroup.MinActiveMembers), serviceGroup.MinActiveMembers > 0
+	}
+	return "", false
+}
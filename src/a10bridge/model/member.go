@@ -1,8 +1,33 @@
 package model
 
+//member priorities accepted by a10, members with lower priority only take traffic when all members with higher priority are down
+const (
+	MemberPriorityDefault = 1
+	MemberPriorityMax     = 16
+)
+
 //Member data structure for holding service group member details
 type Member struct {
 	ServerName       string
 	Port             int
 	ServiceGroupName string
+	//Priority of the member, zero means the a10 default
+	Priority int
+	//Source where the port comes from, only known for members built from kubernetes
+	Source string
+}
+
+//PriorityOrDefault priority of the member, the a10 default when not set
+func (member *Member) PriorityOrDefault() int {
+	if member.Priority == 0 {
+		return MemberPriorityDefault
+	}
+	return member.Priority
+}
+
+//MemberStatus health of a service group member as seen by the a10 health monitor
+type MemberStatus struct {
+	ServerName string
+	Port       int
+	Up         bool
 }
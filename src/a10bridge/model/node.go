@@ -1,12 +1,49 @@
 package model
 
-//Node node information holder
+//server states managed through a10.server.state annotation
+const (
+	ServerStateEnable  = "enable"
+	ServerStateDisable = "disable"
+)
+
+//Node node information holder, empty server attributes are not managed
 type Node struct {
-	Name      string
-	A10Server string
-	Weight    string
-	IPAddress string
-	Labels    map[string]string
+	Name        string
+	Cluster     string
+	A10Server   string
+	Weight      string
+	IPAddress   string
+	IPv6Address string
+	Labels      map[string]string
+	ConnLimit   int
+	SlowStart   string
+	Description string
+	Template    string
+	State       string
+	//MemberPriority priority of the node in service groups, zero when not set
+	MemberPriority int
+	//AllocatableCPU allocatable cpu of the node in millicores
+	AllocatableCPU int64
+	//AllocatableMemory allocatable memory of the node in bytes
+	AllocatableMemory int64
+	//ControllerPods number of ready ingress controller pods running on the node
+	ControllerPods int
+	//WeightThreshold smallest weight difference worth updating the a10 server for, computed weights only
+	WeightThreshold int
+}
+
+//zone labels of kubernetes nodes, the beta one is read when the node doesn't carry the current one
+const (
+	LabelZone     = "topology.kubernetes.io/zone"
+	LabelZoneBeta = "failure-domain.beta.kubernetes.io/zone"
+)
+
+//Zone topology zone of the node, empty when the node isn't labeled
+func (node *Node) Zone() string {
+	if zone, exists := node.Labels[LabelZone]; exists {
+		return zone
+	}
+	return node.Labels[LabelZoneBeta]
 }
 
 type Nodes []*Node
@@ -1,10 +1,55 @@
 package model
 
+//supported service group load balancing methods
+const (
+	LBMethodRoundRobin              = "round-robin"
+	LBMethodWeightedRoundRobin      = "weighted-rr"
+	LBMethodLeastConnection         = "least-connection"
+	LBMethodWeightedLeastConnection = "weighted-least-connection"
+	LBMethodFastestResponse         = "fastest-response"
+	LBMethodLeastRequest            = "least-request"
+)
+
+//LBMethods all supported load balancing methods
+var LBMethods = []string{
+	LBMethodRoundRobin,
+	LBMethodWeightedRoundRobin,
+	LBMethodLeastConnection,
+	LBMethodWeightedLeastConnection,
+	LBMethodFastestResponse,
+	LBMethodLeastRequest,
+}
+
+//supported service group protocols
+const (
+	ProtocolTCP = "tcp"
+	ProtocolUDP = "udp"
+)
+
+//Protocols all supported service group protocols
+var Protocols = []string{
+	ProtocolTCP,
+	ProtocolUDP,
+}
+
 type ServiceGroup struct {
 	Name               string
 	Health             *HealthCheck
+	Method             string
+	Protocol           string
+	MinActiveMembers   int
+	A10Instances       []string
+	Resource           *A10ServiceGroup
 	IngressControllers []*IngressController
 	Members            []*Member
+	//MemberPriorities priorities by a10 server name overriding the ones of nodes and ingress controllers
+	MemberPriorities map[string]int
+	//OwnedServers a10 servers of clusters whose state is known, when set members on other servers are never removed
+	OwnedServers []string
+	//KeptServers a10 servers of nodes which failed to sync, their members are left as they are
+	KeptServers []string
+	//ResetMinActiveMembers min active members applied by a10bridge before were removed, a10 has to go back to its default
+	ResetMinActiveMembers bool
 }
 
 type ServiceGroups []*ServiceGroup
@@ -18,3 +63,48 @@ func (s ServiceGroups) Swap(i, j int) {
 func (s ServiceGroups) Less(i, j int) bool {
 	return s[i].Name < s[j].Name
 }
+
+//TargetsInstance checks the service group should be synced into the a10 instance, service groups
+//of ingress controllers are skipped only when all of the controllers list instances and none of them is the one
+func (serviceGroup *ServiceGroup) TargetsInstance(name string) bool {
+	if len(serviceGroup.A10Instances) > 0 {
+		return containsString(serviceGroup.A10Instances, name)
+	}
+	if len(serviceGroup.IngressControllers) == 0 {
+		return true
+	}
+	for _, controller := range serviceGroup.IngressControllers {
+		if len(controller.A10Instances) == 0 || containsString(controller.A10Instances, name) {
+			return true
+		}
+	}
+	return false
+}
+
+//ProtocolOrDefault protocol of the service group, tcp when not set
+func (serviceGroup *ServiceGroup) ProtocolOrDefault() string {
+	if len(serviceGroup.Protocol) == 0 {
+		return ProtocolTCP
+	}
+	return serviceGroup.Protocol
+}
+
+//Labels labels of ingress controllers of the service group
+func (serviceGroup *ServiceGroup) Labels() []map[string]string {
+	labels := make([]map[string]string, 0)
+	for _, controller := range serviceGroup.IngressControllers {
+		if len(controller.Labels) > 0 {
+			labels = append(labels, controller.Labels)
+		}
+	}
+	return labels
+}
+
+func containsString(slice []string, lookFor string) bool {
+	for _, item := range slice {
+		if item == lookFor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package model
+
+//SourceState desired state declared outside of kubernetes, members of service groups are expressed through their ingress controllers
+type SourceState struct {
+	Nodes         []*Node
+	ServiceGroups []*ServiceGroup
+}
@@ -0,0 +1,52 @@
+package model
+
+//kinds of kubernetes objects sync events are recorded for
+const (
+	KindNode      = "Node"
+	KindDaemonSet = "DaemonSet"
+)
+
+//sync event types, matching kubernetes event types
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+//sync event reasons
+const (
+	ReasonCreated    = "Created"
+	ReasonUpdated    = "Updated"
+	ReasonDeleted    = "Deleted"
+	ReasonInSync     = "InSync"
+	ReasonSyncFailed = "SyncFailed"
+	//ReasonChangedOutOfBand a10 object differs from the state a10bridge last applied to it
+	ReasonChangedOutOfBand = "ChangedOutOfBand"
+	//ReasonMembershipKept node failed to sync and its members are left as they are
+	ReasonMembershipKept = "MembershipKept"
+)
+
+//sync status results
+const (
+	SyncResultSynced = "Synced"
+	SyncResultFailed = "Failed"
+)
+
+//SyncEvent outcome of syncing a kubernetes object into a single a10 instance
+type SyncEvent struct {
+	Cluster   string
+	Kind      string
+	Namespace string
+	Name      string
+	Instance  string
+	Type      string
+	Reason    string
+	Message   string
+}
+
+//SyncStatus last sync result of a kubernetes object on a single a10 instance, LastTransition is the time the result or its message changed
+type SyncStatus struct {
+	LastSync       string `json:"lastSync"`
+	LastTransition string `json:"lastTransition,omitempty"`
+	Result         string `json:"result"`
+	Message        string `json:"message,omitempty"`
+}
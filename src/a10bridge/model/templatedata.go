@@ -0,0 +1,29 @@
+package model
+
+//TemplateData data available to service group name templates, environment fields are accessible directly
+type TemplateData struct {
+	*Environment
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	Instance    string
+	Port        int
+	PortName    string
+	//Zone of the nodes, service groups are split by zone when the name depends on it
+	Zone string
+}
+
+//NewTemplateData builds template data for ingress controller rendered for the a10 instance
+func NewTemplateData(environment *Environment, controller *IngressController, instance string) *TemplateData {
+	return &TemplateData{
+		Environment: environment,
+		Name:        controller.Name,
+		Namespace:   controller.Namespace,
+		Labels:      controller.Labels,
+		Annotations: controller.Annotations,
+		Instance:    instance,
+		Port:        controller.Port,
+		PortName:    controller.PortName,
+	}
+}
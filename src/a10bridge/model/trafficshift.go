@@ -0,0 +1,26 @@
+package model
+
+//traffic shift statuses
+const (
+	ShiftStatusRunning    = "running"
+	ShiftStatusCompleted  = "completed"
+	ShiftStatusStopped    = "stopped"
+	ShiftStatusRolledBack = "rolledBack"
+)
+
+//TrafficShiftState progress of a traffic shift, persisted between runs so a restart resumes the shift
+type TrafficShiftState struct {
+	Status string `json:"status"`
+	//Step index of the current step of the schedule
+	Step int `json:"step"`
+	//StepStarted when the current step started, RFC3339
+	StepStarted string `json:"stepStarted"`
+	//DownMembers members down per a10 instance when the step started
+	DownMembers map[string]int `json:"downMembers,omitempty"`
+	Message     string         `json:"message,omitempty"`
+}
+
+//Running checks the shift still moves traffic and watches member health
+func (state *TrafficShiftState) Running() bool {
+	return state.Status == ShiftStatusRunning
+}
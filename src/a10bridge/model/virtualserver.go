@@ -0,0 +1,34 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+//virtualServerDescriptionPrefix marks virtual servers created by a10bridge, no other virtual servers are ever removed
+const virtualServerDescriptionPrefix = "a10bridge k8s service "
+
+//VirtualServer data structure for a10 virtual server
+type VirtualServer struct {
+	Name        string
+	IPAddress   string
+	Description string
+	Ports       []*VirtualPort
+}
+
+//VirtualServerDescription description marking virtual server of the kubernetes service as created by a10bridge
+func VirtualServerDescription(namespace, name string) string {
+	return fmt.Sprintf("%s%s/%s", virtualServerDescriptionPrefix, namespace, name)
+}
+
+//CreatedByA10bridge checks the virtual server carries the description a10bridge marks its virtual servers with
+func (virtualServer *VirtualServer) CreatedByA10bridge() bool {
+	return strings.HasPrefix(virtualServer.Description, virtualServerDescriptionPrefix)
+}
+
+//VirtualPort data structure for a10 virtual server port
+type VirtualPort struct {
+	Port             int
+	Protocol         string
+	ServiceGroupName string
+}
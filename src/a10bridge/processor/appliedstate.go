@@ -0,0 +1,234 @@
+package processor
+
+import (
+	"a10bridge/model"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+//AppliedStateTracker keeps state a10bridge applies to an a10 instance. Processors diff desired state and a10 objects against the
+//state applied by the previous run, a10 objects differing from it were changed out of band. Until tracking starts every difference is corrected
+type AppliedStateTracker struct {
+	previous          *model.AppliedState
+	current           *model.AppliedState
+	keepManualChanges bool
+}
+
+//NewAppliedStateTracker builds tracker which doesn't track until started
+func NewAppliedStateTracker() *AppliedStateTracker {
+	return &AppliedStateTracker{}
+}
+
+//Start tracks changes against the state applied by the previous run, out of band changes are left alone when
+//keepManualChanges is set and the desired state didn't change since it was last applied
+func (tracker *AppliedStateTracker) Start(previous *model.AppliedState, keepManualChanges bool) {
+	if tracker == nil {
+		return
+	}
+	if previous == nil {
+		previous = model.NewAppliedState()
+	}
+	tracker.previous = previous
+	tracker.current = previous.Copy()
+	tracker.keepManualChanges = keepManualChanges
+}
+
+//State state applied so far, nil when tracking hasn't started
+func (tracker *AppliedStateTracker) State() *model.AppliedState {
+	if tracker == nil {
+		return nil
+	}
+	return tracker.current
+}
+
+//HaltedRollout fingerprint of the desired state whose rollout was halted and reverted by the previous run, empty when there is none
+func (tracker *AppliedStateTracker) HaltedRollout() string {
+	if !tracker.tracking() {
+		return ""
+	}
+	return tracker.previous.HaltedRollout
+}
+
+//RolloutHalted keeps fingerprint of the desired state whose rollout was halted and reverted, empty clears it
+func (tracker *AppliedStateTracker) RolloutHalted(fingerprint string) {
+	if !tracker.tracking() {
+		return
+	}
+	tracker.current.HaltedRollout = fingerprint
+}
+
+func (tracker *AppliedStateTracker) tracking() bool {
+	return tracker != nil && tracker.previous != nil
+}
+
+//keeps decides whether an out of band change is left alone
+func (tracker *AppliedStateTracker) keeps(desiredUnchanged bool) bool {
+	return tracker.tracking() && tracker.keepManualChanges && desiredUnchanged
+}
+
+func (tracker *AppliedStateTracker) server(name string) *model.Node {
+	if !tracker.tracking() {
+		return nil
+	}
+	return tracker.previous.Servers[name]
+}
+
+func (tracker *AppliedStateTracker) serverApplied(node *model.Node) {
+	if !tracker.tracking() {
+		return
+	}
+	tracker.current.Servers[node.A10Server] = &model.Node{
+		A10Server:       node.A10Server,
+		IPAddress:       node.IPAddress,
+		IPv6Address:     node.IPv6Address,
+		Weight:          node.Weight,
+		WeightThreshold: node.WeightThreshold,
+		ConnLimit:       node.ConnLimit,
+		SlowStart:       node.SlowStart,
+		Description:     node.Description,
+		Template:        node.Template,
+		State:           node.State,
+	}
+}
+
+//serverReverted records the server as it was applied by the previous run
+func (tracker *AppliedStateTracker) serverReverted(name string) {
+	if !tracker.tracking() {
+		return
+	}
+	previous, exists := tracker.previous.Servers[name]
+	if !exists {
+		delete(tracker.current.Servers, name)
+		return
+	}
+	tracker.current.Servers[name] = previous
+}
+
+func (tracker *AppliedStateTracker) healthCheck(name string) *model.HealthCheck {
+	if !tracker.tracking() {
+		return nil
+	}
+	return tracker.previous.HealthChecks[name]
+}
+
+func (tracker *AppliedStateTracker) healthCheckApplied(healthCheck *model.HealthCheck) {
+	if !tracker.tracking() {
+		return
+	}
+	applied := *healthCheck
+	tracker.current.HealthChecks[healthCheck.Name] = &applied
+}
+
+//healthCheckReverted records the health monitor as it was applied by the previous run
+func (tracker *AppliedStateTracker) healthCheckReverted(name string) {
+	if !tracker.tracking() {
+		return
+	}
+	previous, exists := tracker.previous.HealthChecks[name]
+	if !exists {
+		delete(tracker.current.HealthChecks, name)
+		return
+	}
+	tracker.current.HealthChecks[name] = previous
+}
+
+func (tracker *AppliedStateTracker) serviceGroup(name string) *model.ServiceGroup {
+	if !tracker.tracking() {
+		return nil
+	}
+	return tracker.previous.ServiceGroups[name]
+}
+
+//serviceGroupApplied records settings of the service group with the members a10 is known to have
+func (tracker *AppliedStateTracker) serviceGroupApplied(serviceGroup *model.ServiceGroup, members []*model.Member) {
+	if !tracker.tracking() {
+		return
+	}
+	applied := &model.ServiceGroup{
+		Name:             serviceGroup.Name,
+		Method:           serviceGroup.Method,
+		Protocol:         serviceGroup.Protocol,
+		MinActiveMembers: serviceGroup.MinActiveMembers,
+		Members:          members,
+	}
+	if serviceGroup.Health != nil {
+		applied.Health = &model.HealthCheck{Name: serviceGroup.Health.Name}
+	}
+	tracker.current.ServiceGroups[serviceGroup.Name] = applied
+}
+
+//serviceGroupReverted records the service group as it was applied by the previous run
+func (tracker *AppliedStateTracker) serviceGroupReverted(name string) {
+	if !tracker.tracking() {
+		return
+	}
+	previous, exists := tracker.previous.ServiceGroups[name]
+	if !exists {
+		delete(tracker.current.ServiceGroups, name)
+		return
+	}
+	tracker.current.ServiceGroups[name] = previous
+}
+
+//AppliedStateStore keeps states applied to a10 instances between runs, keyed by instance name
+type AppliedStateStore interface {
+	Load() (map[string]*model.AppliedState, error)
+	Save(states map[string]*model.AppliedState) error
+}
+
+//BuildFileAppliedStateStore builds store keeping applied states in a local json file
+func BuildFileAppliedStateStore(file string) AppliedStateStore {
+	return &fileAppliedStateStore{file: file}
+}
+
+//BuildConfigMapAppliedStateStore builds store keeping applied states in a config map of the cluster of the kubernetes processor
+func BuildConfigMapAppliedStateStore(k8sProcessor K8sProcessor, namespace, configMapName string) AppliedStateStore {
+	return &configMapAppliedStateStore{
+		k8sProcessor:  k8sProcessor,
+		namespace:     namespace,
+		configMapName: configMapName,
+	}
+}
+
+type fileAppliedStateStore struct {
+	file string
+}
+
+func (store *fileAppliedStateStore) Load() (map[string]*model.AppliedState, error) {
+	states := make(map[string]*model.AppliedState)
+	content, err := ioutil.ReadFile(store.file)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, &states)
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (store *fileAppliedStateStore) Save(states map[string]*model.AppliedState) error {
+	content, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(store.file, content, 0600)
+}
+
+type configMapAppliedStateStore struct {
+	k8sProcessor  K8sProcessor
+	namespace     string
+	configMapName string
+}
+
+func (store *configMapAppliedStateStore) Load() (map[string]*model.AppliedState, error) {
+	return store.k8sProcessor.GetAppliedStates(store.namespace, store.configMapName)
+}
+
+func (store *configMapAppliedStateStore) Save(states map[string]*model.AppliedState) error {
+	return store.k8sProcessor.SaveAppliedStates(store.namespace, store.configMapName, states)
+}
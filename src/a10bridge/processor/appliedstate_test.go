@@ -0,0 +1,114 @@
+package processor_test
+
+import (
+	"a10bridge/mocks"
+	"a10bridge/model"
+	"a10bridge/processor"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AppliedStateTestSuite struct {
+	suite.Suite
+	directory string
+}
+
+func TestAppliedState(t *testing.T) {
+	suite.Run(t, new(AppliedStateTestSuite))
+}
+
+func (suite *AppliedStateTestSuite) SetupTest() {
+	directory, err := ioutil.TempDir("", "a10bridge")
+	suite.Require().Nil(err)
+	suite.directory = directory
+}
+
+func (suite *AppliedStateTestSuite) TearDownTest() {
+	os.RemoveAll(suite.directory)
+}
+
+func (suite *AppliedStateTestSuite) TestFileStore_missingFile() {
+	store := processor.BuildFileAppliedStateStore(filepath.Join(suite.directory, "applied.json"))
+
+	states, err := store.Load()
+	suite.Assert().Nil(err)
+	suite.Assert().Empty(states)
+}
+
+func (suite *AppliedStateTestSuite) TestFileStore_saveAndLoad() {
+	store := processor.BuildFileAppliedStateStore(filepath.Join(suite.directory, "applied.json"))
+	applied := model.NewAppliedState()
+	applied.Servers["server"] = &model.Node{A10Server: "server", IPAddress: "10.10.10.10", Weight: "1"}
+	applied.ServiceGroups["service-group"] = &model.ServiceGroup{
+		Name:    "service-group",
+		Members: []*model.Member{&model.Member{ServerName: "server", Port: 8080}},
+	}
+
+	err := store.Save(map[string]*model.AppliedState{"lb01": applied})
+	suite.Assert().Nil(err)
+	states, err := store.Load()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]*model.AppliedState{"lb01": applied}, states)
+}
+
+func (suite *AppliedStateTestSuite) TestFileStore_corruptedFile() {
+	file := filepath.Join(suite.directory, "applied.json")
+	suite.Require().Nil(ioutil.WriteFile(file, []byte(`{"lb01": `), 0600))
+	store := processor.BuildFileAppliedStateStore(file)
+
+	_, err := store.Load()
+	suite.Assert().NotNil(err)
+}
+
+func (suite *AppliedStateTestSuite) TestConfigMapStore() {
+	k8sProcessor := new(mocks.K8sProcessor)
+	store := processor.BuildConfigMapAppliedStateStore(k8sProcessor, "ingress", "a10bridge-applied-state")
+	states := map[string]*model.AppliedState{"lb01": model.NewAppliedState()}
+
+	k8sProcessor.On("GetAppliedStates", "ingress", "a10bridge-applied-state").Once().Return(states, nil)
+	k8sProcessor.On("SaveAppliedStates", "ingress", "a10bridge-applied-state", states).Once().Return(nil)
+	loaded, err := store.Load()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(states, loaded)
+	suite.Assert().Nil(store.Save(loaded))
+	k8sProcessor.AssertExpectations(suite.T())
+}
+
+func (suite *AppliedStateTestSuite) TestTracker_notStarted() {
+	tracker := processor.NewAppliedStateTracker()
+
+	suite.Assert().Nil(tracker.State())
+}
+
+func (suite *AppliedStateTestSuite) TestTracker_keepsPreviousState() {
+	previous := model.NewAppliedState()
+	previous.Servers["server"] = &model.Node{A10Server: "server"}
+	tracker := processor.NewAppliedStateTracker()
+	tracker.Start(previous, false)
+
+	suite.Assert().Equal(previous, tracker.State())
+	suite.Assert().False(previous == tracker.State())
+}
+
+func (suite *AppliedStateTestSuite) TestTracker_haltedRollout() {
+	previous := model.NewAppliedState()
+	previous.HaltedRollout = "halted"
+	tracker := processor.NewAppliedStateTracker()
+	tracker.Start(previous, false)
+
+	suite.Assert().Equal("halted", tracker.HaltedRollout())
+	tracker.RolloutHalted("")
+	suite.Assert().Equal("", tracker.State().HaltedRollout)
+	suite.Assert().Equal("halted", previous.HaltedRollout)
+}
+
+func (suite *AppliedStateTestSuite) TestTracker_haltedRolloutNotStarted() {
+	tracker := processor.NewAppliedStateTracker()
+	tracker.RolloutHalted("halted")
+
+	suite.Assert().Equal("", tracker.HaltedRollout())
+}
@@ -0,0 +1,147 @@
+package processor
+
+import (
+	"a10bridge/model"
+	"sort"
+)
+
+//CanaryRollout keeps servers, health monitors and service groups of the canary a10 instance as they were before the run changed them,
+//so the changes can be verified and reverted. It doesn't keep anything until started
+type CanaryRollout struct {
+	servers       map[string]*model.Node
+	healthChecks  map[string]*model.HealthCheck
+	serviceGroups map[string]*model.ServiceGroup
+}
+
+//NewCanaryRollout builds rollout which doesn't keep anything until started
+func NewCanaryRollout() *CanaryRollout {
+	return &CanaryRollout{}
+}
+
+//Start keeps objects changed from now on
+func (canary *CanaryRollout) Start() {
+	if canary == nil {
+		return
+	}
+	canary.servers = make(map[string]*model.Node)
+	canary.healthChecks = make(map[string]*model.HealthCheck)
+	canary.serviceGroups = make(map[string]*model.ServiceGroup)
+}
+
+func (canary *CanaryRollout) started() bool {
+	return canary != nil && canary.serviceGroups != nil
+}
+
+//Changed checks any object was changed
+func (canary *CanaryRollout) Changed() bool {
+	return canary.started() && len(canary.servers)+len(canary.healthChecks)+len(canary.serviceGroups) > 0
+}
+
+//Servers names of changed servers
+func (canary *CanaryRollout) Servers() []string {
+	if !canary.started() {
+		return nil
+	}
+	names := make([]string, 0, len(canary.servers))
+	for name := range canary.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//HealthChecks names of changed health monitors
+func (canary *CanaryRollout) HealthChecks() []string {
+	if !canary.started() {
+		return nil
+	}
+	names := make([]string, 0, len(canary.healthChecks))
+	for name := range canary.healthChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//ServiceGroups names of changed service groups
+func (canary *CanaryRollout) ServiceGroups() []string {
+	if !canary.started() {
+		return nil
+	}
+	names := make([]string, 0, len(canary.serviceGroups))
+	for name := range canary.serviceGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//PreviousServer the server as it was in a10 before it was changed, nil when it didn't exist
+func (canary *CanaryRollout) PreviousServer(serverName string) *model.Node {
+	if !canary.started() {
+		return nil
+	}
+	return canary.servers[serverName]
+}
+
+//PreviousHealthCheck the health monitor as it was in a10 before it was changed, nil when it didn't exist
+func (canary *CanaryRollout) PreviousHealthCheck(healthCheckName string) *model.HealthCheck {
+	if !canary.started() {
+		return nil
+	}
+	return canary.healthChecks[healthCheckName]
+}
+
+//PreviousServiceGroup the service group as it was in a10 before it was changed, nil when it didn't exist
+func (canary *CanaryRollout) PreviousServiceGroup(serviceGroupName string) *model.ServiceGroup {
+	if !canary.started() {
+		return nil
+	}
+	return canary.serviceGroups[serviceGroupName]
+}
+
+//ServerChanged keeps the a10 server as it was before its first change, nil when it is being created
+func (canary *CanaryRollout) ServerChanged(serverName string, previous *model.Node) {
+	if !canary.started() {
+		return
+	}
+	if _, exists := canary.servers[serverName]; exists {
+		return
+	}
+	if previous != nil {
+		copied := *previous
+		previous = &copied
+	}
+	canary.servers[serverName] = previous
+}
+
+//HealthCheckChanged keeps the a10 health monitor as it was before its first change, nil when it is being created
+func (canary *CanaryRollout) HealthCheckChanged(healthCheckName string, previous *model.HealthCheck) {
+	if !canary.started() {
+		return
+	}
+	if _, exists := canary.healthChecks[healthCheckName]; exists {
+		return
+	}
+	if previous != nil {
+		copied := *previous
+		previous = &copied
+	}
+	canary.healthChecks[healthCheckName] = previous
+}
+
+//ServiceGroupChanged keeps the a10 service group as it was before its first change, nil when it is being created
+func (canary *CanaryRollout) ServiceGroupChanged(serviceGroupName string, previous *model.ServiceGroup) {
+	if !canary.started() {
+		return
+	}
+	if _, exists := canary.serviceGroups[serviceGroupName]; exists {
+		return
+	}
+	if previous != nil {
+		copied := *previous
+		copied.Members = append([]*model.Member{}, previous.Members...)
+		previous = &copied
+	}
+	canary.serviceGroups[serviceGroupName] = previous
+}
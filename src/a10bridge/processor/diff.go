@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"a10bridge/model"
+	"strconv"
+)
+
+//fieldDiff collects field changes needed to bring an a10 object from its current to its desired state
+type fieldDiff struct {
+	changes model.FieldChanges
+}
+
+func (diff *fieldDiff) add(field, current, desired string) {
+	diff.changes = append(diff.changes, model.FieldChange{Field: field, Old: current, New: desired})
+}
+
+func (diff *fieldDiff) compareString(field, current, desired string) {
+	if current != desired {
+		diff.add(field, current, desired)
+	}
+}
+
+//compareFields compares fields managed for the desired object, the same fields update requests send
+func (diff *fieldDiff) compareFields(fields []model.ManagedField, current, desired model.FieldValuer) {
+	managed := model.Managed(fields, desired)
+	for _, field := range fields {
+		if !managed[field.Name] {
+			continue
+		}
+		currentValue, _ := current.FieldValue(field.Name)
+		desiredValue, _ := desired.FieldValue(field.Name)
+		diff.compareString(field.Name, currentValue, desiredValue)
+	}
+}
+
+//without drops changes of the field
+func (diff *fieldDiff) without(field string) {
+	changes := make(model.FieldChanges, 0, len(diff.changes))
+	for _, change := range diff.changes {
+		if change.Field != field {
+			changes = append(changes, change)
+		}
+	}
+	diff.changes = changes
+}
+
+//diffServer changes of the a10 server needed to match the node, attributes the node doesn't manage are not compared
+func diffServer(server *model.Node, node *model.Node) model.FieldChanges {
+	diff := &fieldDiff{}
+	diff.compareFields(model.ServerFields, server, node)
+	if sameWeight(node, server) {
+		diff.without("weight")
+	}
+	return diff.changes
+}
+
+//sameWeight compares weights of the server and the node, computed weights are considered the same while they differ by less than the threshold
+func sameWeight(node *model.Node, server *model.Node) bool {
+	if node.Weight == server.Weight {
+		return true
+	}
+	if node.WeightThreshold <= 0 {
+		return false
+	}
+	nodeWeight, err := strconv.Atoi(node.Weight)
+	if err != nil {
+		return false
+	}
+	serverWeight, err := strconv.Atoi(server.Weight)
+	if err != nil {
+		return false
+	}
+	difference := nodeWeight - serverWeight
+	if difference < 0 {
+		difference = -difference
+	}
+	return difference < node.WeightThreshold
+}
+
+//diffHealthCheck changes of the a10 health monitor needed to match the health check
+func diffHealthCheck(healthMonitor *model.HealthCheck, healthCheck *model.HealthCheck) model.FieldChanges {
+	diff := &fieldDiff{}
+	diff.compareFields(model.HealthCheckFields, healthMonitor, healthCheck)
+	return diff.changes
+}
+
+//diffServiceGroup changes of settings of the a10 service group needed to match the service group, members are compared separately
+func diffServiceGroup(a10ServiceGroup *model.ServiceGroup, serviceGroup *model.ServiceGroup) model.FieldChanges {
+	diff := &fieldDiff{}
+	diff.compareFields(model.ServiceGroupFields, a10ServiceGroup, serviceGroup)
+	return diff.changes
+}
+
+//withRemovedSettingsReset service group marked to reset managed settings it no longer sets but which were applied before
+func withRemovedSettingsReset(serviceGroup *model.ServiceGroup, lastApplied *model.ServiceGroup) *model.ServiceGroup {
+	if serviceGroup.MinActiveMembers > 0 || lastApplied == nil || lastApplied.MinActiveMembers == 0 {
+		return serviceGroup
+	}
+	reset := *serviceGroup
+	reset.ResetMinActiveMembers = true
+	return &reset
+}
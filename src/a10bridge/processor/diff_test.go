@@ -0,0 +1,76 @@
+package processor_test
+
+import (
+	"a10bridge/model"
+	"a10bridge/processor"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DiffTestSuite struct {
+	suite.Suite
+	helper *processor.TestHelper
+}
+
+func TestDiff(t *testing.T) {
+	tests := new(DiffTestSuite)
+	tests.helper = new(processor.TestHelper)
+	suite.Run(t, tests)
+}
+
+func (suite *DiffTestSuite) TestDiffServer() {
+	server := &model.Node{A10Server: "server", IPAddress: "10.10.10.10", Weight: "1", ConnLimit: 100, Template: "tpl"}
+	node := &model.Node{A10Server: "server", IPAddress: "10.10.10.11", Weight: "5", ConnLimit: 200}
+
+	changes := suite.helper.DiffServer(server, node)
+	suite.Assert().Equal(model.FieldChanges{
+		model.FieldChange{Field: "ip", Old: "10.10.10.10", New: "10.10.10.11"},
+		model.FieldChange{Field: "weight", Old: "1", New: "5"},
+		model.FieldChange{Field: "connLimit", Old: "100", New: "200"},
+	}, changes)
+	suite.Assert().Equal("ip '10.10.10.10' -> '10.10.10.11', weight '1' -> '5', connLimit '100' -> '200'", changes.String())
+	suite.Assert().Equal([]string{"ip", "weight", "connLimit"}, changes.Fields())
+}
+
+func (suite *DiffTestSuite) TestDiffServer_inSync() {
+	server := &model.Node{A10Server: "server", IPAddress: "10.10.10.10", Weight: "10", Template: "tpl"}
+	node := &model.Node{A10Server: "server", IPAddress: "10.10.10.10", Weight: "12", WeightThreshold: 5}
+
+	suite.Assert().Empty(suite.helper.DiffServer(server, node))
+}
+
+func (suite *DiffTestSuite) TestDiffHealthCheck() {
+	healthMonitor := &model.HealthCheck{Name: "health", Endpoint: "/health", Port: 80, Interval: 5}
+	healthCheck := &model.HealthCheck{Name: "health", Endpoint: "/healthz", Port: 80, Interval: 10}
+
+	suite.Assert().Equal(model.FieldChanges{
+		model.FieldChange{Field: "endpoint", Old: "/health", New: "/healthz"},
+		model.FieldChange{Field: "interval", Old: "5", New: "10"},
+	}, suite.helper.DiffHealthCheck(healthMonitor, healthCheck))
+}
+
+func (suite *DiffTestSuite) TestDiffServiceGroup() {
+	a10ServiceGroup := &model.ServiceGroup{Name: "group", Health: &model.HealthCheck{Name: "old"}, Method: model.LBMethodRoundRobin, MinActiveMembers: 2}
+	serviceGroup := &model.ServiceGroup{Name: "group", Health: &model.HealthCheck{Name: "new"}, Protocol: model.ProtocolUDP}
+
+	suite.Assert().Equal(model.FieldChanges{
+		model.FieldChange{Field: "healthMonitor", Old: "old", New: "new"},
+		model.FieldChange{Field: "protocol", Old: "tcp", New: "udp"},
+	}, suite.helper.DiffServiceGroup(a10ServiceGroup, serviceGroup))
+}
+
+func (suite *DiffTestSuite) TestDiffServiceGroup_resetMinActiveMembers() {
+	a10ServiceGroup := &model.ServiceGroup{Name: "group", MinActiveMembers: 2}
+	serviceGroup := &model.ServiceGroup{Name: "group", ResetMinActiveMembers: true}
+
+	suite.Assert().Equal(model.FieldChanges{
+		model.FieldChange{Field: "minActiveMembers", Old: "2", New: "0"},
+	}, suite.helper.DiffServiceGroup(a10ServiceGroup, serviceGroup))
+}
+
+func (suite *DiffTestSuite) TestReversed() {
+	changes := model.FieldChanges{model.FieldChange{Field: "weight", Old: "1", New: "5"}}
+
+	suite.Assert().Equal(model.FieldChanges{model.FieldChange{Field: "weight", Old: "5", New: "1"}}, changes.Reversed())
+}
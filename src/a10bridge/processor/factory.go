@@ -8,6 +8,7 @@ import (
 )
 
 var apiserverCreateClient = apiserver.CreateClient
+var apiserverCreateClusterClient = apiserver.CreateClusterClient
 var a10BuildClient = a10.BuildClient
 
 //A10Processors a10 processors holder
@@ -15,6 +16,11 @@ type A10Processors struct {
 	Node         NodeProcessor
 	ServiceGroup ServiceGroupProcessor
 	HealthCheck  HealthCheckProcessor
+	LoadBalancer LoadBalancerProcessor
+	Recorder     *SyncRecorder
+	Applied      *AppliedStateTracker
+	Removals     *RemovalGuard
+	Canary       *CanaryRollout
 	client       api.Client
 }
 
@@ -25,8 +31,19 @@ func (processors A10Processors) Destroy() {
 }
 
 //BuildK8sProcessor builds kubernetes processor
-func BuildK8sProcessor() (K8sProcessor, error) {
-	client, err := apiserverCreateClient()
+func BuildK8sProcessor(clientConfig apiserver.ClientConfig) (K8sProcessor, error) {
+	client, err := apiserverCreateClient(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &k8sProcessorImpl{
+		k8sClient: client,
+	}, nil
+}
+
+//BuildClusterK8sProcessor builds kubernetes processor for the configured cluster
+func BuildClusterK8sProcessor(cluster config.ClusterConfig) (K8sProcessor, error) {
+	client, err := apiserverCreateClusterClient(cluster.Kubeconfig, cluster.Context)
 	if err != nil {
 		return nil, err
 	}
@@ -41,20 +58,45 @@ func BuildA10Processors(a10instance *config.A10Instance) (*A10Processors, error)
 	if err != nil {
 		return nil, err
 	}
+	recorder := NewSyncRecorder(a10instance.Name)
+	applied := NewAppliedStateTracker()
+	removals := NewRemovalGuard()
+	canary := NewCanaryRollout()
 
 	return &A10Processors{
 		Node: &nodeProcessorImpl{
-			a10Client: a10Client,
+			a10Client:     a10Client,
+			addressFamily: a10instance.AddressFamily,
+			apiVersion:    a10instance.APIVersion,
+			recorder:      recorder,
+			applied:       applied,
+			canary:        canary,
 		},
 
 		ServiceGroup: &serviceGroupProcessorImpl{
 			a10Client: a10Client,
+			homeZone:  a10instance.Zone,
+			recorder:  recorder,
+			applied:   applied,
+			removals:  removals,
+			canary:    canary,
 		},
 
 		HealthCheck: &healthCheckProcessorImpl{
 			a10Client: a10Client,
+			recorder:  recorder,
+			applied:   applied,
+			canary:    canary,
+		},
+
+		LoadBalancer: &loadBalancerProcessorImpl{
+			a10Client: a10Client,
 		},
 
-		client: a10Client,
+		Recorder: recorder,
+		Applied:  applied,
+		Removals: removals,
+		Canary:   canary,
+		client:   a10Client,
 	}, err
 }
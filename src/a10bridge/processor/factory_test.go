@@ -25,23 +25,48 @@ func TestFactory(t *testing.T) {
 
 func (suite *FactoryTestSuite) TestBuildK8sProcessor() {
 	k8sClient := new(mocks.K8sClient)
-	original := suite.helper.SetApiserverCreateClient(func() (apiserver.K8sClient, error) {
+	original := suite.helper.SetApiserverCreateClient(func(clientConfig apiserver.ClientConfig) (apiserver.K8sClient, error) {
 		return k8sClient, nil
 	})
 	defer suite.helper.SetApiserverCreateClient(original)
 
-	kprocessor, err := processor.BuildK8sProcessor()
+	kprocessor, err := processor.BuildK8sProcessor(apiserver.ClientConfig{})
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(kprocessor)
 }
 
 func (suite *FactoryTestSuite) TestBuildK8sProcessor_createClientFailure() {
-	original := suite.helper.SetApiserverCreateClient(func() (apiserver.K8sClient, error) {
+	original := suite.helper.SetApiserverCreateClient(func(clientConfig apiserver.ClientConfig) (apiserver.K8sClient, error) {
 		return nil, errors.New("test")
 	})
 	defer suite.helper.SetApiserverCreateClient(original)
 
-	kprocessor, err := processor.BuildK8sProcessor()
+	kprocessor, err := processor.BuildK8sProcessor(apiserver.ClientConfig{})
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(kprocessor)
+}
+
+func (suite *FactoryTestSuite) TestBuildClusterK8sProcessor() {
+	k8sClient := new(mocks.K8sClient)
+	original := suite.helper.SetApiserverCreateClusterClient(func(kubeconfigPath, context string) (apiserver.K8sClient, error) {
+		suite.Assert().Equal("/etc/kubeconfig", kubeconfigPath)
+		suite.Assert().Equal("lga-k8s2", context)
+		return k8sClient, nil
+	})
+	defer suite.helper.SetApiserverCreateClusterClient(original)
+
+	kprocessor, err := processor.BuildClusterK8sProcessor(config.ClusterConfig{Kubeconfig: "/etc/kubeconfig", Context: "lga-k8s2"})
+	suite.Assert().Nil(err)
+	suite.Assert().NotNil(kprocessor)
+}
+
+func (suite *FactoryTestSuite) TestBuildClusterK8sProcessor_createClientFailure() {
+	original := suite.helper.SetApiserverCreateClusterClient(func(kubeconfigPath, context string) (apiserver.K8sClient, error) {
+		return nil, errors.New("test")
+	})
+	defer suite.helper.SetApiserverCreateClusterClient(original)
+
+	kprocessor, err := processor.BuildClusterK8sProcessor(config.ClusterConfig{Context: "lga-k8s2"})
 	suite.Assert().NotNil(err)
 	suite.Assert().Nil(kprocessor)
 }
@@ -0,0 +1,200 @@
+package processor
+
+import (
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//fileSourceImpl reads servers and service groups from a yaml or json file, each member becomes an ingress controller named after the source
+type fileSourceImpl struct {
+	name string
+	file string
+}
+
+type staticState struct {
+	Servers       []staticServer       `yaml:"servers"`
+	ServiceGroups []staticServiceGroup `yaml:"serviceGroups"`
+}
+
+type staticServer struct {
+	Name        string            `yaml:"name"`
+	IP          string            `yaml:"ip"`
+	IPv6        string            `yaml:"ipv6"`
+	Weight      int               `yaml:"weight"`
+	Description string            `yaml:"description"`
+	Labels      map[string]string `yaml:"labels"`
+}
+
+type staticServiceGroup struct {
+	Name             string             `yaml:"name"`
+	A10Instances     []string           `yaml:"a10Instances"`
+	Method           string             `yaml:"method"`
+	Protocol         string             `yaml:"protocol"`
+	MinActiveMembers int                `yaml:"minActiveMembers"`
+	Health           *staticHealthCheck `yaml:"health"`
+	Members          []staticMember     `yaml:"members"`
+}
+
+type staticHealthCheck struct {
+	Endpoint                  string `yaml:"endpoint"`
+	Port                      int    `yaml:"port"`
+	ExpectCode                string `yaml:"expectCode"`
+	RetryCount                int    `yaml:"retryCount"`
+	RequiredConsecutivePasses int    `yaml:"requiredConsecutivePasses"`
+	Interval                  int    `yaml:"interval"`
+	Timeout                   int    `yaml:"timeout"`
+}
+
+type staticMember struct {
+	Server   string `yaml:"server"`
+	Port     int    `yaml:"port"`
+	Priority int    `yaml:"priority"`
+}
+
+func (source *fileSourceImpl) Name() string {
+	return source.name
+}
+
+//Load reads the file on every call so that changes are picked up without a restart
+func (source *fileSourceImpl) Load() (*model.SourceState, error) {
+	content, err := ioutil.ReadFile(source.file)
+	if err != nil {
+		return nil, err
+	}
+	var static staticState
+	err = yaml.Unmarshal(content, &static)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s. %s", source.file, err)
+	}
+
+	state := &model.SourceState{
+		Nodes:         make([]*model.Node, 0),
+		ServiceGroups: make([]*model.ServiceGroup, 0),
+	}
+	nodes := make(map[string]*model.Node)
+	for _, server := range static.Servers {
+		node, err := source.buildNode(server)
+		if err != nil {
+			return nil, err
+		}
+		existing, exists := nodes[node.A10Server]
+		if exists {
+			if existing.IPAddress != node.IPAddress || existing.IPv6Address != node.IPv6Address {
+				return nil, fmt.Errorf("server %s is declared with ip '%s' and with ip '%s'", node.A10Server, nodeAddress(existing), nodeAddress(node))
+			}
+			continue
+		}
+		nodes[node.A10Server] = node
+		state.Nodes = append(state.Nodes, node)
+	}
+
+	names := make([]string, 0)
+	for _, declared := range static.ServiceGroups {
+		if util.Contains(names, declared.Name) {
+			return nil, fmt.Errorf("service group %s is declared more than once", declared.Name)
+		}
+		names = append(names, declared.Name)
+		serviceGroup, err := source.buildServiceGroup(declared, nodes)
+		if err != nil {
+			return nil, fmt.Errorf("service group %s is invalid. %s", declared.Name, err)
+		}
+		state.ServiceGroups = append(state.ServiceGroups, serviceGroup)
+	}
+	return state, nil
+}
+
+func (source *fileSourceImpl) buildNode(server staticServer) (*model.Node, error) {
+	err := util.ValidateA10Name(server.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(server.IP) == 0 && len(server.IPv6) == 0 {
+		return nil, fmt.Errorf("server %s needs ip, ipv6 or both", server.Name)
+	}
+	node := &model.Node{
+		Name:        server.Name,
+		Cluster:     source.name,
+		A10Server:   server.Name,
+		IPAddress:   server.IP,
+		IPv6Address: server.IPv6,
+		Description: server.Description,
+		Labels:      server.Labels,
+	}
+	if server.Weight != 0 {
+		err = util.ValidateA10Weight(server.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("server %s is invalid. %s", server.Name, err)
+		}
+		node.Weight = strconv.Itoa(server.Weight)
+	}
+	return node, nil
+}
+
+func (source *fileSourceImpl) buildServiceGroup(static staticServiceGroup, nodes map[string]*model.Node) (*model.ServiceGroup, error) {
+	err := util.ValidateA10Name(static.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(static.Method) > 0 && !util.Contains(model.LBMethods, static.Method) {
+		return nil, fmt.Errorf("method '%s' is not supported, use one of %s", static.Method, strings.Join(model.LBMethods, ", "))
+	}
+	if len(static.Protocol) > 0 && !util.Contains(model.Protocols, static.Protocol) {
+		return nil, fmt.Errorf("protocol '%s' is not supported, use one of %s", static.Protocol, strings.Join(model.Protocols, ", "))
+	}
+
+	serviceGroup := &model.ServiceGroup{
+		Name:               static.Name,
+		Method:             static.Method,
+		Protocol:           static.Protocol,
+		MinActiveMembers:   static.MinActiveMembers,
+		A10Instances:       static.A10Instances,
+		IngressControllers: make([]*model.IngressController, 0),
+	}
+	if static.Health != nil {
+		if static.Health.Port < 0 || static.Health.Port > 65535 {
+			return nil, fmt.Errorf("health port %d is out of range", static.Health.Port)
+		}
+		serviceGroup.Health = &model.HealthCheck{
+			Name:                      static.Name,
+			Endpoint:                  static.Health.Endpoint,
+			Port:                      static.Health.Port,
+			ExpectCode:                static.Health.ExpectCode,
+			RetryCount:                static.Health.RetryCount,
+			RequiredConsecutivePasses: static.Health.RequiredConsecutivePasses,
+			Interval:                  static.Health.Interval,
+			Timeout:                   static.Health.Timeout,
+		}
+	}
+
+	for _, member := range static.Members {
+		node, exists := nodes[member.Server]
+		if !exists {
+			return nil, fmt.Errorf("member server %s is not declared", member.Server)
+		}
+		if member.Port < 1 || member.Port > 65535 {
+			return nil, fmt.Errorf("member %s has port %d out of range", member.Server, member.Port)
+		}
+		serviceGroup.IngressControllers = append(serviceGroup.IngressControllers, &model.IngressController{
+			Name:           source.name,
+			Cluster:        source.name,
+			A10Instances:   static.A10Instances,
+			Nodes:          []*model.Node{node},
+			Port:           member.Port,
+			MemberPriority: member.Priority,
+		})
+	}
+	return serviceGroup, nil
+}
+
+func nodeAddress(node *model.Node) string {
+	if len(node.IPAddress) == 0 {
+		return node.IPv6Address
+	}
+	return node.IPAddress
+}
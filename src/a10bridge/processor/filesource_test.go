@@ -0,0 +1,84 @@
+package processor_test
+
+import (
+	"a10bridge/config"
+	"a10bridge/model"
+	"a10bridge/processor"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FileSourceTestSuite struct {
+	suite.Suite
+}
+
+func TestFileSource(t *testing.T) {
+	suite.Run(t, new(FileSourceTestSuite))
+}
+
+func (suite *FileSourceTestSuite) load(file string) (*model.SourceState, error) {
+	sources := processor.BuildStateSources([]config.SourceConfig{config.SourceConfig{Name: "metal", File: file}})
+	suite.Require().Len(sources, 1)
+	suite.Assert().Equal("metal", sources[0].Name())
+	return sources[0].Load()
+}
+
+func (suite *FileSourceTestSuite) TestLoad() {
+	state, err := suite.load("testdata/static1.yaml")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(state.Nodes, 2)
+	metal1 := state.Nodes[0]
+	suite.Assert().Equal(&model.Node{
+		Name:      "metal1",
+		Cluster:   "metal",
+		A10Server: "metal1",
+		IPAddress: "10.20.0.1",
+		Weight:    "20",
+		Labels:    map[string]string{model.LabelZone: "lga1"},
+	}, metal1)
+	suite.Assert().Equal("fd00::2", state.Nodes[1].IPv6Address)
+
+	suite.Require().Len(state.ServiceGroups, 1)
+	serviceGroup := state.ServiceGroups[0]
+	suite.Assert().Equal("svcGroup", serviceGroup.Name)
+	suite.Assert().Equal([]string{"lb"}, serviceGroup.A10Instances)
+	suite.Assert().Equal(model.LBMethodWeightedRoundRobin, serviceGroup.Method)
+	suite.Assert().Equal(&model.HealthCheck{Name: "svcGroup", Endpoint: "/healthz", Port: 8081, ExpectCode: "200"}, serviceGroup.Health)
+	suite.Require().Len(serviceGroup.IngressControllers, 2)
+	controller := serviceGroup.IngressControllers[0]
+	suite.Assert().Equal("metal", controller.Name)
+	suite.Assert().Equal("metal", controller.Cluster)
+	suite.Assert().Equal(8080, controller.Port)
+	suite.Assert().Equal(10, controller.MemberPriority)
+	suite.Assert().Equal([]*model.Node{metal1}, controller.Nodes)
+}
+
+func (suite *FileSourceTestSuite) TestLoad_json() {
+	state, err := suite.load("testdata/static2.json")
+
+	suite.Require().Nil(err)
+	suite.Assert().Len(state.Nodes, 1)
+	suite.Require().Len(state.ServiceGroups, 1)
+	suite.Assert().Nil(state.ServiceGroups[0].Health)
+	suite.Assert().Len(state.ServiceGroups[0].IngressControllers, 1)
+}
+
+func (suite *FileSourceTestSuite) TestLoad_conflictingServer() {
+	_, err := suite.load("testdata/static3.yaml")
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *FileSourceTestSuite) TestLoad_undeclaredMemberServer() {
+	_, err := suite.load("testdata/static4.yaml")
+
+	suite.Assert().NotNil(err)
+}
+
+func (suite *FileSourceTestSuite) TestLoad_missingFile() {
+	_, err := suite.load("testdata/missing.yaml")
+
+	suite.Assert().NotNil(err)
+}
@@ -11,75 +11,88 @@ import (
 
 //HealthCheckProcessor processor responsible for processing ingresses
 type HealthCheckProcessor interface {
-	ProcessHealthCheck(healthCheck *model.HealthCheck) error
+	ProcessHealthCheck(healthCheck *model.HealthCheck, serviceGroup *model.ServiceGroup) error
+	RevertHealthCheck(healthCheckName string, previous *model.HealthCheck) error
 }
 
 type healthCheckProcessorImpl struct {
 	a10Client api.Client
+	recorder  *SyncRecorder
+	applied   *AppliedStateTracker
+	canary    *CanaryRollout
 }
 
-func (processor healthCheckProcessorImpl) ProcessHealthCheck(healthCheck *model.HealthCheck) error {
+//ProcessHealthCheck syncs the a10 health monitor of the service group, out of band changes are reported on the service group
+func (processor healthCheckProcessorImpl) ProcessHealthCheck(healthCheck *model.HealthCheck, serviceGroup *model.ServiceGroup) error {
 	glog.Infof("Processing healht check %s", util.ToJSON(healthCheck))
 
 	healthMonitor, a10err := processor.a10Client.GetHealthMonitor(healthCheck.Name)
 	if a10err != nil {
 		//health monitor not found
 		if processor.a10Client.IsHealthMonitorNotFound(a10err) {
+			err := util.ValidateA10Name(healthCheck.Name)
+			if err != nil {
+				return err
+			}
 			healthMonitor = healthCheck
 			a10err = processor.a10Client.CreateHealthMonitor(healthMonitor)
 			if a10err != nil {
 				return a10err
 			}
+			processor.canary.HealthCheckChanged(healthCheck.Name, nil)
+			processor.applied.healthCheckApplied(healthCheck)
 		} else {
 			return a10err
 		}
 	} else {
 		fmt.Println(util.ToJSON(healthMonitor))
 
-		if !sameHealthConfigs(healthCheck, healthMonitor) {
-			glog.Info("Health monitor configuration in a10 differs from healthcheck configuration in kubernetes, resetting monitor in a10")
+		changes := diffHealthCheck(healthMonitor, healthCheck)
+		if len(changes) > 0 {
+			lastApplied := processor.applied.healthCheck(healthCheck.Name)
+			if lastApplied != nil {
+				outOfBand := diffHealthCheck(healthMonitor, lastApplied).Reversed()
+				if len(outOfBand) > 0 {
+					keep := processor.applied.keeps(len(diffHealthCheck(lastApplied, healthCheck)) == 0)
+					message := describeOutOfBand(fmt.Sprintf("health monitor %s", healthCheck.Name), outOfBand, keep)
+					glog.Warning(message)
+					processor.recorder.recordServiceGroup(serviceGroup, model.ReasonChangedOutOfBand, message)
+					if keep {
+						return nil
+					}
+				}
+			}
+			glog.Infof("Health monitor configuration in a10 differs from healthcheck configuration in kubernetes, resetting monitor in a10: %s", changes)
 			a10err = processor.a10Client.UpdateHealthMonitor(healthCheck)
 			if a10err != nil {
 				return a10err
 			}
+			processor.canary.HealthCheckChanged(healthCheck.Name, healthMonitor)
 			glog.Info("Health monitor configuration synced with kubernetes configuration")
 		} else {
 			glog.Info("Health monitor configuration is in sync with kubernetes configuration")
 		}
+		processor.applied.healthCheckApplied(healthCheck)
 	}
 
 	return a10err
 }
 
-func sameHealthConfigs(healthCheck *model.HealthCheck, healthMonitor *model.HealthCheck) bool {
-	if healthCheck.Endpoint != healthMonitor.Endpoint {
-		glog.Infof("Endpoints '%s' and '%s' don't match", healthCheck.Endpoint, healthMonitor.Endpoint)
-		return false
-	}
-	if healthCheck.ExpectCode != healthMonitor.ExpectCode {
-		glog.Infof("Expected codes '%s' and '%s' don't match", healthCheck.ExpectCode, healthMonitor.ExpectCode)
-		return false
-	}
-	if healthCheck.Interval != healthMonitor.Interval {
-		glog.Infof("Intervals '%s' and '%s' don't match", healthCheck.Interval, healthMonitor.Interval)
-		return false
-	}
-	if healthCheck.Port != healthMonitor.Port {
-		glog.Infof("Ports '%d' and '%d' don't match", healthCheck.Port, healthMonitor.Port)
-		return false
-	}
-	if healthCheck.RequiredConsecutivePasses != healthMonitor.RequiredConsecutivePasses {
-		glog.Infof("Required consecutive passes '%d' and '%d' don't match", healthCheck.RequiredConsecutivePasses, healthMonitor.RequiredConsecutivePasses)
-		return false
-	}
-	if healthCheck.RetryCount != healthMonitor.RetryCount {
-		glog.Infof("Retry counts '%d' and '%d' don't match", healthCheck.RetryCount, healthMonitor.RetryCount)
-		return false
-	}
-	if healthCheck.Timeout != healthMonitor.Timeout {
-		glog.Infof("Timeouts '%d' and '%d' don't match", healthCheck.Timeout, healthMonitor.Timeout)
-		return false
+//RevertHealthCheck brings the health monitor back to the state it had in a10 before it was changed, health monitors which didn't exist are deleted
+func (processor healthCheckProcessorImpl) RevertHealthCheck(healthCheckName string, previous *model.HealthCheck) error {
+	if previous == nil {
+		glog.Infof("Reverting health monitor %s by deleting it", healthCheckName)
+		a10err := processor.a10Client.DeleteHealthMonitor(healthCheckName)
+		if a10err != nil {
+			return a10err
+		}
+	} else {
+		glog.Infof("Reverting health monitor %s", healthCheckName)
+		a10err := processor.a10Client.UpdateHealthMonitor(previous)
+		if a10err != nil {
+			return a10err
+		}
 	}
-
-	return true
+	processor.applied.healthCheckReverted(healthCheckName)
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"a10bridge/mocks"
 	"a10bridge/model"
 	"a10bridge/processor"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -31,7 +32,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_notChanged()
 	healthCheck := healthCheck()
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(healthCheck, nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -45,11 +46,26 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_notFound() {
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(nil, a10error)
 	client.On("IsHealthMonitorNotFound", a10error).Once().Return(true)
 	client.On("CreateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
 
+func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_invalidName() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildHealthcheckProcessor(client)
+	healthCheck := healthCheck()
+	healthCheck.Name = strings.Repeat("x", 64)
+
+	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(nil, a10error)
+	client.On("IsHealthMonitorNotFound", a10error).Once().Return(true)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "CreateHealthMonitor", healthCheck)
+}
+
 func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_getFails() {
 	a10error := new(mocks.A10Error)
 	client := suite.client
@@ -58,7 +74,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_getFails() {
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(nil, a10error)
 	client.On("IsHealthMonitorNotFound", a10error).Once().Return(false)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -72,7 +88,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_createFails()
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(nil, a10error)
 	client.On("IsHealthMonitorNotFound", a10error).Once().Return(true)
 	client.On("CreateHealthMonitor", healthCheck).Once().Return(a10error)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -87,7 +103,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_endpointChang
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -102,7 +118,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_expectedCodeC
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -117,7 +133,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_intervalChang
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -132,7 +148,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_portChanged()
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -147,7 +163,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_requiredConse
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -162,7 +178,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_retryCountCha
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -177,7 +193,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_timeoutChange
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -193,7 +209,7 @@ func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_updateFails()
 
 	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
 	client.On("UpdateHealthMonitor", healthCheck).Once().Return(a10error)
-	err := processor.ProcessHealthCheck(healthCheck)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -210,3 +226,123 @@ func healthCheck() *model.HealthCheck {
 		Timeout:                   10,
 	}
 }
+
+func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_changedOutOfBandIsKept() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.HealthChecks[healthCheck().Name] = healthCheck()
+	applied.Start(previous, true)
+	processor := suite.helper.BuildHealthcheckProcessorWithAppliedState(client, nil, applied)
+	healthCheck := healthCheck()
+	existing := *healthCheck
+	existing.Endpoint = "/manual"
+
+	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_changedOutOfBandIsRecorded() {
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.HealthChecks[healthCheck().Name] = healthCheck()
+	applied.Start(previous, true)
+	processor := suite.helper.BuildHealthcheckProcessorWithAppliedState(client, recorder, applied)
+	healthCheck := healthCheck()
+	existing := *healthCheck
+	existing.Endpoint = "/manual"
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].Namespace = "ingress"
+
+	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonChangedOutOfBand,
+			Message: "a10 lb01: health monitor " + healthCheck.Name + " was changed outside of a10bridge and is left alone: endpoint '" + healthCheck.Endpoint + "' -> '/manual'"},
+	}, recorder.Events())
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_changedOutOfBandIsReset() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.HealthChecks[healthCheck().Name] = healthCheck()
+	applied.Start(previous, false)
+	processor := suite.helper.BuildHealthcheckProcessorWithAppliedState(client, nil, applied)
+	healthCheck := healthCheck()
+	existing := *healthCheck
+	existing.Endpoint = "/manual"
+
+	client.On("GetHealthMonitor", healthCheck.Name).Once().Return(&existing, nil)
+	client.On("UpdateHealthMonitor", healthCheck).Once().Return(nil)
+	err := processor.ProcessHealthCheck(healthCheck, serviceGroup())
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(healthCheck, applied.State().HealthChecks[healthCheck.Name])
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *HealthCheckProcessorTestSuite) TestProcessHealthCheck_canaryKeepsChangedHealthMonitors() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	canary := processor.NewCanaryRollout()
+	canary.Start()
+	processor := suite.helper.BuildHealthcheckProcessorWithCanary(client, nil, canary)
+	created := healthCheck()
+	created.Name = "created"
+	changed := healthCheck()
+	existing := *changed
+	existing.Endpoint = "/old"
+
+	client.On("GetHealthMonitor", created.Name).Once().Return(nil, a10error)
+	client.On("IsHealthMonitorNotFound", a10error).Once().Return(true)
+	client.On("CreateHealthMonitor", created).Once().Return(nil)
+	client.On("GetHealthMonitor", changed.Name).Once().Return(&existing, nil)
+	client.On("UpdateHealthMonitor", changed).Once().Return(nil)
+	suite.Assert().Nil(processor.ProcessHealthCheck(created, serviceGroup()))
+	suite.Assert().Nil(processor.ProcessHealthCheck(changed, serviceGroup()))
+
+	suite.Assert().Equal([]string{"created", "test"}, canary.HealthChecks())
+	suite.Assert().Nil(canary.PreviousHealthCheck("created"))
+	suite.Assert().Equal(&existing, canary.PreviousHealthCheck("test"))
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *HealthCheckProcessorTestSuite) TestRevertHealthCheck() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(model.NewAppliedState(), false)
+	processor := suite.helper.BuildHealthcheckProcessorWithCanary(client, applied, nil)
+	previous := healthCheck()
+	previous.Endpoint = "/old"
+
+	client.On("UpdateHealthMonitor", previous).Once().Return(nil)
+	err := processor.RevertHealthCheck(previous.Name, previous)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *HealthCheckProcessorTestSuite) TestRevertHealthCheck_deletesCreatedHealthMonitor() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(model.NewAppliedState(), false)
+	processor := suite.helper.BuildHealthcheckProcessorWithCanary(client, applied, nil)
+	created := healthCheck()
+
+	client.On("GetHealthMonitor", created.Name).Once().Return(nil, a10error)
+	client.On("IsHealthMonitorNotFound", a10error).Once().Return(true)
+	client.On("CreateHealthMonitor", created).Once().Return(nil)
+	client.On("DeleteHealthMonitor", created.Name).Once().Return(nil)
+	suite.Assert().Nil(processor.ProcessHealthCheck(created, serviceGroup()))
+	suite.Assert().Contains(applied.State().HealthChecks, created.Name)
+	err := processor.RevertHealthCheck(created.Name, nil)
+	suite.Assert().Nil(err)
+	suite.Assert().NotContains(applied.State().HealthChecks, created.Name)
+	client.AssertExpectations(suite.T())
+}
@@ -4,12 +4,16 @@ import (
 	"a10bridge/a10/api"
 	"a10bridge/apiserver"
 	"a10bridge/config"
+	"a10bridge/model"
+	"time"
 )
 
 type TestHelper struct{}
-type ApiserverCreateClientFunc func() (apiserver.K8sClient, error)
+type ApiserverCreateClientFunc func(clientConfig apiserver.ClientConfig) (apiserver.K8sClient, error)
+type ApiserverCreateClusterClientFunc func(kubeconfigPath, context string) (apiserver.K8sClient, error)
 type A10BuildClientFunc func(a10Instance *config.A10Instance) (api.Client, api.A10Error)
 type UtilApplyTemplateFunc func(data interface{}, tpl string) (string, error)
+type TimeNowFunc func() time.Time
 
 func (helper TestHelper) SetApiserverCreateClient(createClientFunc ApiserverCreateClientFunc) ApiserverCreateClientFunc {
 	old := apiserverCreateClient
@@ -17,6 +21,12 @@ func (helper TestHelper) SetApiserverCreateClient(createClientFunc ApiserverCrea
 	return old
 }
 
+func (helper TestHelper) SetApiserverCreateClusterClient(createClusterClientFunc ApiserverCreateClusterClientFunc) ApiserverCreateClusterClientFunc {
+	old := apiserverCreateClusterClient
+	apiserverCreateClusterClient = createClusterClientFunc
+	return old
+}
+
 func (helper TestHelper) SetA10BuildClient(buildClientFunc A10BuildClientFunc) A10BuildClientFunc {
 	old := a10BuildClient
 	a10BuildClient = buildClientFunc
@@ -29,10 +39,56 @@ func (helper TestHelper) SetUtilApplyTemplate(utilApplyTemplateFunc UtilApplyTem
 	return old
 }
 
+func (helper TestHelper) SetTimeNow(timeNowFunc TimeNowFunc) TimeNowFunc {
+	old := timeNow
+	timeNow = timeNowFunc
+	return old
+}
+
 func (helper TestHelper) BuildNodeProcessor(client api.Client) NodeProcessor {
 	return nodeProcessorImpl{a10Client: client}
 }
 
+func (helper TestHelper) BuildNodeProcessorForAddressFamily(client api.Client, addressFamily string) NodeProcessor {
+	return nodeProcessorImpl{a10Client: client, addressFamily: addressFamily}
+}
+
+func (helper TestHelper) BuildNodeProcessorForAPIVersion(client api.Client, apiVersion int) NodeProcessor {
+	return nodeProcessorImpl{a10Client: client, apiVersion: apiVersion}
+}
+
+func (helper TestHelper) BuildNodeProcessorWithRecorder(client api.Client, recorder *SyncRecorder) NodeProcessor {
+	return nodeProcessorImpl{a10Client: client, recorder: recorder}
+}
+
+func (helper TestHelper) BuildNodeProcessorWithAppliedState(client api.Client, recorder *SyncRecorder, applied *AppliedStateTracker) NodeProcessor {
+	return nodeProcessorImpl{a10Client: client, recorder: recorder, applied: applied}
+}
+
+func (helper TestHelper) BuildHealthcheckProcessorWithAppliedState(client api.Client, recorder *SyncRecorder, applied *AppliedStateTracker) HealthCheckProcessor {
+	return healthCheckProcessorImpl{a10Client: client, recorder: recorder, applied: applied}
+}
+
+func (helper TestHelper) BuildServiceGroupProcessorWithAppliedState(client api.Client, recorder *SyncRecorder, applied *AppliedStateTracker) ServiceGroupProcessor {
+	return serviceGroupProcessorImpl{a10Client: client, recorder: recorder, applied: applied}
+}
+
+func (helper TestHelper) BuildServiceGroupProcessorWithRemovalGuard(client api.Client, recorder *SyncRecorder, removals *RemovalGuard) ServiceGroupProcessor {
+	return serviceGroupProcessorImpl{a10Client: client, recorder: recorder, removals: removals}
+}
+
+func (helper TestHelper) BuildServiceGroupProcessorWithCanary(client api.Client, applied *AppliedStateTracker, canary *CanaryRollout) ServiceGroupProcessor {
+	return serviceGroupProcessorImpl{a10Client: client, applied: applied, canary: canary}
+}
+
+func (helper TestHelper) BuildNodeProcessorWithCanary(client api.Client, applied *AppliedStateTracker, canary *CanaryRollout) NodeProcessor {
+	return nodeProcessorImpl{a10Client: client, applied: applied, canary: canary}
+}
+
+func (helper TestHelper) BuildHealthcheckProcessorWithCanary(client api.Client, applied *AppliedStateTracker, canary *CanaryRollout) HealthCheckProcessor {
+	return healthCheckProcessorImpl{a10Client: client, applied: applied, canary: canary}
+}
+
 func (helper TestHelper) BuildHealthcheckProcessor(client api.Client) HealthCheckProcessor {
 	return healthCheckProcessorImpl{a10Client: client}
 }
@@ -41,6 +97,30 @@ func (helper TestHelper) BuildServiceGroupProcessor(client api.Client) ServiceGr
 	return serviceGroupProcessorImpl{a10Client: client}
 }
 
+func (helper TestHelper) BuildServiceGroupProcessorForZone(client api.Client, homeZone string) ServiceGroupProcessor {
+	return serviceGroupProcessorImpl{a10Client: client, homeZone: homeZone}
+}
+
+func (helper TestHelper) BuildServiceGroupProcessorWithRecorder(client api.Client, recorder *SyncRecorder) ServiceGroupProcessor {
+	return serviceGroupProcessorImpl{a10Client: client, recorder: recorder}
+}
+
+func (helper TestHelper) DiffServer(server *model.Node, node *model.Node) model.FieldChanges {
+	return diffServer(server, node)
+}
+
+func (helper TestHelper) DiffHealthCheck(healthMonitor *model.HealthCheck, healthCheck *model.HealthCheck) model.FieldChanges {
+	return diffHealthCheck(healthMonitor, healthCheck)
+}
+
+func (helper TestHelper) DiffServiceGroup(a10ServiceGroup *model.ServiceGroup, serviceGroup *model.ServiceGroup) model.FieldChanges {
+	return diffServiceGroup(a10ServiceGroup, serviceGroup)
+}
+
+func (helper TestHelper) BuildLoadBalancerProcessor(client api.Client) LoadBalancerProcessor {
+	return loadBalancerProcessorImpl{a10Client: client}
+}
+
 func (helper TestHelper) BuildK8sProcessor(client apiserver.K8sClient) K8sProcessor {
 	return k8sProcessorImpl{k8sClient: client}
 }
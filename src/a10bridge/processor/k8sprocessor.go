@@ -2,50 +2,81 @@ package processor
 
 import (
 	"a10bridge/apiserver"
+	"a10bridge/config"
 	"a10bridge/model"
 	"a10bridge/util"
-	"errors"
-	"strings"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/golang/glog"
 )
 
 var utilApplyTemplate = util.ApplyTemplate
+var timeNow = time.Now
 
 type K8sProcessor interface {
-	BuildEnvironment() (*model.Environment, error)
+	BuildEnvironment(environmentConfig *config.EnvironmentConfig) (*model.Environment, error)
 	FindNodes(nodeSelectors map[string]string) ([]*model.Node, error)
 	FindIngressControllers() ([]*model.IngressController, error)
-	BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment) map[string]*model.ServiceGroup
+	CountControllerPods(controllers []*model.IngressController) (map[string]int, error)
+	BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment, instance string) map[string]*model.ServiceGroup
+	FindLoadBalancers() ([]*model.LoadBalancer, error)
+	AssignVIPs(loadBalancers []*model.LoadBalancer, vips []string)
+	PublishLoadBalancerStatus(loadBalancer *model.LoadBalancer) error
+	BuildLoadBalancerServiceGroups(loadBalancers []*model.LoadBalancer, nodes []*model.Node, namePrefix string) map[string]*model.ServiceGroup
+	FindServiceGroupResources() ([]*model.A10ServiceGroup, error)
+	BuildResourceServiceGroups(resources []*model.A10ServiceGroup, environment *model.Environment) map[string]*model.ServiceGroup
+	UpdateServiceGroupResourceStatus(resource *model.A10ServiceGroup, results []*model.SyncResult) error
+	PublishSyncEvents(events []*model.SyncEvent) error
+	GetTrafficShiftStates(namespace, configMapName string) (map[string]*model.TrafficShiftState, error)
+	SaveTrafficShiftStates(namespace, configMapName string, states map[string]*model.TrafficShiftState) error
+	GetAppliedStates(namespace, configMapName string) (map[string]*model.AppliedState, error)
+	SaveAppliedStates(namespace, configMapName string, states map[string]*model.AppliedState) error
 }
 
 type k8sProcessorImpl struct {
 	k8sClient apiserver.K8sClient
 }
 
-func (processor k8sProcessorImpl) BuildEnvironment() (*model.Environment, error) {
-	config, err := processor.k8sClient.GetConfigMap("ingress", "cluster-configs")
+func (processor k8sProcessorImpl) BuildEnvironment(environmentConfig *config.EnvironmentConfig) (*model.Environment, error) {
+	namePattern, err := environmentConfig.NamePattern()
 	if err != nil {
 		return nil, err
 	}
-	clusterName, exists := config.Data["name"]
+
+	configMap, err := processor.k8sClient.GetConfigMap(environmentConfig.Namespace, environmentConfig.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	if configMap == nil {
+		return nil, fmt.Errorf("Config map %s/%s not found", environmentConfig.Namespace, environmentConfig.ConfigMap)
+	}
+	clusterName, exists := configMap.Data[environmentConfig.NameKey]
 	if !exists || len(clusterName) == 0 {
-		return nil, errors.New("Cluster name not found in config map")
+		return nil, fmt.Errorf("Cluster name not found in config map under key %s", environmentConfig.NameKey)
 	}
 
-	parts := strings.Split(clusterName, "-")
-	dataCenter := parts[0]
-	clusterType := ""
+	match := namePattern.FindStringSubmatch(clusterName)
+	if match == nil {
+		return nil, fmt.Errorf("Cluster name %s doesn't match pattern %s", clusterName, environmentConfig.Pattern)
+	}
 
-	if len(parts) > 1 {
-		clusterType = parts[1]
+	environment := &model.Environment{
+		Cluster: clusterName,
+		Data:    configMap.Data,
+	}
+	for idx, group := range namePattern.SubexpNames() {
+		switch group {
+		case "dataCenter":
+			environment.DataCenter = match[idx]
+		case "type":
+			environment.Type = match[idx]
+		}
 	}
 
-	return &model.Environment{
-		Cluster:    clusterName,
-		DataCenter: dataCenter,
-		Type:       clusterType,
-	}, nil
+	return environment, nil
 }
 
 func (processor k8sProcessorImpl) FindNodes(nodeSelectors map[string]string) ([]*model.Node, error) {
@@ -81,16 +112,47 @@ func (processor k8sProcessorImpl) FindIngressControllers() ([]*model.IngressCont
 	return processor.k8sClient.GetIngressControllers()
 }
 
-func (processor k8sProcessorImpl) BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment) map[string]*model.ServiceGroup {
+//CountControllerPods counts ready pods of the ingress controllers by node name
+func (processor k8sProcessorImpl) CountControllerPods(controllers []*model.IngressController) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, controller := range controllers {
+		if len(controller.PodSelectors) == 0 {
+			glog.Warningf("Ingress controller %s doesn't have pod selectors, its pods are not counted", controller.Name)
+			continue
+		}
+		controllerCounts, err := processor.k8sClient.GetReadyPodCounts(controller.Namespace, controller.PodSelectors)
+		if err != nil {
+			return counts, err
+		}
+		for nodeName, count := range controllerCounts {
+			counts[nodeName] += count
+		}
+	}
+	return counts, nil
+}
+
+func (processor k8sProcessorImpl) BuildServiceGroups(controllers []*model.IngressController, environment *model.Environment, instance string) map[string]*model.ServiceGroup {
 	serviceGroups := make(map[string]*model.ServiceGroup)
 
+	portControllers := make([]*model.IngressController, 0)
 	for _, controller := range controllers {
-		serviceGroupName, err := utilApplyTemplate(environment, controller.ServiceGroupNameTemplate)
+		portControllers = append(portControllers, controller.PerPort()...)
+	}
+
+	zonedControllers := make([]*zonedController, 0)
+	for _, controller := range portControllers {
+		controllers, err := splitByZone(controller, environment, instance)
 		if err != nil {
 			glog.Errorf("Failed to build service group name for ingress controller %s. error: %s", controller.Name, err)
 			continue
 		}
-		glog.Infof("Ingress controller %s belongs to service group %s", controller.Name, serviceGroupName)
+		zonedControllers = append(zonedControllers, controllers...)
+	}
+
+	for _, zoned := range zonedControllers {
+		controller := zoned.controller
+		serviceGroupName := sanitizeServiceGroupName(zoned.serviceGroupName, "ingress controller "+controller.Name)
+		glog.Infof("Port %d of ingress controller %s belongs to service group %s", controller.Port, controller.Name, serviceGroupName)
 		serviceGroup, existed := serviceGroups[serviceGroupName]
 		if !existed {
 			healthCheck := *controller.Health
@@ -98,10 +160,14 @@ func (processor k8sProcessorImpl) BuildServiceGroups(controllers []*model.Ingres
 			serviceGroup := model.ServiceGroup{
 				Health:             &healthCheck,
 				Name:               serviceGroupName,
+				Method:             controller.Method,
+				Protocol:           controller.Protocol,
+				MinActiveMembers:   controller.MinActiveMembers,
 				IngressControllers: []*model.IngressController{controller},
 			}
 			serviceGroups[serviceGroupName] = &serviceGroup
 		} else {
+			mergeServiceGroupOptions(serviceGroup, controller)
 			serviceGroup.IngressControllers = append(serviceGroup.IngressControllers, controller)
 		}
 	}
@@ -116,3 +182,404 @@ func (processor k8sProcessorImpl) BuildServiceGroups(controllers []*model.Ingres
 
 	return serviceGroups
 }
+
+//zonedController ingress controller with the service group name rendered for zone of its nodes
+type zonedController struct {
+	serviceGroupName string
+	controller       *model.IngressController
+}
+
+//splitByZone renders the service group name for each zone the nodes of the ingress controller are in,
+//when the name depends on the zone the controller is split into one controller per service group holding only nodes of its zones
+func splitByZone(controller *model.IngressController, environment *model.Environment, instance string) ([]*zonedController, error) {
+	zones := make([]string, 0)
+	nodesByZone := make(map[string][]*model.Node)
+	for _, node := range controller.Nodes {
+		zone := node.Zone()
+		if _, exists := nodesByZone[zone]; !exists {
+			zones = append(zones, zone)
+		}
+		nodesByZone[zone] = append(nodesByZone[zone], node)
+	}
+	if len(zones) == 0 {
+		zones = append(zones, "")
+	}
+
+	names := make([]string, 0)
+	controllers := make(map[string]*zonedController)
+	for _, zone := range zones {
+		templateData := model.NewTemplateData(environment, controller, instance)
+		templateData.Zone = zone
+		serviceGroupName, err := utilApplyTemplate(templateData, controller.ServiceGroupNameTemplate)
+		if err != nil {
+			return nil, err
+		}
+		zoned, exists := controllers[serviceGroupName]
+		if !exists {
+			zoneController := *controller
+			zoneController.Nodes = make([]*model.Node, 0)
+			zoned = &zonedController{serviceGroupName: serviceGroupName, controller: &zoneController}
+			controllers[serviceGroupName] = zoned
+			names = append(names, serviceGroupName)
+		}
+		zoned.controller.Nodes = append(zoned.controller.Nodes, nodesByZone[zone]...)
+	}
+
+	if len(names) == 1 {
+		return []*zonedController{&zonedController{serviceGroupName: names[0], controller: controller}}, nil
+	}
+
+	result := make([]*zonedController, len(names))
+	for idx, name := range names {
+		glog.Infof("Ingress controller %s is split by zone, %d of its nodes belong to service group %s", controller.Name, len(controllers[name].controller.Nodes), name)
+		result[idx] = controllers[name]
+	}
+	return result, nil
+}
+
+//mergeServiceGroupOptions fills options missing on the service group from another ingress controller sharing it, the first one set wins
+func mergeServiceGroupOptions(serviceGroup *model.ServiceGroup, controller *model.IngressController) {
+	if len(serviceGroup.Method) == 0 {
+		serviceGroup.Method = controller.Method
+	} else if len(controller.Method) > 0 && controller.Method != serviceGroup.Method {
+		glog.Warningf("Ingress controller %s requests load balancing method %s for service group %s which already uses %s", controller.Name, controller.Method, serviceGroup.Name, serviceGroup.Method)
+	}
+	if len(serviceGroup.Protocol) == 0 {
+		serviceGroup.Protocol = controller.Protocol
+	} else if len(controller.Protocol) > 0 && controller.Protocol != serviceGroup.Protocol {
+		glog.Warningf("Ingress controller %s requests protocol %s for service group %s which already uses %s", controller.Name, controller.Protocol, serviceGroup.Name, serviceGroup.Protocol)
+	}
+	if serviceGroup.MinActiveMembers == 0 {
+		serviceGroup.MinActiveMembers = controller.MinActiveMembers
+	}
+}
+
+func (processor k8sProcessorImpl) FindLoadBalancers() ([]*model.LoadBalancer, error) {
+	loadBalancers, err := processor.k8sClient.GetLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(model.LoadBalancers(loadBalancers))
+	glog.Infof("Found %d services of type LoadBalancer", len(loadBalancers))
+	return loadBalancers, nil
+}
+
+func (processor k8sProcessorImpl) AssignVIPs(loadBalancers []*model.LoadBalancer, vips []string) {
+	used := make(map[string]bool)
+	pending := make([]*model.LoadBalancer, 0)
+
+	//services keep the vip they already have as long as it is still part of the pool
+	for _, loadBalancer := range loadBalancers {
+		if len(loadBalancer.VIP) > 0 && util.Contains(vips, loadBalancer.VIP) && !used[loadBalancer.VIP] &&
+			(len(loadBalancer.RequestedIP) == 0 || loadBalancer.RequestedIP == loadBalancer.VIP) {
+			used[loadBalancer.VIP] = true
+			continue
+		}
+		pending = append(pending, loadBalancer)
+	}
+
+	for _, loadBalancer := range pending {
+		vip := ""
+		if len(loadBalancer.RequestedIP) > 0 {
+			if util.Contains(vips, loadBalancer.RequestedIP) && !used[loadBalancer.RequestedIP] {
+				vip = loadBalancer.RequestedIP
+			} else {
+				glog.Errorf("Requested ip %s for service %s/%s is not available in the vip pool", loadBalancer.RequestedIP, loadBalancer.Namespace, loadBalancer.Name)
+			}
+		} else {
+			for _, candidate := range vips {
+				if !used[candidate] {
+					vip = candidate
+					break
+				}
+			}
+			if len(vip) == 0 {
+				glog.Errorf("VIP pool is exhausted, service %s/%s won't get a vip", loadBalancer.Namespace, loadBalancer.Name)
+			}
+		}
+
+		if len(vip) == 0 {
+			loadBalancer.VIP = ""
+			continue
+		}
+
+		used[vip] = true
+		loadBalancer.VIP = vip
+		glog.Infof("Assigning vip %s to service %s/%s", vip, loadBalancer.Namespace, loadBalancer.Name)
+	}
+}
+
+//PublishLoadBalancerStatus writes the assigned vip to the status of the service unless it is already there,
+//it is meant to be called once virtual servers of the load balancer are synced
+func (processor k8sProcessorImpl) PublishLoadBalancerStatus(loadBalancer *model.LoadBalancer) error {
+	if len(loadBalancer.VIP) == 0 || loadBalancer.VIP == loadBalancer.StatusVIP {
+		return nil
+	}
+	glog.Infof("Publishing vip %s of service %s/%s", loadBalancer.VIP, loadBalancer.Namespace, loadBalancer.Name)
+	err := processor.k8sClient.UpdateLoadBalancerStatus(loadBalancer)
+	if err != nil {
+		return err
+	}
+	loadBalancer.StatusVIP = loadBalancer.VIP
+	return nil
+}
+
+func (processor k8sProcessorImpl) BuildLoadBalancerServiceGroups(loadBalancers []*model.LoadBalancer, nodes []*model.Node, namePrefix string) map[string]*model.ServiceGroup {
+	serviceGroups := make(map[string]*model.ServiceGroup)
+
+	for _, loadBalancer := range loadBalancers {
+		//leave room for the -port suffix so service group names keep the virtual server name as prefix
+		loadBalancer.VirtualServerName = util.HashSuffix(util.A10NameMaxLength-6, util.SanitizeA10Name(fmt.Sprintf("%s%s-%s", namePrefix, loadBalancer.Namespace, loadBalancer.Name)))
+		for _, port := range loadBalancer.Ports {
+			if port.Protocol != "tcp" {
+				glog.Warningf("Skipping port %d of service %s/%s, protocol %s is not supported", port.Port, loadBalancer.Namespace, loadBalancer.Name, port.Protocol)
+				continue
+			}
+			if port.NodePort == 0 {
+				glog.Warningf("Skipping port %d of service %s/%s, node port is not allocated", port.Port, loadBalancer.Namespace, loadBalancer.Name)
+				continue
+			}
+
+			port.ServiceGroupName = fmt.Sprintf("%s-%d", loadBalancer.VirtualServerName, port.Port)
+			serviceGroups[port.ServiceGroupName] = &model.ServiceGroup{
+				Name:     port.ServiceGroupName,
+				Protocol: model.ProtocolTCP,
+				IngressControllers: []*model.IngressController{
+					&model.IngressController{
+						Name:  fmt.Sprintf("%s/%s", loadBalancer.Namespace, loadBalancer.Name),
+						Nodes: nodes,
+						Port:  port.NodePort,
+					},
+				},
+			}
+		}
+	}
+
+	return serviceGroups
+}
+
+func (processor k8sProcessorImpl) FindServiceGroupResources() ([]*model.A10ServiceGroup, error) {
+	resources, err := processor.k8sClient.GetA10ServiceGroups()
+	if err != nil {
+		return nil, err
+	}
+	glog.Infof("Found %d A10ServiceGroup resources", len(resources))
+	return resources, nil
+}
+
+func (processor k8sProcessorImpl) BuildResourceServiceGroups(resources []*model.A10ServiceGroup, environment *model.Environment) map[string]*model.ServiceGroup {
+	serviceGroups := make(map[string]*model.ServiceGroup)
+	owners := make(map[string]*model.A10ServiceGroup)
+
+	for _, resource := range resources {
+		if len(resource.Error) > 0 {
+			continue
+		}
+
+		serviceGroupName, err := utilApplyTemplate(&model.TemplateData{
+			Environment: environment,
+			Name:        resource.Name,
+			Namespace:   resource.Namespace,
+			Port:        resource.Ports[0],
+		}, resource.ServiceGroupNameTemplate)
+		if err != nil {
+			resource.Error = fmt.Sprintf("Failed to build service group name. error: %s", err)
+			glog.Errorf("A10ServiceGroup %s/%s: %s", resource.Namespace, resource.Name, resource.Error)
+			continue
+		}
+		serviceGroupName = sanitizeServiceGroupName(serviceGroupName, fmt.Sprintf("A10ServiceGroup %s/%s", resource.Namespace, resource.Name))
+		if owner, exists := owners[serviceGroupName]; exists {
+			resource.Error = fmt.Sprintf("Service group %s is already declared by %s/%s", serviceGroupName, owner.Namespace, owner.Name)
+			glog.Errorf("A10ServiceGroup %s/%s: %s", resource.Namespace, resource.Name, resource.Error)
+			continue
+		}
+
+		nodes, err := processor.findResourceNodes(resource)
+		if err != nil {
+			resource.Error = fmt.Sprintf("Failed to find nodes. error: %s", err)
+			glog.Errorf("A10ServiceGroup %s/%s: %s", resource.Namespace, resource.Name, resource.Error)
+			continue
+		}
+
+		resource.ServiceGroupName = serviceGroupName
+		owners[serviceGroupName] = resource
+		glog.Infof("A10ServiceGroup %s/%s declares service group %s", resource.Namespace, resource.Name, serviceGroupName)
+
+		serviceGroup := &model.ServiceGroup{
+			Name:               serviceGroupName,
+			Method:             resource.Method,
+			Protocol:           resource.Protocol,
+			MinActiveMembers:   resource.MinActiveMembers,
+			A10Instances:       resource.A10Instances,
+			Resource:           resource,
+			IngressControllers: make([]*model.IngressController, 0),
+		}
+		if resource.Health != nil {
+			healthCheck := *resource.Health
+			healthCheck.Name = serviceGroupName
+			serviceGroup.Health = &healthCheck
+		}
+		for _, port := range resource.Ports {
+			serviceGroup.IngressControllers = append(serviceGroup.IngressControllers, &model.IngressController{
+				Name:          fmt.Sprintf("%s/%s", resource.Namespace, resource.Name),
+				NodeSelectors: resource.NodeSelectors,
+				Nodes:         nodes,
+				Port:          port,
+			})
+		}
+		serviceGroups[serviceGroupName] = serviceGroup
+	}
+
+	return serviceGroups
+}
+
+func (processor k8sProcessorImpl) findResourceNodes(resource *model.A10ServiceGroup) ([]*model.Node, error) {
+	nodes, err := processor.FindNodes(resource.NodeSelectors)
+	if err != nil || len(resource.PodSelectors) == 0 {
+		return nodes, err
+	}
+
+	podNodeNames, err := processor.k8sClient.GetPodNodeNames(resource.Namespace, resource.PodSelectors)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodes := make([]*model.Node, 0)
+	for _, node := range nodes {
+		if util.Contains(podNodeNames, node.Name) {
+			podNodes = append(podNodes, node)
+		}
+	}
+	return podNodes, nil
+}
+
+func (processor k8sProcessorImpl) UpdateServiceGroupResourceStatus(resource *model.A10ServiceGroup, results []*model.SyncResult) error {
+	return processor.k8sClient.UpdateA10ServiceGroupStatus(resource, results)
+}
+
+//syncObject kubernetes object sync events were recorded for
+type syncObject struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+//PublishSyncEvents publishes sync events of every kubernetes object together with the sync status of each a10 instance,
+//only failures to sync mark the status as failed
+func (processor k8sProcessorImpl) PublishSyncEvents(events []*model.SyncEvent) error {
+	objects := make([]syncObject, 0)
+	objectEvents := make(map[syncObject][]*model.SyncEvent)
+	for _, event := range events {
+		object := syncObject{kind: event.Kind, namespace: event.Namespace, name: event.Name}
+		if _, exists := objectEvents[object]; !exists {
+			objects = append(objects, object)
+		}
+		objectEvents[object] = append(objectEvents[object], event)
+	}
+
+	lastSync := timeNow().UTC().Format(time.RFC3339)
+	var err error
+	for _, object := range objects {
+		notableEvents := make([]*model.SyncEvent, 0)
+		statuses := make(map[string]*model.SyncStatus)
+		for _, event := range objectEvents[object] {
+			status, exists := statuses[event.Instance]
+			if !exists {
+				status = &model.SyncStatus{LastSync: lastSync, Result: model.SyncResultSynced}
+				statuses[event.Instance] = status
+			}
+			//warnings about outcomes of policies, like kept out of band changes, don't fail the sync
+			if event.Reason == model.ReasonSyncFailed {
+				status.Result = model.SyncResultFailed
+				status.Message = event.Message
+			}
+			//being in sync is reflected by the status only, events would just pile up
+			if event.Reason != model.ReasonInSync {
+				notableEvents = append(notableEvents, event)
+			}
+		}
+
+		publishErr := processor.k8sClient.PublishSyncStatus(object.kind, object.namespace, object.name, notableEvents, statuses)
+		if publishErr != nil {
+			glog.Errorf("Failed to publish sync status of %s %s. error: %s", object.kind, object.name, publishErr)
+			err = publishErr
+		}
+	}
+
+	return err
+}
+
+//GetTrafficShiftStates reads progress of traffic shifts by shift name, states which can't be parsed are left out so their shifts start over
+func (processor k8sProcessorImpl) GetTrafficShiftStates(namespace, configMapName string) (map[string]*model.TrafficShiftState, error) {
+	states := make(map[string]*model.TrafficShiftState)
+	configMap, err := processor.k8sClient.GetConfigMap(namespace, configMapName)
+	if err != nil || configMap == nil {
+		return states, err
+	}
+
+	for name, data := range configMap.Data {
+		state := &model.TrafficShiftState{}
+		err := json.Unmarshal([]byte(data), state)
+		if err != nil {
+			glog.Errorf("Failed to parse state of traffic shift %s from config map %s/%s. error: %s", name, namespace, configMapName, err)
+			continue
+		}
+		states[name] = state
+	}
+	return states, nil
+}
+
+//SaveTrafficShiftStates persists progress of traffic shifts by shift name
+func (processor k8sProcessorImpl) SaveTrafficShiftStates(namespace, configMapName string, states map[string]*model.TrafficShiftState) error {
+	data := make(map[string]string)
+	for name, state := range states {
+		data[name] = util.ToJSON(state)
+	}
+	return processor.k8sClient.SaveConfigMap(&model.ConfigMap{
+		Name:      configMapName,
+		Namespace: namespace,
+		Data:      data,
+	})
+}
+
+//GetAppliedStates reads states applied to a10 instances by instance name, states which can't be parsed are left out so their instances are diffed as on the first run
+func (processor k8sProcessorImpl) GetAppliedStates(namespace, configMapName string) (map[string]*model.AppliedState, error) {
+	states := make(map[string]*model.AppliedState)
+	configMap, err := processor.k8sClient.GetConfigMap(namespace, configMapName)
+	if err != nil || configMap == nil {
+		return states, err
+	}
+
+	for instance, data := range configMap.Data {
+		state := model.NewAppliedState()
+		err := json.Unmarshal([]byte(data), state)
+		if err != nil {
+			glog.Errorf("Failed to parse state applied to a10 %s from config map %s/%s. error: %s", instance, namespace, configMapName, err)
+			continue
+		}
+		states[instance] = state
+	}
+	return states, nil
+}
+
+//SaveAppliedStates persists states applied to a10 instances by instance name
+func (processor k8sProcessorImpl) SaveAppliedStates(namespace, configMapName string, states map[string]*model.AppliedState) error {
+	data := make(map[string]string)
+	for instance, state := range states {
+		data[instance] = util.ToJSON(state)
+	}
+	return processor.k8sClient.SaveConfigMap(&model.ConfigMap{
+		Name:      configMapName,
+		Namespace: namespace,
+		Data:      data,
+	})
+}
+
+//sanitizeServiceGroupName makes rendered service group name acceptable for a10, the result is stable across runs
+func sanitizeServiceGroupName(serviceGroupName, source string) string {
+	sanitized := util.SanitizeA10Name(serviceGroupName)
+	if sanitized != serviceGroupName {
+		glog.Warningf("Service group name %s of %s is not a valid a10 name, using %s instead", serviceGroupName, source, sanitized)
+	}
+	return sanitized
+}
@@ -1,12 +1,17 @@
 package processor_test
 
 import (
+	"a10bridge/config"
 	"a10bridge/mocks"
 	"a10bridge/model"
 	"a10bridge/processor"
+	"a10bridge/util"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,12 +42,80 @@ func (suite *K8sProcessorTestSuite) TestBuildEnvironment() {
 	}
 
 	client.On("GetConfigMap", "ingress", "cluster-configs").Once().Return(configMap, nil)
-	env, err := processor.BuildEnvironment()
+	env, err := processor.BuildEnvironment(config.DefaultEnvironmentConfig())
 	suite.Assert().Nil(err)
 	suite.Assert().NotNil(env)
 	suite.Assert().Equal("dc", env.DataCenter)
 	suite.Assert().Equal("type", env.Type)
 	suite.Assert().Equal("dc-type", env.Cluster)
+	suite.Assert().Equal(configMap.Data, env.Data)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildEnvironment_customConfig() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	configMap := &model.ConfigMap{
+		Name:      "cluster-info",
+		Namespace: "kube-system",
+		Data: map[string]string{
+			"cluster": "us-east-1-prod",
+			"region":  "us-east",
+		},
+	}
+	environmentConfig := &config.EnvironmentConfig{
+		Namespace: "kube-system",
+		ConfigMap: "cluster-info",
+		NameKey:   "cluster",
+		Pattern:   "^(?P<dataCenter>.*)-(?P<type>[^-]*)$",
+	}
+
+	client.On("GetConfigMap", "kube-system", "cluster-info").Once().Return(configMap, nil)
+	env, err := processor.BuildEnvironment(environmentConfig)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(&model.Environment{
+		Cluster:    "us-east-1-prod",
+		DataCenter: "us-east-1",
+		Type:       "prod",
+		Data:       configMap.Data,
+	}, env)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildEnvironment_nameDoesNotMatchPattern() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	configMap := &model.ConfigMap{
+		Data: map[string]string{
+			"name": "dc",
+		},
+	}
+	environmentConfig := config.DefaultEnvironmentConfig()
+	environmentConfig.Pattern = "^(?P<dataCenter>[^-]*)-(?P<type>[^-]*)$"
+
+	client.On("GetConfigMap", "ingress", "cluster-configs").Once().Return(configMap, nil)
+	env, err := processor.BuildEnvironment(environmentConfig)
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(env)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildEnvironment_invalidPattern() {
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	environmentConfig := config.DefaultEnvironmentConfig()
+	environmentConfig.Pattern = "("
+
+	env, err := processor.BuildEnvironment(environmentConfig)
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(env)
+	suite.client.AssertNotCalled(suite.T(), "GetConfigMap", "ingress", "cluster-configs")
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildEnvironment_configMapNotFound() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+
+	client.On("GetConfigMap", "ingress", "cluster-configs").Once().Return(nil, nil)
+	env, err := processor.BuildEnvironment(config.DefaultEnvironmentConfig())
+	suite.Assert().NotNil(err)
+	suite.Assert().Nil(env)
 }
 
 func (suite *K8sProcessorTestSuite) TestBuildEnvironment_getConfigMapFails() {
@@ -50,7 +123,7 @@ func (suite *K8sProcessorTestSuite) TestBuildEnvironment_getConfigMapFails() {
 	processor := suite.helper.BuildK8sProcessor(client)
 
 	client.On("GetConfigMap", "ingress", "cluster-configs").Once().Return(nil, errors.New("crap"))
-	env, err := processor.BuildEnvironment()
+	env, err := processor.BuildEnvironment(config.DefaultEnvironmentConfig())
 	suite.Assert().NotNil(err)
 	suite.Assert().Nil(env)
 }
@@ -67,7 +140,7 @@ func (suite *K8sProcessorTestSuite) TestBuildEnvironment_configWithoutClusterNam
 	}
 
 	client.On("GetConfigMap", "ingress", "cluster-configs").Once().Return(configMap, nil)
-	env, err := processor.BuildEnvironment()
+	env, err := processor.BuildEnvironment(config.DefaultEnvironmentConfig())
 	suite.Assert().NotNil(err)
 	suite.Assert().Nil(env)
 }
@@ -125,11 +198,11 @@ func (suite *K8sProcessorTestSuite) TestFindIngressControllers() {
 	controller1 := model.IngressController{
 		Name: "ingress1",
 		Health: &model.HealthCheck{
-			Name:       "health1",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   10,
-			Port:       8080,
+			Name:                      "health1",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  10,
+			Port:                      8080,
 			RequiredConsecutivePasses: 5,
 			RetryCount:                5,
 			Timeout:                   10,
@@ -137,17 +210,17 @@ func (suite *K8sProcessorTestSuite) TestFindIngressControllers() {
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress1-{{.ClusterName}}",
 	}
 	controller2 := model.IngressController{
 		Name: "ingress2",
 		Health: &model.HealthCheck{
-			Name:       "health2",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   10,
-			Port:       8080,
+			Name:                      "health2",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  10,
+			Port:                      8080,
 			RequiredConsecutivePasses: 5,
 			RetryCount:                5,
 			Timeout:                   10,
@@ -155,7 +228,7 @@ func (suite *K8sProcessorTestSuite) TestFindIngressControllers() {
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress2-{{.ClusterName}}",
 	}
 	expectedControllers := []*model.IngressController{&controller1, &controller2}
@@ -177,6 +250,31 @@ func (suite *K8sProcessorTestSuite) TestFindIngressControllers_getIngressControl
 	suite.Assert().Nil(controllers)
 }
 
+func (suite *K8sProcessorTestSuite) TestCountControllerPods() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	web := &model.IngressController{Name: "web", Namespace: "ingress", PodSelectors: map[string]string{"app": "web"}}
+	api := &model.IngressController{Name: "api", Namespace: "ingress", PodSelectors: map[string]string{"app": "api"}}
+	noSelectors := &model.IngressController{Name: "legacy", Namespace: "ingress"}
+	client.On("GetReadyPodCounts", "ingress", web.PodSelectors).Once().Return(map[string]int{"node1": 1, "node2": 1}, nil)
+	client.On("GetReadyPodCounts", "ingress", api.PodSelectors).Once().Return(map[string]int{"node2": 2}, nil)
+
+	counts, err := processor.CountControllerPods([]*model.IngressController{web, api, noSelectors})
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]int{"node1": 1, "node2": 3}, counts)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestCountControllerPods_fails() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	web := &model.IngressController{Name: "web", Namespace: "ingress", PodSelectors: map[string]string{"app": "web"}}
+	client.On("GetReadyPodCounts", "ingress", web.PodSelectors).Once().Return(nil, errors.New("fail"))
+
+	_, err := processor.CountControllerPods([]*model.IngressController{web})
+	suite.Assert().NotNil(err)
+}
+
 func (suite *K8sProcessorTestSuite) TestBuildServiceGroups() {
 	expectedServiceGroupName := "service-group-name"
 	suite.helper.SetUtilApplyTemplate(func(data interface{}, tpl string) (string, error) {
@@ -187,11 +285,11 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups() {
 	controller := model.IngressController{
 		Name: "ingress1",
 		Health: &model.HealthCheck{
-			Name:       "health1",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   10,
-			Port:       8080,
+			Name:                      "health1",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  10,
+			Port:                      8080,
 			RequiredConsecutivePasses: 5,
 			RetryCount:                5,
 			Timeout:                   10,
@@ -199,7 +297,7 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups() {
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress1-{{.ClusterName}}",
 	}
 	controllers := []*model.IngressController{&controller}
@@ -209,7 +307,7 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups() {
 		Type:       "type",
 	}
 
-	serviceGroups := processor.BuildServiceGroups(controllers, &environment)
+	serviceGroups := processor.BuildServiceGroups(controllers, &environment, "lb")
 	suite.Assert().NotNil(serviceGroups)
 	suite.Assert().Equal(1, len(serviceGroups))
 	actualServiceGroup, found := serviceGroups[expectedServiceGroupName]
@@ -236,11 +334,11 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_collapseControllersWi
 	controller1 := model.IngressController{
 		Name: "ingress1",
 		Health: &model.HealthCheck{
-			Name:       "health1",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   10,
-			Port:       8080,
+			Name:                      "health1",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  10,
+			Port:                      8080,
 			RequiredConsecutivePasses: 5,
 			RetryCount:                5,
 			Timeout:                   10,
@@ -248,17 +346,17 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_collapseControllersWi
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress1-{{.ClusterName}}",
 	}
 	controller2 := model.IngressController{
 		Name: "ingress2",
 		Health: &model.HealthCheck{
-			Name:       "health2",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   12,
-			Port:       8082,
+			Name:                      "health2",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  12,
+			Port:                      8082,
 			RequiredConsecutivePasses: 7,
 			RetryCount:                7,
 			Timeout:                   12,
@@ -266,7 +364,7 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_collapseControllersWi
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress2-{{.ClusterName}}",
 	}
 	controllers := []*model.IngressController{&controller1, &controller2}
@@ -276,7 +374,7 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_collapseControllersWi
 		Type:       "type",
 	}
 
-	serviceGroups := processor.BuildServiceGroups(controllers, &environment)
+	serviceGroups := processor.BuildServiceGroups(controllers, &environment, "lb")
 	suite.Assert().NotNil(serviceGroups)
 	suite.Assert().Equal(1, len(serviceGroups))
 	actualServiceGroup, found := serviceGroups[expectedServiceGroupName]
@@ -293,6 +391,37 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_collapseControllersWi
 	suite.Assert().Equal(controller1.Health.Timeout, actualServiceGroup.Health.Timeout)
 }
 
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_options() {
+	suite.helper.SetUtilApplyTemplate(func(data interface{}, tpl string) (string, error) {
+		return "service-group-name", nil
+	})
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	controller1 := model.IngressController{
+		Name:                     "ingress1",
+		Health:                   &model.HealthCheck{Endpoint: "/health", Port: 8080},
+		Port:                     80,
+		ServiceGroupNameTemplate: "tpl",
+		Protocol:                 model.ProtocolUDP,
+	}
+	controller2 := model.IngressController{
+		Name:                     "ingress2",
+		Health:                   &model.HealthCheck{Endpoint: "/health", Port: 8080},
+		Port:                     80,
+		ServiceGroupNameTemplate: "tpl",
+		Method:                   "least-connection",
+		Protocol:                 model.ProtocolTCP,
+		MinActiveMembers:         2,
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller1, &controller2}, &model.Environment{}, "lb")
+	serviceGroup := serviceGroups["service-group-name"]
+	suite.Assert().NotNil(serviceGroup)
+	suite.Assert().Equal("least-connection", serviceGroup.Method)
+	suite.Assert().Equal(model.ProtocolUDP, serviceGroup.Protocol)
+	suite.Assert().Equal(2, serviceGroup.MinActiveMembers)
+}
+
 func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_applyTemplateFails() {
 	suite.helper.SetUtilApplyTemplate(func(data interface{}, tpl string) (string, error) {
 		return "", errors.New("fail")
@@ -302,11 +431,11 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_applyTemplateFails()
 	controller := model.IngressController{
 		Name: "ingress1",
 		Health: &model.HealthCheck{
-			Name:       "health1",
-			Endpoint:   "/health",
-			ExpectCode: "200",
-			Interval:   10,
-			Port:       8080,
+			Name:                      "health1",
+			Endpoint:                  "/health",
+			ExpectCode:                "200",
+			Interval:                  10,
+			Port:                      8080,
 			RequiredConsecutivePasses: 5,
 			RetryCount:                5,
 			Timeout:                   10,
@@ -314,7 +443,7 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_applyTemplateFails()
 		NodeSelectors: map[string]string{
 			"ingress": "true",
 		},
-		Port: 80,
+		Port:                     80,
 		ServiceGroupNameTemplate: "ingress1-{{.ClusterName}}",
 	}
 	controllers := []*model.IngressController{&controller}
@@ -324,7 +453,487 @@ func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_applyTemplateFails()
 		Type:       "type",
 	}
 
-	serviceGroups := processor.BuildServiceGroups(controllers, &environment)
+	serviceGroups := processor.BuildServiceGroups(controllers, &environment, "lb")
 	suite.Assert().NotNil(serviceGroups)
 	suite.Assert().Equal(0, len(serviceGroups))
 }
+
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_templateData() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	controller := model.IngressController{
+		Name:                     "ingress1",
+		Namespace:                "ingress",
+		Labels:                   map[string]string{"team": "web"},
+		Annotations:              map[string]string{"a10.service_group": "ignored"},
+		Health:                   &model.HealthCheck{Endpoint: "/health", Port: 8080},
+		Port:                     80,
+		ServiceGroupNameTemplate: "{{.Instance}}-{{.DataCenter}}-{{.Data.region}}-{{.Namespace}}-{{.Name}}-{{.Labels.team}}-{{.Port}}",
+	}
+	environment := model.Environment{
+		Cluster:    "dc-type",
+		DataCenter: "dc",
+		Type:       "type",
+		Data:       map[string]string{"region": "east"},
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller}, &environment, "lb01")
+	suite.Assert().Len(serviceGroups, 1)
+	suite.Assert().Contains(serviceGroups, "lb01-dc-east-ingress-ingress1-web-80")
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_additionalPorts() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	nodes := []*model.Node{&model.Node{Name: "node1", A10Server: "server1"}}
+	controller := model.IngressController{
+		Name:                     "ingress1",
+		Health:                   &model.HealthCheck{Endpoint: "/health", Port: 10254},
+		Nodes:                    nodes,
+		Port:                     80,
+		PortName:                 "http",
+		ServiceGroupNameTemplate: "web-{{.PortName}}",
+		Ports: []*model.ControllerPort{
+			&model.ControllerPort{
+				Name:                     "https",
+				Port:                     443,
+				ServiceGroupNameTemplate: "web-{{.PortName}}",
+				Health:                   &model.HealthCheck{Endpoint: "/health", Port: 10255},
+			},
+		},
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller}, &model.Environment{}, "lb")
+	suite.Assert().Len(serviceGroups, 2)
+
+	http := serviceGroups["web-http"]
+	suite.Assert().Equal(80, http.IngressControllers[0].Port)
+	suite.Assert().Equal("web-http", http.Health.Name)
+	suite.Assert().Equal(10254, http.Health.Port)
+
+	https := serviceGroups["web-https"]
+	suite.Assert().Equal(443, https.IngressControllers[0].Port)
+	suite.Assert().Equal(nodes, https.IngressControllers[0].Nodes)
+	suite.Assert().Equal("web-https", https.Health.Name)
+	suite.Assert().Equal(10255, https.Health.Port)
+	suite.Assert().Equal("/health", https.Health.Endpoint)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_splitByZone() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	node1 := &model.Node{Name: "node1", A10Server: "server1", Labels: map[string]string{model.LabelZone: "hall-a"}}
+	node2 := &model.Node{Name: "node2", A10Server: "server2", Labels: map[string]string{model.LabelZoneBeta: "hall-b"}}
+	node3 := &model.Node{Name: "node3", A10Server: "server3", Labels: map[string]string{model.LabelZone: "hall-a"}}
+	controller := model.IngressController{
+		Name:                     "ingress1",
+		Health:                   &model.HealthCheck{Endpoint: "/health", Port: 10254},
+		Nodes:                    []*model.Node{node1, node2, node3},
+		Port:                     80,
+		ServiceGroupNameTemplate: "web-{{.Zone}}",
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller}, &model.Environment{}, "lb")
+	suite.Assert().Len(serviceGroups, 2)
+	suite.Assert().Equal([]*model.Node{node1, node3}, serviceGroups["web-hall-a"].IngressControllers[0].Nodes)
+	suite.Assert().Equal("web-hall-a", serviceGroups["web-hall-a"].Health.Name)
+	suite.Assert().Equal([]*model.Node{node2}, serviceGroups["web-hall-b"].IngressControllers[0].Nodes)
+	suite.Assert().Equal("web-hall-b", serviceGroups["web-hall-b"].Health.Name)
+	suite.Assert().Equal("/health", serviceGroups["web-hall-b"].Health.Endpoint)
+	suite.Assert().Len(controller.Nodes, 3)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_zoneNotInTemplate() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	controller := model.IngressController{
+		Name:   "ingress1",
+		Health: &model.HealthCheck{Endpoint: "/health", Port: 10254},
+		Nodes: []*model.Node{
+			&model.Node{Name: "node1", A10Server: "server1", Labels: map[string]string{model.LabelZone: "hall-a"}},
+			&model.Node{Name: "node2", A10Server: "server2", Labels: map[string]string{model.LabelZone: "hall-b"}},
+		},
+		Port:                     80,
+		ServiceGroupNameTemplate: "web",
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller}, &model.Environment{}, "lb")
+	suite.Assert().Len(serviceGroups, 1)
+	suite.Assert().Equal([]*model.IngressController{&controller}, serviceGroups["web"].IngressControllers)
+	suite.Assert().Equal("/health", serviceGroups["web"].Health.Endpoint)
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildServiceGroups_sanitizesName() {
+	suite.helper.SetUtilApplyTemplate(func(data interface{}, tpl string) (string, error) {
+		return "web group/" + strings.Repeat("x", 70), nil
+	})
+	processor := suite.helper.BuildK8sProcessor(suite.client)
+	controller := model.IngressController{
+		Name:   "ingress1",
+		Health: &model.HealthCheck{Endpoint: "/health", Port: 8080},
+	}
+
+	serviceGroups := processor.BuildServiceGroups([]*model.IngressController{&controller}, &model.Environment{}, "lb")
+	serviceGroups2 := processor.BuildServiceGroups([]*model.IngressController{&controller}, &model.Environment{}, "lb")
+	suite.Assert().Len(serviceGroups, 1)
+	for name, serviceGroup := range serviceGroups {
+		suite.Assert().Nil(util.ValidateA10Name(name))
+		suite.Assert().True(strings.HasPrefix(name, "web-group-xxx"))
+		suite.Assert().Equal(name, serviceGroup.Health.Name)
+		suite.Assert().Contains(serviceGroups2, name)
+	}
+}
+
+func (suite *K8sProcessorTestSuite) TestFindLoadBalancers() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	loadBalancers := []*model.LoadBalancer{
+		&model.LoadBalancer{Name: "web", Namespace: "prod"},
+		&model.LoadBalancer{Name: "web", Namespace: "dev"},
+	}
+
+	client.On("GetLoadBalancers").Once().Return(loadBalancers, nil)
+	result, err := processor.FindLoadBalancers()
+	suite.Assert().Nil(err)
+	suite.Assert().Len(result, 2)
+	suite.Assert().Equal("dev", result[0].Namespace)
+	suite.Assert().Equal("prod", result[1].Namespace)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestFindLoadBalancers_getLoadBalancersFails() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+
+	client.On("GetLoadBalancers").Once().Return(nil, errors.New("fail"))
+	_, err := processor.FindLoadBalancers()
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestAssignVIPs() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	vips := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}
+	existing := &model.LoadBalancer{Name: "existing", Namespace: "default", VIP: "10.0.0.1"}
+	requested := &model.LoadBalancer{Name: "requested", Namespace: "default", RequestedIP: "10.0.0.3"}
+	fresh := &model.LoadBalancer{Name: "fresh", Namespace: "default"}
+	outOfPool := &model.LoadBalancer{Name: "outofpool", Namespace: "default", VIP: "192.168.0.1"}
+
+	processor.AssignVIPs([]*model.LoadBalancer{existing, requested, fresh, outOfPool}, vips)
+
+	suite.Assert().Equal("10.0.0.1", existing.VIP)
+	suite.Assert().Equal("10.0.0.3", requested.VIP)
+	suite.Assert().Equal("10.0.0.2", fresh.VIP)
+	suite.Assert().Equal("10.0.0.4", outOfPool.VIP)
+	client.AssertNotCalled(suite.T(), "UpdateLoadBalancerStatus", mock.Anything)
+}
+
+func (suite *K8sProcessorTestSuite) TestPublishLoadBalancerStatus() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	published := &model.LoadBalancer{Name: "published", Namespace: "default", VIP: "10.0.0.1", StatusVIP: "10.0.0.1"}
+	assigned := &model.LoadBalancer{Name: "assigned", Namespace: "default", VIP: "10.0.0.2"}
+	failing := &model.LoadBalancer{Name: "failing", Namespace: "default", VIP: "10.0.0.3"}
+	noVIP := &model.LoadBalancer{Name: "novip", Namespace: "default"}
+
+	client.On("UpdateLoadBalancerStatus", assigned).Once().Return(nil)
+	client.On("UpdateLoadBalancerStatus", failing).Once().Return(errors.New("fail"))
+	suite.Assert().Nil(processor.PublishLoadBalancerStatus(published))
+	suite.Assert().Nil(processor.PublishLoadBalancerStatus(assigned))
+	suite.Assert().NotNil(processor.PublishLoadBalancerStatus(failing))
+	suite.Assert().Nil(processor.PublishLoadBalancerStatus(noVIP))
+
+	suite.Assert().Equal("10.0.0.2", assigned.StatusVIP)
+	suite.Assert().Equal("", failing.StatusVIP)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestAssignVIPs_requestedIPNotAvailable() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	vips := []string{"10.0.0.1"}
+	existing := &model.LoadBalancer{Name: "existing", Namespace: "default", VIP: "10.0.0.1"}
+	taken := &model.LoadBalancer{Name: "taken", Namespace: "default", RequestedIP: "10.0.0.1"}
+	outOfPool := &model.LoadBalancer{Name: "outofpool", Namespace: "default", RequestedIP: "10.0.0.9"}
+	exhausted := &model.LoadBalancer{Name: "exhausted", Namespace: "default"}
+
+	processor.AssignVIPs([]*model.LoadBalancer{existing, taken, outOfPool, exhausted}, vips)
+
+	suite.Assert().Equal("10.0.0.1", existing.VIP)
+	suite.Assert().Equal("", taken.VIP)
+	suite.Assert().Equal("", outOfPool.VIP)
+	suite.Assert().Equal("", exhausted.VIP)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildLoadBalancerServiceGroups() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	nodes := []*model.Node{&model.Node{Name: "node1", A10Server: "node1"}}
+	loadBalancer := &model.LoadBalancer{
+		Name:      "web",
+		Namespace: "default",
+		VIP:       "10.0.0.1",
+		Ports: []*model.LoadBalancerPort{
+			&model.LoadBalancerPort{Name: "http", Protocol: "tcp", Port: 80, NodePort: 30080},
+			&model.LoadBalancerPort{Name: "dns", Protocol: "udp", Port: 53, NodePort: 30053},
+			&model.LoadBalancerPort{Name: "pending", Protocol: "tcp", Port: 443},
+		},
+	}
+
+	serviceGroups := processor.BuildLoadBalancerServiceGroups([]*model.LoadBalancer{loadBalancer}, nodes, "k8s-")
+	suite.Assert().Len(serviceGroups, 1)
+	suite.Assert().Equal("k8s-default-web", loadBalancer.VirtualServerName)
+	suite.Assert().Equal("k8s-default-web-80", loadBalancer.Ports[0].ServiceGroupName)
+	suite.Assert().Equal("", loadBalancer.Ports[1].ServiceGroupName)
+	suite.Assert().Equal("", loadBalancer.Ports[2].ServiceGroupName)
+
+	serviceGroup := serviceGroups["k8s-default-web-80"]
+	suite.Assert().NotNil(serviceGroup)
+	suite.Assert().Nil(serviceGroup.Health)
+	suite.Assert().Len(serviceGroup.IngressControllers, 1)
+	suite.Assert().Equal(30080, serviceGroup.IngressControllers[0].Port)
+	suite.Assert().Equal(nodes, serviceGroup.IngressControllers[0].Nodes)
+}
+
+func (suite *K8sProcessorTestSuite) TestFindServiceGroupResources() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	resources := []*model.A10ServiceGroup{&model.A10ServiceGroup{Name: "web"}}
+
+	client.On("GetA10ServiceGroups").Once().Return(resources, nil)
+	result, err := processor.FindServiceGroupResources()
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(resources, result)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestFindServiceGroupResources_getA10ServiceGroupsFails() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+
+	client.On("GetA10ServiceGroups").Once().Return(nil, errors.New("fail"))
+	_, err := processor.FindServiceGroupResources()
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildResourceServiceGroups() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	nodes := []*model.Node{
+		&model.Node{Name: "node1", Labels: map[string]string{"role": "ingress"}},
+		&model.Node{Name: "node2", Labels: map[string]string{"role": "ingress"}},
+		&model.Node{Name: "node3", Labels: map[string]string{"role": "worker"}},
+	}
+	web := &model.A10ServiceGroup{
+		Name:                     "web",
+		Namespace:                "ingress",
+		ServiceGroupNameTemplate: "{{.Cluster}}-web",
+		A10Instances:             []string{"lb01"},
+		NodeSelectors:            map[string]string{"role": "ingress"},
+		PodSelectors:             map[string]string{"app": "web"},
+		Ports:                    []int{80, 443},
+		Health:                   &model.HealthCheck{Endpoint: "/healthz", Port: 80},
+		Method:                   "least-connection",
+		Protocol:                 model.ProtocolUDP,
+		MinActiveMembers:         1,
+	}
+	duplicate := &model.A10ServiceGroup{
+		Name:                     "duplicate",
+		Namespace:                "default",
+		ServiceGroupNameTemplate: "{{.Cluster}}-web",
+		Ports:                    []int{80},
+	}
+	invalid := &model.A10ServiceGroup{Name: "invalid", Error: "spec.name is required"}
+	badTemplate := &model.A10ServiceGroup{Name: "badtemplate", ServiceGroupNameTemplate: "{{.Unknown}}", Ports: []int{80}}
+
+	client.On("GetNodes").Return(nodes, nil)
+	client.On("GetPodNodeNames", "ingress", web.PodSelectors).Once().Return([]string{"node2", "node3"}, nil)
+	serviceGroups := processor.BuildResourceServiceGroups([]*model.A10ServiceGroup{web, duplicate, invalid, badTemplate}, &model.Environment{Cluster: "dc-prod"})
+
+	suite.Assert().Len(serviceGroups, 1)
+	serviceGroup := serviceGroups["dc-prod-web"]
+	suite.Assert().NotNil(serviceGroup)
+	suite.Assert().Equal("dc-prod-web", web.ServiceGroupName)
+	suite.Assert().Equal(web, serviceGroup.Resource)
+	suite.Assert().Equal("least-connection", serviceGroup.Method)
+	suite.Assert().Equal(model.ProtocolUDP, serviceGroup.Protocol)
+	suite.Assert().Equal(1, serviceGroup.MinActiveMembers)
+	suite.Assert().Equal([]string{"lb01"}, serviceGroup.A10Instances)
+	suite.Assert().Equal("dc-prod-web", serviceGroup.Health.Name)
+	suite.Assert().Equal("/healthz", serviceGroup.Health.Endpoint)
+	suite.Assert().Equal("", web.Health.Name)
+	suite.Assert().Len(serviceGroup.IngressControllers, 2)
+	suite.Assert().Equal(80, serviceGroup.IngressControllers[0].Port)
+	suite.Assert().Equal(443, serviceGroup.IngressControllers[1].Port)
+	suite.Assert().Equal([]*model.Node{nodes[1]}, serviceGroup.IngressControllers[0].Nodes)
+
+	suite.Assert().Contains(duplicate.Error, "ingress/web")
+	suite.Assert().Equal("spec.name is required", invalid.Error)
+	suite.Assert().NotEmpty(badTemplate.Error)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestBuildResourceServiceGroups_findNodesFails() {
+	original := suite.helper.SetUtilApplyTemplate(util.ApplyTemplate)
+	defer suite.helper.SetUtilApplyTemplate(original)
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	resource := &model.A10ServiceGroup{Name: "web", ServiceGroupNameTemplate: "web", Ports: []int{80}}
+
+	client.On("GetNodes").Once().Return(nil, errors.New("fail"))
+	serviceGroups := processor.BuildResourceServiceGroups([]*model.A10ServiceGroup{resource}, &model.Environment{})
+	suite.Assert().Empty(serviceGroups)
+	suite.Assert().NotEmpty(resource.Error)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestUpdateServiceGroupResourceStatus() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	resource := &model.A10ServiceGroup{Name: "web"}
+	results := []*model.SyncResult{&model.SyncResult{Instance: "lb01"}}
+
+	client.On("UpdateA10ServiceGroupStatus", resource, results).Once().Return(nil)
+	err := processor.UpdateServiceGroupResourceStatus(resource, results)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestPublishSyncEvents() {
+	original := suite.helper.SetTimeNow(func() time.Time {
+		return time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	})
+	defer suite.helper.SetTimeNow(original)
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	created := &model.SyncEvent{Kind: model.KindNode, Name: "node1", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonCreated, Message: "created"}
+	inSync := &model.SyncEvent{Kind: model.KindNode, Name: "node1", Instance: "lb02", Type: model.EventTypeNormal, Reason: model.ReasonInSync, Message: "in sync"}
+	updated := &model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "controller", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonUpdated, Message: "updated"}
+	failed := &model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "controller", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonSyncFailed, Message: "failed"}
+
+	client.On("PublishSyncStatus", model.KindNode, "", "node1", []*model.SyncEvent{created}, map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+		"lb02": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+	}).Once().Return(nil)
+	client.On("PublishSyncStatus", model.KindDaemonSet, "ingress", "controller", []*model.SyncEvent{updated, failed}, map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultFailed, Message: "failed"},
+	}).Once().Return(errors.New("forbidden"))
+
+	err := processor.PublishSyncEvents([]*model.SyncEvent{created, updated, inSync, failed})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestPublishSyncEvents_policyWarningsDontFail() {
+	original := suite.helper.SetTimeNow(func() time.Time {
+		return time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	})
+	defer suite.helper.SetTimeNow(original)
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	kept := &model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "controller", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonChangedOutOfBand, Message: "kept"}
+
+	client.On("PublishSyncStatus", model.KindDaemonSet, "ingress", "controller", []*model.SyncEvent{kept}, map[string]*model.SyncStatus{
+		"lb01": &model.SyncStatus{LastSync: "2018-05-01T10:00:00Z", Result: model.SyncResultSynced},
+	}).Once().Return(nil)
+
+	err := processor.PublishSyncEvents([]*model.SyncEvent{kept})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestGetTrafficShiftStates() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	configMap := &model.ConfigMap{
+		Name:      "a10bridge-traffic-shifts",
+		Namespace: "ingress",
+		Data: map[string]string{
+			"migrate": `{"status": "running", "step": 2, "stepStarted": "2018-01-01T10:00:00Z", "downMembers": {"lb": 1}}`,
+			"broken":  `{"status": `,
+		},
+	}
+
+	client.On("GetConfigMap", "ingress", "a10bridge-traffic-shifts").Once().Return(configMap, nil)
+	states, err := processor.GetTrafficShiftStates("ingress", "a10bridge-traffic-shifts")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]*model.TrafficShiftState{
+		"migrate": &model.TrafficShiftState{
+			Status:      model.ShiftStatusRunning,
+			Step:        2,
+			StepStarted: "2018-01-01T10:00:00Z",
+			DownMembers: map[string]int{"lb": 1},
+		},
+	}, states)
+}
+
+func (suite *K8sProcessorTestSuite) TestGetTrafficShiftStates_configMapMissing() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+
+	client.On("GetConfigMap", "ingress", "a10bridge-traffic-shifts").Once().Return(nil, nil)
+	states, err := processor.GetTrafficShiftStates("ingress", "a10bridge-traffic-shifts")
+	suite.Assert().Nil(err)
+	suite.Assert().Empty(states)
+}
+
+func (suite *K8sProcessorTestSuite) TestSaveTrafficShiftStates() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	states := map[string]*model.TrafficShiftState{
+		"migrate": &model.TrafficShiftState{Status: model.ShiftStatusCompleted, Step: 3},
+	}
+
+	client.On("SaveConfigMap", &model.ConfigMap{
+		Name:      "a10bridge-traffic-shifts",
+		Namespace: "ingress",
+		Data:      map[string]string{"migrate": util.ToJSON(states["migrate"])},
+	}).Once().Return(errors.New("fail"))
+	err := processor.SaveTrafficShiftStates("ingress", "a10bridge-traffic-shifts", states)
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *K8sProcessorTestSuite) TestGetAppliedStates() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	applied := model.NewAppliedState()
+	applied.HealthChecks["health"] = &model.HealthCheck{Name: "health", Endpoint: "/healthz"}
+	configMap := &model.ConfigMap{
+		Name:      "a10bridge-applied-state",
+		Namespace: "ingress",
+		Data: map[string]string{
+			"lb01": util.ToJSON(applied),
+			"lb02": `{"servers": `,
+		},
+	}
+
+	client.On("GetConfigMap", "ingress", "a10bridge-applied-state").Once().Return(configMap, nil)
+	states, err := processor.GetAppliedStates("ingress", "a10bridge-applied-state")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(map[string]*model.AppliedState{"lb01": applied}, states)
+}
+
+func (suite *K8sProcessorTestSuite) TestSaveAppliedStates() {
+	client := suite.client
+	processor := suite.helper.BuildK8sProcessor(client)
+	states := map[string]*model.AppliedState{"lb01": model.NewAppliedState()}
+
+	client.On("SaveConfigMap", &model.ConfigMap{
+		Name:      "a10bridge-applied-state",
+		Namespace: "ingress",
+		Data:      map[string]string{"lb01": util.ToJSON(states["lb01"])},
+	}).Once().Return(nil)
+	err := processor.SaveAppliedStates("ingress", "a10bridge-applied-state", states)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
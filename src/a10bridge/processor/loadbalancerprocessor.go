@@ -0,0 +1,188 @@
+package processor
+
+import (
+	"a10bridge/a10/api"
+	"a10bridge/model"
+	"a10bridge/util"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+//LoadBalancerProcessor processor responsible for processing kubernetes services of type LoadBalancer
+type LoadBalancerProcessor interface {
+	ProcessLoadBalancer(loadBalancer *model.LoadBalancer, failedServiceGroupNames []string) error
+	DeleteStaleLoadBalancers(loadBalancers []*model.LoadBalancer, namePrefix string) error
+}
+
+type loadBalancerProcessorImpl struct {
+	a10Client api.Client
+}
+
+func (processor loadBalancerProcessorImpl) ProcessLoadBalancer(loadBalancer *model.LoadBalancer, failedServiceGroupNames []string) error {
+	glog.Infof("Processing load balancer %s/%s", loadBalancer.Namespace, loadBalancer.Name)
+
+	if len(loadBalancer.VIP) == 0 {
+		return fmt.Errorf("There is no vip assigned to load balancer %s/%s", loadBalancer.Namespace, loadBalancer.Name)
+	}
+
+	a10VirtualServer, a10err := processor.a10Client.GetVirtualServer(loadBalancer.VirtualServerName)
+	if a10err != nil {
+		if !processor.a10Client.IsVirtualServerNotFound(a10err) {
+			return a10err
+		}
+		virtualServer := buildVirtualServer(loadBalancer, failedServiceGroupNames, nil)
+		if len(virtualServer.Ports) == 0 {
+			return fmt.Errorf("There were no ports found for load balancer %s/%s", loadBalancer.Namespace, loadBalancer.Name)
+		}
+		return processor.a10Client.CreateVirtualServer(virtualServer)
+	}
+
+	virtualServer := buildVirtualServer(loadBalancer, failedServiceGroupNames, a10VirtualServer)
+	if len(virtualServer.Ports) == 0 {
+		return fmt.Errorf("There were no ports found for load balancer %s/%s", loadBalancer.Namespace, loadBalancer.Name)
+	}
+
+	if sameVirtualServers(virtualServer, a10VirtualServer) {
+		glog.Infof("A10 virtual server %s is in sync with kubernetes", virtualServer.Name)
+		return nil
+	}
+
+	glog.Infof("Virtual server %s in a10 differs from configuration in kubernetes, updating virtual server in a10", virtualServer.Name)
+	a10err = processor.a10Client.UpdateVirtualServer(virtualServer)
+	if a10err != nil {
+		return a10err
+	}
+
+	//only service groups of ports removed from the service are deleted, groups which failed to sync keep their ports
+	var err error
+	for _, port := range a10VirtualServer.Ports {
+		if containsVirtualPort(virtualServer.Ports, port) || servesServiceGroup(loadBalancer, port.ServiceGroupName) ||
+			!strings.HasPrefix(port.ServiceGroupName, virtualServer.Name) {
+			continue
+		}
+		a10err = processor.a10Client.DeleteServiceGroup(port.ServiceGroupName)
+		if a10err != nil && !processor.a10Client.IsServiceGroupNotFound(a10err) {
+			glog.Errorf("Failed to delete service group %s. error: %s", port.ServiceGroupName, a10err)
+			err = a10err
+		}
+	}
+
+	return err
+}
+
+func (processor loadBalancerProcessorImpl) DeleteStaleLoadBalancers(loadBalancers []*model.LoadBalancer, namePrefix string) error {
+	a10VirtualServers, a10err := processor.a10Client.GetVirtualServers()
+	if a10err != nil {
+		return a10err
+	}
+
+	expected := make([]string, len(loadBalancers))
+	for idx, loadBalancer := range loadBalancers {
+		expected[idx] = loadBalancer.VirtualServerName
+	}
+
+	var err error
+	for _, virtualServer := range a10VirtualServers {
+		if !strings.HasPrefix(virtualServer.Name, namePrefix) || util.Contains(expected, virtualServer.Name) {
+			continue
+		}
+		if !virtualServer.CreatedByA10bridge() {
+			glog.Infof("Keeping virtual server %s, it was not created by a10bridge", virtualServer.Name)
+			continue
+		}
+
+		glog.Infof("Virtual server %s doesn't belong to any kubernetes service anymore, removing it", virtualServer.Name)
+		a10err = processor.a10Client.DeleteVirtualServer(virtualServer.Name)
+		if a10err != nil {
+			glog.Errorf("Failed to delete virtual server %s. error: %s", virtualServer.Name, a10err)
+			err = a10err
+			continue
+		}
+
+		for _, port := range virtualServer.Ports {
+			if !strings.HasPrefix(port.ServiceGroupName, namePrefix) {
+				continue
+			}
+			a10err = processor.a10Client.DeleteServiceGroup(port.ServiceGroupName)
+			if a10err != nil && !processor.a10Client.IsServiceGroupNotFound(a10err) {
+				glog.Errorf("Failed to delete service group %s. error: %s", port.ServiceGroupName, a10err)
+				err = a10err
+			}
+		}
+	}
+
+	return err
+}
+
+//buildVirtualServer builds virtual server serving ports of the load balancer. Ports of service groups which failed to sync
+//are left as they are in a10, they are not added until their service groups sync
+func buildVirtualServer(loadBalancer *model.LoadBalancer, failedServiceGroupNames []string, a10VirtualServer *model.VirtualServer) *model.VirtualServer {
+	virtualServer := &model.VirtualServer{
+		Name:        loadBalancer.VirtualServerName,
+		IPAddress:   loadBalancer.VIP,
+		Description: model.VirtualServerDescription(loadBalancer.Namespace, loadBalancer.Name),
+		Ports:       make([]*model.VirtualPort, 0),
+	}
+
+	for _, port := range loadBalancer.Ports {
+		if len(port.ServiceGroupName) == 0 {
+			continue
+		}
+		virtualPort := &model.VirtualPort{
+			Port:             port.Port,
+			Protocol:         port.Protocol,
+			ServiceGroupName: port.ServiceGroupName,
+		}
+		if util.Contains(failedServiceGroupNames, port.ServiceGroupName) {
+			if a10VirtualServer == nil || !containsVirtualPort(a10VirtualServer.Ports, virtualPort) {
+				continue
+			}
+			glog.Warningf("Keeping virtual port %d/%s of virtual server %s, service group %s failed to sync", port.Port, port.Protocol, virtualServer.Name, port.ServiceGroupName)
+		}
+		virtualServer.Ports = append(virtualServer.Ports, virtualPort)
+	}
+
+	return virtualServer
+}
+
+//servesServiceGroup checks any port of the load balancer is served by the service group
+func servesServiceGroup(loadBalancer *model.LoadBalancer, serviceGroupName string) bool {
+	for _, port := range loadBalancer.Ports {
+		if port.ServiceGroupName == serviceGroupName {
+			return true
+		}
+	}
+	return false
+}
+
+func sameVirtualServers(virtualServer *model.VirtualServer, a10VirtualServer *model.VirtualServer) bool {
+	if virtualServer.IPAddress != a10VirtualServer.IPAddress {
+		glog.Infof("Virtual server addresses '%s' and '%s' don't match", virtualServer.IPAddress, a10VirtualServer.IPAddress)
+		return false
+	}
+	if virtualServer.Description != a10VirtualServer.Description {
+		glog.Infof("Virtual server descriptions '%s' and '%s' don't match", virtualServer.Description, a10VirtualServer.Description)
+		return false
+	}
+	if len(virtualServer.Ports) != len(a10VirtualServer.Ports) {
+		return false
+	}
+	for _, port := range virtualServer.Ports {
+		if !containsVirtualPort(a10VirtualServer.Ports, port) {
+			glog.Infof("Virtual port %d/%s is missing", port.Port, port.Protocol)
+			return false
+		}
+	}
+	return true
+}
+
+func containsVirtualPort(ports []*model.VirtualPort, lookFor *model.VirtualPort) bool {
+	for _, item := range ports {
+		if item.Port == lookFor.Port && item.Protocol == lookFor.Protocol && item.ServiceGroupName == lookFor.ServiceGroupName {
+			return true
+		}
+	}
+	return false
+}
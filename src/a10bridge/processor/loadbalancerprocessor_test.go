@@ -0,0 +1,266 @@
+package processor_test
+
+import (
+	"a10bridge/mocks"
+	"a10bridge/model"
+	"a10bridge/processor"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadBalancerProcessorTestSuite struct {
+	suite.Suite
+	helper *processor.TestHelper
+	client *mocks.Client
+}
+
+func (suite *LoadBalancerProcessorTestSuite) SetupTest() {
+	suite.client = new(mocks.Client)
+}
+
+func TestLoadBalancerProcessor(t *testing.T) {
+	tests := new(LoadBalancerProcessorTestSuite)
+	tests.helper = new(processor.TestHelper)
+	suite.Run(t, tests)
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_noVIP() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+	loadBalancer.VIP = ""
+
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_noPorts() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(nil, a10error)
+	client.On("IsVirtualServerNotFound", a10error).Once().Return(true)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{"k8s-default-web-80"})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_notFound() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(nil, a10error)
+	client.On("IsVirtualServerNotFound", a10error).Once().Return(true)
+	client.On("CreateVirtualServer", virtualServer()).Once().Return(nil)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_getVirtualServerFails() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(nil, a10error)
+	client.On("IsVirtualServerNotFound", a10error).Once().Return(false)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_notChanged() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(virtualServer(), nil)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_changed() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+	a10VirtualServer := virtualServer()
+	a10VirtualServer.IPAddress = "10.0.0.2"
+	a10VirtualServer.Ports = append(a10VirtualServer.Ports, &model.VirtualPort{
+		Port:             443,
+		Protocol:         "tcp",
+		ServiceGroupName: "k8s-default-web-443",
+	})
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(a10VirtualServer, nil)
+	client.On("UpdateVirtualServer", virtualServer()).Once().Return(nil)
+	client.On("DeleteServiceGroup", "k8s-default-web-443").Once().Return(nil)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_updateFails() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+	a10VirtualServer := virtualServer()
+	a10VirtualServer.IPAddress = "10.0.0.2"
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(a10VirtualServer, nil)
+	client.On("UpdateVirtualServer", virtualServer()).Once().Return(a10error)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_failedServiceGroupKeepsPort() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+	loadBalancer.Ports = append(loadBalancer.Ports, &model.LoadBalancerPort{
+		Name:             "https",
+		Protocol:         "tcp",
+		Port:             443,
+		NodePort:         30443,
+		ServiceGroupName: "k8s-default-web-443",
+	})
+	a10VirtualServer := virtualServer()
+	a10VirtualServer.Description = "changed"
+	a10VirtualServer.Ports = append(a10VirtualServer.Ports, &model.VirtualPort{
+		Port:             443,
+		Protocol:         "tcp",
+		ServiceGroupName: "k8s-default-web-443",
+	})
+	expected := virtualServer()
+	expected.Ports = append(expected.Ports, &model.VirtualPort{
+		Port:             443,
+		Protocol:         "tcp",
+		ServiceGroupName: "k8s-default-web-443",
+	})
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(a10VirtualServer, nil)
+	client.On("UpdateVirtualServer", expected).Once().Return(nil)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{"k8s-default-web-443"})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "DeleteServiceGroup", "k8s-default-web-443")
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestProcessLoadBalancer_failedServiceGroupIsNotAdded() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	loadBalancer := loadBalancer()
+	loadBalancer.Ports = append(loadBalancer.Ports, &model.LoadBalancerPort{
+		Name:             "https",
+		Protocol:         "tcp",
+		Port:             443,
+		NodePort:         30443,
+		ServiceGroupName: "k8s-default-web-443",
+	})
+
+	client.On("GetVirtualServer", "k8s-default-web").Once().Return(virtualServer(), nil)
+	err := processor.ProcessLoadBalancer(loadBalancer, []string{"k8s-default-web-443"})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestDeleteStaleLoadBalancers() {
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	stale := &model.VirtualServer{
+		Name:        "k8s-default-old",
+		IPAddress:   "10.0.0.3",
+		Description: "a10bridge k8s service default/old",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{Port: 80, Protocol: "tcp", ServiceGroupName: "k8s-default-old-80"},
+		},
+	}
+	notManaged := &model.VirtualServer{Name: "manual", IPAddress: "10.0.0.4"}
+	manualWithPrefix := &model.VirtualServer{
+		Name:      "k8s-manual",
+		IPAddress: "10.0.0.5",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{Port: 80, Protocol: "tcp", ServiceGroupName: "k8s-manual-80"},
+		},
+	}
+
+	client.On("GetVirtualServers").Once().Return([]*model.VirtualServer{virtualServer(), stale, notManaged, manualWithPrefix}, nil)
+	client.On("DeleteVirtualServer", "k8s-default-old").Once().Return(nil)
+	client.On("DeleteServiceGroup", "k8s-default-old-80").Once().Return(nil)
+	err := processor.DeleteStaleLoadBalancers([]*model.LoadBalancer{loadBalancer()}, "k8s-")
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNumberOfCalls(suite.T(), "DeleteVirtualServer", 1)
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestDeleteStaleLoadBalancers_deleteFails() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+	stale := &model.VirtualServer{
+		Name:        "k8s-default-old",
+		Description: "a10bridge k8s service default/old",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{Port: 80, Protocol: "tcp", ServiceGroupName: "k8s-default-old-80"},
+		},
+	}
+
+	client.On("GetVirtualServers").Once().Return([]*model.VirtualServer{stale}, nil)
+	client.On("DeleteVirtualServer", "k8s-default-old").Once().Return(a10error)
+	err := processor.DeleteStaleLoadBalancers([]*model.LoadBalancer{}, "k8s-")
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *LoadBalancerProcessorTestSuite) TestDeleteStaleLoadBalancers_getVirtualServersFails() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildLoadBalancerProcessor(client)
+
+	client.On("GetVirtualServers").Once().Return(nil, a10error)
+	err := processor.DeleteStaleLoadBalancers([]*model.LoadBalancer{}, "k8s-")
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func loadBalancer() *model.LoadBalancer {
+	return &model.LoadBalancer{
+		Name:              "web",
+		Namespace:         "default",
+		VIP:               "10.0.0.1",
+		VirtualServerName: "k8s-default-web",
+		Ports: []*model.LoadBalancerPort{
+			&model.LoadBalancerPort{
+				Name:             "http",
+				Protocol:         "tcp",
+				Port:             80,
+				NodePort:         30080,
+				ServiceGroupName: "k8s-default-web-80",
+			},
+		},
+	}
+}
+
+func virtualServer() *model.VirtualServer {
+	return &model.VirtualServer{
+		Name:        "k8s-default-web",
+		IPAddress:   "10.0.0.1",
+		Description: "a10bridge k8s service default/web",
+		Ports: []*model.VirtualPort{
+			&model.VirtualPort{
+				Port:             80,
+				Protocol:         "tcp",
+				ServiceGroupName: "k8s-default-web-80",
+			},
+		},
+	}
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"a10bridge/a10/api"
+	"a10bridge/config"
 	"a10bridge/model"
 	"a10bridge/util"
 	"fmt"
@@ -12,56 +13,172 @@ import (
 //NodeProcessor processor responsible for processing nodes
 type NodeProcessor interface {
 	ProcessNode(node *model.Node) error
+	RevertServer(serverName string, previous *model.Node) error
 }
 
 type nodeProcessorImpl struct {
-	a10Client api.Client
+	a10Client     api.Client
+	addressFamily string
+	apiVersion    int
+	recorder      *SyncRecorder
+	applied       *AppliedStateTracker
+	canary        *CanaryRollout
 }
 
-func (processor nodeProcessorImpl) ProcessNode(node *model.Node) error {
-	glog.Infof("Processing node %s", util.ToJSON(node))
+func (processor nodeProcessorImpl) ProcessNode(k8sNode *model.Node) error {
+	glog.Infof("Processing node %s", util.ToJSON(k8sNode))
+
+	reason, changes, err := processor.processNode(k8sNode)
+	if err != nil {
+		processor.recorder.nodeFailed(k8sNode, err)
+		return err
+	}
+
+	processor.recorder.recordNode(k8sNode, reason, describeChanges(fmt.Sprintf("server %s %s", k8sNode.A10Server, describeReason(reason)), changes))
+	return nil
+}
+
+//processNode syncs the a10 server with the node, returns what happened to the server with the changed fields
+func (processor nodeProcessorImpl) processNode(k8sNode *model.Node) (string, model.FieldChanges, error) {
+	node, err := selectAddresses(k8sNode, processor.addressFamily)
+	if err != nil {
+		return "", nil, err
+	}
+	if processor.apiVersion == 2 {
+		//v2 api doesn't keep server descriptions
+		node.Description = ""
+	}
+	err = util.ValidateA10Name(node.A10Server)
+	if err != nil {
+		return "", nil, err
+	}
 
 	server, a10err := processor.a10Client.GetServer(node.A10Server)
 	if a10err != nil {
 		//server not found
-		if processor.a10Client.IsServerNotFound(a10err) {
-			a10err = processor.a10Client.CreateServer(node)
-			if a10err != nil {
-				return a10err
+		if !processor.a10Client.IsServerNotFound(a10err) {
+			return "", nil, a10err
+		}
+		if processor.applied.server(node.A10Server) != nil {
+			glog.Warningf("Server %s was removed from a10 outside of a10bridge, creating it again", node.A10Server)
+		}
+		a10err = processor.a10Client.CreateServer(node)
+		if a10err != nil {
+			return "", nil, a10err
+		}
+		processor.canary.ServerChanged(node.A10Server, nil)
+		processor.applied.serverApplied(node)
+		return model.ReasonCreated, nil, nil
+	}
+
+	fmt.Println(util.ToJSON(server))
+
+	changes := diffServer(server, node)
+	if len(changes) == 0 {
+		glog.Info("Server and node configurations are in sync")
+		processor.applied.serverApplied(node)
+		return model.ReasonInSync, nil, nil
+	}
+
+	lastApplied := processor.applied.server(node.A10Server)
+	if lastApplied != nil {
+		outOfBand := diffServer(server, lastApplied).Reversed()
+		if len(outOfBand) > 0 {
+			glog.Warningf("Server %s was changed outside of a10bridge: %s", node.A10Server, outOfBand)
+			if processor.applied.keeps(len(diffServer(lastApplied, node)) == 0) {
+				return model.ReasonChangedOutOfBand, outOfBand, nil
 			}
-		} else {
+			processor.recorder.recordNode(k8sNode, model.ReasonChangedOutOfBand, describeChanges(fmt.Sprintf("server %s was changed outside of a10bridge, resetting it", node.A10Server), outOfBand))
+		}
+	}
+
+	glog.Infof("Server and node configurations differ: %s", changes)
+	previous := *server
+	server.IPAddress = node.IPAddress
+	server.IPv6Address = node.IPv6Address
+	//servers keep their weight while the node's weight can't be computed
+	if len(node.Weight) > 0 {
+		server.Weight = node.Weight
+	}
+	//attributes not managed for the node are left out of the update so a10 keeps its values
+	server.ConnLimit = node.ConnLimit
+	server.SlowStart = node.SlowStart
+	server.Description = node.Description
+	server.Template = node.Template
+	server.State = node.State
+	a10err = processor.writeServer(&previous, server)
+	if a10err != nil {
+		return "", nil, a10err
+	}
+	glog.Info("Server configuration synced with node configuration")
+	processor.canary.ServerChanged(node.A10Server, &previous)
+	processor.applied.serverApplied(node)
+	return model.ReasonUpdated, changes, nil
+}
+
+//RevertServer brings the server back to the state it had in a10 before it was changed, servers which didn't exist are deleted
+func (processor nodeProcessorImpl) RevertServer(serverName string, previous *model.Node) error {
+	if previous == nil {
+		glog.Infof("Reverting server %s by deleting it", serverName)
+		a10err := processor.a10Client.DeleteServer(serverName)
+		if a10err != nil {
 			return a10err
 		}
 	} else {
-		fmt.Println(util.ToJSON(server))
-
-		if !isSame(node, server) {
-			glog.Infof("Server and node configurations differ, setting the server to ip %s and weight %s", node.IPAddress, node.Weight)
-			server.IPAddress = node.IPAddress
-			server.Weight = node.Weight
-			a10err = processor.a10Client.UpdateServer(server)
-			if a10err != nil {
-				return a10err
-			}
-			glog.Info("Server configuration synced with node configuration")
-		} else {
-			glog.Info("Server and node configurations are in sync")
+		glog.Infof("Reverting server %s", serverName)
+		current, a10err := processor.a10Client.GetServer(serverName)
+		if a10err == nil {
+			a10err = processor.writeServer(current, previous)
+		} else if processor.a10Client.IsServerNotFound(a10err) {
+			a10err = processor.a10Client.CreateServer(previous)
+		}
+		if a10err != nil {
+			return a10err
 		}
 	}
-
-	return a10err
+	processor.applied.serverReverted(serverName)
+	return nil
 }
 
-func isSame(node *model.Node, server *model.Node) bool {
-	if node.IPAddress != server.IPAddress {
-		glog.Infof("Server ip addresses '%s' and '%s' don't match", server.IPAddress, node.IPAddress)
-		return false
+//writeServer updates the a10 server, a10 doesn't clear addresses left out of updates so servers which stop
+//using an address family are deleted and created again, their service group members are added back by service group sync
+func (processor nodeProcessorImpl) writeServer(current *model.Node, desired *model.Node) api.A10Error {
+	if !dropsAddressFamily(current, desired) {
+		return processor.a10Client.UpdateServer(desired)
+	}
+	glog.Warningf("Server %s stops using an address family, creating it again", desired.A10Server)
+	a10err := processor.a10Client.DeleteServer(desired.A10Server)
+	if a10err != nil && !processor.a10Client.IsServerNotFound(a10err) {
+		return a10err
 	}
+	return processor.a10Client.CreateServer(desired)
+}
+
+//dropsAddressFamily checks the desired server lacks an address the a10 server has
+func dropsAddressFamily(current *model.Node, desired *model.Node) bool {
+	return (len(current.IPAddress) > 0 && len(desired.IPAddress) == 0) || (len(current.IPv6Address) > 0 && len(desired.IPv6Address) == 0)
+}
 
-	if node.Weight != server.Weight {
-		glog.Infof("Server weights '%s' and '%s' don't match", server.IPAddress, node.IPAddress)
-		return false
+//selectAddresses builds a copy of the node carrying only the addresses of the address family used by the a10 instance
+func selectAddresses(node *model.Node, addressFamily string) (*model.Node, error) {
+	selected := *node
+
+	switch addressFamily {
+	case config.AddressFamilyIPv6:
+		selected.IPAddress = ""
+		if len(selected.IPv6Address) == 0 {
+			return nil, fmt.Errorf("Node %s doesn't have ipv6 address", node.Name)
+		}
+	case config.AddressFamilyDualStack:
+		if len(selected.IPAddress) == 0 && len(selected.IPv6Address) == 0 {
+			return nil, fmt.Errorf("Node %s doesn't have any ip address", node.Name)
+		}
+	default:
+		selected.IPv6Address = ""
+		if len(selected.IPAddress) == 0 {
+			return nil, fmt.Errorf("Node %s doesn't have ipv4 address", node.Name)
+		}
 	}
 
-	return true
+	return &selected, nil
 }
@@ -1,11 +1,13 @@
 package processor_test
 
 import (
+	"a10bridge/config"
 	"a10bridge/mocks"
 	"a10bridge/model"
 	"a10bridge/processor"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -105,6 +107,119 @@ func (suite *NodeProcessorTestSuite) TestProcessNode_weightChanged() {
 	client.AssertExpectations(suite.T())
 }
 
+func (suite *NodeProcessorTestSuite) TestProcessNode_unsetWeightKeepsServerWeight() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.Weight = ""
+	existing := *node
+	existing.Weight = "10"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "UpdateServer", mock.Anything)
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_unsetWeightIsNotUpdated() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.Weight = ""
+	existing := *node
+	existing.Weight = "10"
+	existing.IPAddress = "blah"
+	updated := *node
+	updated.Weight = "10"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", &updated).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_weightWithinThreshold() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.Weight = "12"
+	node.WeightThreshold = 3
+	existing := *node
+	existing.Weight = "10"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_weightOverThreshold() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.Weight = "13"
+	node.WeightThreshold = 3
+	existing := *node
+	existing.Weight = "10"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_attributesChanged() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.ConnLimit = 1000
+	node.SlowStart = "true"
+	node.State = model.ServerStateDisable
+	existing := *node
+	existing.ConnLimit = 8000000
+	existing.Template = "default"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_attributesNotManaged() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	existing := *node
+	existing.ConnLimit = 8000000
+	existing.SlowStart = "false"
+	existing.Template = "default"
+	existing.Description = "manual"
+	existing.State = model.ServerStateEnable
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_v2IgnoresDescription() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAPIVersion(client, 2)
+	node := node()
+	node.Description = "k8s dc-kube node node"
+	existing := *node
+	existing.Description = ""
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
 func (suite *NodeProcessorTestSuite) TestProcessNode_updateFails() {
 	a10error := new(mocks.A10Error)
 	client := suite.client
@@ -120,6 +235,135 @@ func (suite *NodeProcessorTestSuite) TestProcessNode_updateFails() {
 	client.AssertExpectations(suite.T())
 }
 
+func (suite *NodeProcessorTestSuite) TestProcessNode_ipv6ServerNotFound() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyIPv6)
+	node := node()
+	node.IPv6Address = "fd00::10"
+	expected := *node
+	expected.IPAddress = ""
+
+	client.On("GetServer", node.A10Server).Once().Return(nil, a10error)
+	client.On("IsServerNotFound", a10error).Once().Return(true)
+	client.On("CreateServer", &expected).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_ipv6Missing() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyIPv6)
+	node := node()
+
+	err := processor.ProcessNode(node)
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_invalidServerName() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessor(client)
+	node := node()
+	node.A10Server = "worker 1/k8s"
+
+	err := processor.ProcessNode(node)
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_ipv4Missing() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyIPv4)
+	node := node()
+	node.IPAddress = ""
+	node.IPv6Address = "fd00::10"
+
+	err := processor.ProcessNode(node)
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_ipv4IgnoresIPv6Address() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyIPv4)
+	node := node()
+	node.IPv6Address = "fd00::10"
+	existing := *node
+	existing.IPv6Address = ""
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_dualStackIPv6Changed() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyDualStack)
+	node := node()
+	node.IPv6Address = "fd00::10"
+	existing := *node
+	existing.IPv6Address = "fd00::11"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_addressFamilySwitchRecreatesServer() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyIPv6)
+	node := node()
+	node.IPv6Address = "fd00::10"
+	existing := *node
+	existing.IPv6Address = ""
+	expected := *node
+	expected.IPAddress = ""
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("DeleteServer", node.A10Server).Once().Return(nil)
+	client.On("CreateServer", &expected).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "UpdateServer", mock.Anything)
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_dualStackDroppingIPv6RecreatesServer() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorForAddressFamily(client, config.AddressFamilyDualStack)
+	node := node()
+	existing := *node
+	existing.IPv6Address = "fd00::10"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("DeleteServer", node.A10Server).Once().Return(nil)
+	client.On("CreateServer", node).Once().Return(nil)
+	err := processor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestRevertServer_recreatesServerOfPreviousAddressFamily() {
+	client := suite.client
+	processor := suite.helper.BuildNodeProcessorWithCanary(client, nil, nil)
+	current := node()
+	current.IPAddress = ""
+	current.IPv6Address = "fd00::10"
+	previous := node()
+
+	client.On("GetServer", previous.A10Server).Once().Return(current, nil)
+	client.On("DeleteServer", previous.A10Server).Once().Return(nil)
+	client.On("CreateServer", previous).Once().Return(nil)
+	err := processor.RevertServer(previous.A10Server, previous)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
 func node() *model.Node {
 	return &model.Node{
 		A10Server: "a10server",
@@ -131,3 +375,183 @@ func node() *model.Node {
 		Weight: "1",
 	}
 }
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_recordsEvents() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	nodeProcessor := suite.helper.BuildNodeProcessorWithRecorder(client, recorder)
+	created := node()
+	unchanged := node()
+	unchanged.Name = "unchanged"
+	unchanged.A10Server = "unchanged"
+	failing := node()
+	failing.Name = "failing"
+	failing.A10Server = "failing"
+
+	client.On("GetServer", created.A10Server).Once().Return(nil, a10error)
+	client.On("IsServerNotFound", a10error).Once().Return(true)
+	client.On("CreateServer", created).Once().Return(nil)
+	client.On("GetServer", unchanged.A10Server).Once().Return(unchanged, nil)
+	client.On("GetServer", failing.A10Server).Once().Return(nil, a10error)
+	client.On("IsServerNotFound", a10error).Once().Return(false)
+	a10error.On("Error").Return("connection refused")
+
+	suite.Assert().Nil(nodeProcessor.ProcessNode(created))
+	suite.Assert().Nil(nodeProcessor.ProcessNode(unchanged))
+	suite.Assert().NotNil(nodeProcessor.ProcessNode(failing))
+
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindNode, Name: "server", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonCreated, Message: "a10 lb01: server a10server created"},
+		&model.SyncEvent{Kind: model.KindNode, Name: "unchanged", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonInSync, Message: "a10 lb01: server unchanged is in sync"},
+		&model.SyncEvent{Kind: model.KindNode, Name: "failing", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonSyncFailed, Message: "a10 lb01: connection refused"},
+	}, recorder.Events())
+	client.AssertExpectations(suite.T())
+}
+
+//appliedNode state of node() applied by the previous run
+func appliedNode() *model.Node {
+	return &model.Node{A10Server: "a10server", IPAddress: "10.10.10.10", Weight: "1"}
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_changedOutOfBandIsReset() {
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.Servers["a10server"] = appliedNode()
+	applied.Start(previous, false)
+	nodeProcessor := suite.helper.BuildNodeProcessorWithAppliedState(client, recorder, applied)
+	node := node()
+	existing := *node
+	existing.Weight = "50"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	err := nodeProcessor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	suite.Require().Len(recorder.Events(), 2)
+	suite.Assert().Equal(model.ReasonChangedOutOfBand, recorder.Events()[0].Reason)
+	suite.Assert().Equal("a10 lb01: server a10server was changed outside of a10bridge, resetting it: weight '1' -> '50'", recorder.Events()[0].Message)
+	suite.Assert().Equal(model.ReasonUpdated, recorder.Events()[1].Reason)
+	suite.Assert().Equal("a10 lb01: server a10server updated: weight '50' -> '1'", recorder.Events()[1].Message)
+	suite.Assert().Equal(appliedNode(), applied.State().Servers["a10server"])
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_changedOutOfBandIsKept() {
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.Servers["a10server"] = appliedNode()
+	applied.Start(previous, true)
+	nodeProcessor := suite.helper.BuildNodeProcessorWithAppliedState(client, recorder, applied)
+	node := node()
+	existing := *node
+	existing.Weight = "50"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	err := nodeProcessor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindNode, Name: "server", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonChangedOutOfBand,
+			Message: "a10 lb01: server a10server was changed outside of a10bridge and is left alone: weight '1' -> '50'"},
+	}, recorder.Events())
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_desiredChangeWinsOverOutOfBand() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	previous := model.NewAppliedState()
+	previous.Servers["a10server"] = appliedNode()
+	applied.Start(previous, true)
+	nodeProcessor := suite.helper.BuildNodeProcessorWithAppliedState(client, nil, applied)
+	node := node()
+	node.Weight = "10"
+	existing := *node
+	existing.Weight = "50"
+
+	client.On("GetServer", node.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	err := nodeProcessor.ProcessNode(node)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("10", applied.State().Servers["a10server"].Weight)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestProcessNode_canaryKeepsChangedServers() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	canary := processor.NewCanaryRollout()
+	canary.Start()
+	processor := suite.helper.BuildNodeProcessorWithCanary(client, nil, canary)
+	created := node()
+	created.A10Server = "created"
+	inSync := node()
+	inSync.A10Server = "in-sync"
+	changed := node()
+	existing := *changed
+	existing.IPAddress = "10.10.10.11"
+	previous := existing
+
+	client.On("GetServer", created.A10Server).Once().Return(nil, a10error)
+	client.On("IsServerNotFound", a10error).Once().Return(true)
+	client.On("CreateServer", created).Once().Return(nil)
+	client.On("GetServer", inSync.A10Server).Once().Return(inSync, nil)
+	client.On("GetServer", changed.A10Server).Once().Return(&existing, nil)
+	client.On("UpdateServer", changed).Once().Return(nil)
+	suite.Assert().Nil(processor.ProcessNode(created))
+	suite.Assert().Nil(processor.ProcessNode(inSync))
+	suite.Assert().Nil(processor.ProcessNode(changed))
+
+	suite.Assert().Equal([]string{"a10server", "created"}, canary.Servers())
+	suite.Assert().Nil(canary.PreviousServer("created"))
+	suite.Assert().Equal(&previous, canary.PreviousServer("a10server"))
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestRevertServer() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	previousState := model.NewAppliedState()
+	appliedServer := &model.Node{A10Server: "a10server", IPAddress: "10.10.10.11"}
+	previousState.Servers["a10server"] = appliedServer
+	applied.Start(previousState, false)
+	processor := suite.helper.BuildNodeProcessorWithCanary(client, applied, nil)
+	node := node()
+	existing := *node
+	existing.IPAddress = "10.10.10.11"
+	previous := existing
+
+	client.On("GetServer", node.A10Server).Twice().Return(&existing, nil)
+	client.On("UpdateServer", node).Once().Return(nil)
+	client.On("UpdateServer", &previous).Once().Return(nil)
+	suite.Assert().Nil(processor.ProcessNode(node))
+	suite.Assert().Equal(node.IPAddress, applied.State().Servers["a10server"].IPAddress)
+	err := processor.RevertServer(node.A10Server, &previous)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(appliedServer, applied.State().Servers["a10server"])
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *NodeProcessorTestSuite) TestRevertServer_deletesCreatedServer() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(model.NewAppliedState(), false)
+	processor := suite.helper.BuildNodeProcessorWithCanary(client, applied, nil)
+	node := node()
+
+	client.On("GetServer", node.A10Server).Once().Return(nil, a10error)
+	client.On("IsServerNotFound", a10error).Once().Return(true)
+	client.On("CreateServer", node).Once().Return(nil)
+	client.On("DeleteServer", node.A10Server).Once().Return(nil)
+	suite.Assert().Nil(processor.ProcessNode(node))
+	suite.Assert().Contains(applied.State().Servers, node.A10Server)
+	err := processor.RevertServer(node.A10Server, nil)
+	suite.Assert().Nil(err)
+	suite.Assert().NotContains(applied.State().Servers, node.A10Server)
+	client.AssertExpectations(suite.T())
+}
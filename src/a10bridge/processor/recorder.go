@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"a10bridge/model"
+	"fmt"
+)
+
+//SyncRecorder collects outcomes of syncing kubernetes objects into a single a10 instance
+type SyncRecorder struct {
+	instance string
+	events   []*model.SyncEvent
+}
+
+//NewSyncRecorder builds recorder for the a10 instance
+func NewSyncRecorder(instance string) *SyncRecorder {
+	return &SyncRecorder{
+		instance: instance,
+		events:   make([]*model.SyncEvent, 0),
+	}
+}
+
+//Events returns recorded events
+func (recorder *SyncRecorder) Events() []*model.SyncEvent {
+	if recorder == nil {
+		return nil
+	}
+	return recorder.events
+}
+
+//ServiceGroupFailed records failure to sync the service group on all its ingress controllers
+func (recorder *SyncRecorder) ServiceGroupFailed(serviceGroup *model.ServiceGroup, err error) {
+	if recorder == nil {
+		return
+	}
+	recorder.recordServiceGroup(serviceGroup, model.ReasonSyncFailed, err.Error())
+}
+
+//MembershipKept records the node failed to sync and its members are left as they are
+func (recorder *SyncRecorder) MembershipKept(node *model.Node) {
+	if recorder == nil {
+		return
+	}
+	recorder.recordNode(node, model.ReasonMembershipKept, fmt.Sprintf("server %s failed to sync, its service group members are kept as they are", node.A10Server))
+}
+
+func (recorder *SyncRecorder) nodeFailed(node *model.Node, err error) {
+	if recorder == nil {
+		return
+	}
+	recorder.recordNode(node, model.ReasonSyncFailed, err.Error())
+}
+
+func (recorder *SyncRecorder) recordNode(node *model.Node, reason, message string) {
+	recorder.record(node.Cluster, model.KindNode, "", node.Name, reason, message)
+}
+
+func (recorder *SyncRecorder) recordServiceGroup(serviceGroup *model.ServiceGroup, reason, message string) {
+	for _, controller := range serviceGroup.IngressControllers {
+		//only ingress controllers read from daemon sets carry namespace
+		if len(controller.Namespace) == 0 {
+			continue
+		}
+		recorder.record(controller.Cluster, model.KindDaemonSet, controller.Namespace, controller.Name, reason, message)
+	}
+}
+
+func (recorder *SyncRecorder) record(cluster, kind, namespace, name, reason, message string) {
+	if recorder == nil {
+		return
+	}
+
+	eventType := model.EventTypeNormal
+	if reason == model.ReasonSyncFailed || reason == model.ReasonChangedOutOfBand || reason == model.ReasonMembershipKept {
+		eventType = model.EventTypeWarning
+	}
+
+	recorder.events = append(recorder.events, &model.SyncEvent{
+		Cluster:   cluster,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Instance:  recorder.instance,
+		Type:      eventType,
+		Reason:    reason,
+		Message:   fmt.Sprintf("a10 %s: %s", recorder.instance, message),
+	})
+}
+
+func describeReason(reason string) string {
+	switch reason {
+	case model.ReasonCreated:
+		return "created"
+	case model.ReasonUpdated:
+		return "updated"
+	case model.ReasonDeleted:
+		return "deleted"
+	case model.ReasonChangedOutOfBand:
+		return "was changed outside of a10bridge and is left alone"
+	}
+	return "is in sync"
+}
+
+//describeOutOfBand describes the a10 object which differs from the state a10bridge last applied to it
+func describeOutOfBand(object string, changes model.FieldChanges, keep bool) string {
+	message := fmt.Sprintf("%s was changed outside of a10bridge, resetting it", object)
+	if keep {
+		message = fmt.Sprintf("%s %s", object, describeReason(model.ReasonChangedOutOfBand))
+	}
+	return describeChanges(message, changes)
+}
+
+//describeChanges appends changed fields to the description
+func describeChanges(description string, changes model.FieldChanges) string {
+	if len(changes) == 0 {
+		return description
+	}
+	return fmt.Sprintf("%s: %s", description, changes)
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"a10bridge/config"
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+//RemovalGuard refuses member removals breaching the removal limits of a single a10 instance, removals from all a10 instances count into the run limit.
+//It doesn't refuse anything until started
+type RemovalGuard struct {
+	limits *config.RemovalLimits
+	//previous members removed from a10 instances processed earlier in the run
+	previous int
+	removed  int
+}
+
+//NewRemovalGuard builds guard which doesn't refuse any removals until started
+func NewRemovalGuard() *RemovalGuard {
+	return &RemovalGuard{}
+}
+
+//Start enforces the limits for the a10 instance after members removed from a10 instances processed earlier in the run, overridden limits are not enforced
+func (guard *RemovalGuard) Start(limits config.RemovalLimits, override bool, removedInRun int) {
+	if guard == nil {
+		return
+	}
+	guard.previous = removedInRun
+	guard.removed = 0
+	if override {
+		glog.Warning("Member removal limits are overridden for this run")
+		guard.limits = nil
+		return
+	}
+	guard.limits = &limits
+}
+
+//Removed members removed from the a10 instance since the guard was started
+func (guard *RemovalGuard) Removed() int {
+	if guard == nil {
+		return 0
+	}
+	return guard.removed
+}
+
+//memberRemoved counts member deleted from the a10 instance into the run
+func (guard *RemovalGuard) memberRemoved() {
+	if guard == nil {
+		return
+	}
+	guard.removed++
+}
+
+//check checks removal of members from the service group keeping the remaining members is within the limits of the service group and the run.
+//Allowed removals count into the run only once the members are deleted
+func (guard *RemovalGuard) check(serviceGroupName string, current, removed, remaining int) error {
+	if guard == nil || guard.limits == nil {
+		return nil
+	}
+	limits := guard.limits.ServiceGroup(serviceGroupName)
+	if limits.MaxRemovedMembers > 0 && removed > limits.MaxRemovedMembers {
+		return fmt.Errorf("removing %d members of service group %s breaches the limit of %d removed members, run with allow-mass-removal if it is intended", removed, serviceGroupName, limits.MaxRemovedMembers)
+	}
+	if limits.MaxRemovedPercent > 0 && current > 0 && removed*100 > limits.MaxRemovedPercent*current {
+		return fmt.Errorf("removing %d of %d members of service group %s breaches the limit of %d%% removed members, run with allow-mass-removal if it is intended", removed, current, serviceGroupName, limits.MaxRemovedPercent)
+	}
+	if limits.MinRemainingMembers > 0 && remaining < limits.MinRemainingMembers {
+		return fmt.Errorf("removing %d members of service group %s leaves %d members, at least %d have to remain, run with allow-mass-removal if it is intended", removed, serviceGroupName, remaining, limits.MinRemainingMembers)
+	}
+	removedInRun := guard.previous + guard.removed
+	maxPerRun := guard.limits.MaxRemovedMembersPerRun
+	if maxPerRun > 0 && removedInRun+removed > maxPerRun {
+		return fmt.Errorf("removing %d members of service group %s after %d members removed in this run breaches the limit of %d removed members per run, run with allow-mass-removal if it is intended", removed, serviceGroupName, removedInRun, maxPerRun)
+	}
+	return nil
+}
@@ -5,23 +5,104 @@ import (
 	"a10bridge/model"
 	"a10bridge/util"
 	"fmt"
+	"strconv"
 
 	"github.com/golang/glog"
 )
 
 //ServiceGroupProcessor processor responsible for processing nodes
 type ServiceGroupProcessor interface {
-	ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedNodeNames []string) error
+	ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedServers []string) error
+	CountDownMembers(serviceGroupName string) (int, error)
+	RevertServiceGroup(serviceGroupName string, previous *model.ServiceGroup) error
 }
 
 type serviceGroupProcessorImpl struct {
 	a10Client api.Client
+	homeZone  string
+	recorder  *SyncRecorder
+	applied   *AppliedStateTracker
+	removals  *RemovalGuard
+	canary    *CanaryRollout
 }
 
-func (processor serviceGroupProcessorImpl) ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedNodeNames []string) error {
+//homeZoneMemberPriority priority of members in the home zone of the a10 instance, members in other zones keep the default and serve as backup
+const homeZoneMemberPriority = model.MemberPriorityDefault + 1
+
+func (processor serviceGroupProcessorImpl) ProcessServiceGroup(serviceGroup *model.ServiceGroup, failedServers []string) error {
 	glog.Infof("Processing service group %s", serviceGroup.Name /* util.ToJSON(serviceGroup) */)
 
-	members := buildMembers(serviceGroup, failedNodeNames)
+	err := processor.processServiceGroup(serviceGroup, failedServers)
+	if err != nil {
+		processor.recorder.ServiceGroupFailed(serviceGroup, err)
+	}
+	return err
+}
+
+//CountDownMembers counts members of the service group the a10 health monitor reports as down
+func (processor serviceGroupProcessorImpl) CountDownMembers(serviceGroupName string) (int, error) {
+	statuses, a10err := processor.a10Client.GetMemberStatuses(serviceGroupName)
+	if a10err != nil {
+		return 0, a10err
+	}
+	down := 0
+	for _, status := range statuses {
+		if !status.Up {
+			down++
+		}
+	}
+	return down, nil
+}
+
+//RevertServiceGroup brings the service group back to the state it had in a10 before it was changed, service groups which didn't exist are deleted
+func (processor serviceGroupProcessorImpl) RevertServiceGroup(serviceGroupName string, previous *model.ServiceGroup) error {
+	if previous == nil {
+		glog.Infof("Reverting service group %s by deleting it", serviceGroupName)
+		a10err := processor.a10Client.DeleteServiceGroup(serviceGroupName)
+		if a10err != nil {
+			return a10err
+		}
+		processor.applied.serviceGroupReverted(serviceGroupName)
+		return nil
+	}
+
+	current, a10err := processor.a10Client.GetServiceGroup(serviceGroupName)
+	if a10err != nil {
+		return a10err
+	}
+	desired := withRemovedSettingsReset(previous, current)
+	changes := diffServiceGroup(current, desired)
+	if len(changes) > 0 {
+		glog.Infof("Reverting service group %s: %s", serviceGroupName, changes)
+		a10err = processor.a10Client.UpdateServiceGroup(desired)
+		if a10err != nil {
+			return a10err
+		}
+	}
+	for _, member := range findExtraMembers(previous.Members, current.Members) {
+		a10err = processor.a10Client.DeleteMember(member)
+		if a10err != nil {
+			return a10err
+		}
+	}
+	for _, member := range findMissingMembers(previous.Members, current.Members) {
+		a10err = processor.a10Client.CreateMember(member)
+		if a10err != nil && !processor.a10Client.IsMemberAlreadyExists(a10err) {
+			return a10err
+		}
+	}
+	for _, member := range findChangedMembers(previous.Members, current.Members) {
+		a10err = processor.a10Client.UpdateMember(member)
+		if a10err != nil {
+			return a10err
+		}
+	}
+	processor.applied.serviceGroupReverted(serviceGroupName)
+	return nil
+}
+
+func (processor serviceGroupProcessorImpl) processServiceGroup(serviceGroup *model.ServiceGroup, failedServers []string) error {
+	members := buildMembers(serviceGroup, failedServers, processor.homeZone)
 
 	if len(members) == 0 {
 		return fmt.Errorf("There were no members found for service group %s", serviceGroup.Name)
@@ -29,21 +110,50 @@ func (processor serviceGroupProcessorImpl) ProcessServiceGroup(serviceGroup *mod
 
 	serviceGroup.Members = members
 
+	//refusal of member removals breaching the removal limits
+	var refusal error
 	a10ServiceGroup, a10err := processor.a10Client.GetServiceGroup(serviceGroup.Name)
 	if a10err != nil {
 		if processor.a10Client.IsServiceGroupNotFound(a10err) {
+			err := util.ValidateA10Name(serviceGroup.Name)
+			if err != nil {
+				return err
+			}
 			a10err = processor.a10Client.CreateServiceGroup(serviceGroup)
+			if a10err == nil {
+				processor.canary.ServiceGroupChanged(serviceGroup.Name, nil)
+				processor.applied.serviceGroupApplied(serviceGroup, members)
+				processor.recorder.recordServiceGroup(serviceGroup, model.ReasonCreated, fmt.Sprintf("service group %s created", serviceGroup.Name))
+			}
 		}
 	} else {
 		fmt.Println(util.ToJSON(a10ServiceGroup))
+		changed := false
+		added := 0
+		lastApplied := processor.applied.serviceGroup(serviceGroup.Name)
+		applied := newAppliedMembers(members, lastApplied)
 
-		if !sameGroupConfigs(serviceGroup, a10ServiceGroup) {
-			glog.Info("Service group configuration in a10 differs from configuration in kubernetes, resetting service group in a10")
-			a10err = processor.a10Client.UpdateServiceGroup(serviceGroup)
-			if a10err != nil {
-				return a10err
+		desired := withRemovedSettingsReset(serviceGroup, lastApplied)
+		changes := diffServiceGroup(a10ServiceGroup, desired)
+		if len(changes) > 0 {
+			keep := false
+			if lastApplied != nil {
+				outOfBand := diffServiceGroup(a10ServiceGroup, lastApplied).Reversed()
+				if len(outOfBand) > 0 {
+					keep = processor.applied.keeps(len(diffServiceGroup(lastApplied, desired)) == 0)
+					processor.reportOutOfBand(serviceGroup, fmt.Sprintf("service group %s", serviceGroup.Name), outOfBand, keep)
+				}
+			}
+			if !keep {
+				glog.Infof("Service group configuration in a10 differs from configuration in kubernetes, resetting service group in a10: %s", changes)
+				a10err = processor.a10Client.UpdateServiceGroup(desired)
+				if a10err != nil {
+					return a10err
+				}
+				glog.Info("A10 Service group configuration synced with kubernetes")
+				processor.recorder.recordServiceGroup(serviceGroup, model.ReasonUpdated, describeChanges(fmt.Sprintf("service group %s updated", serviceGroup.Name), changes))
+				changed = true
 			}
-			glog.Info("A10 Service group configuration synced with kubernetes")
 		} else {
 			glog.Info("A10 Service group configuration is in sync with kubernetes")
 		}
@@ -52,37 +162,163 @@ func (processor serviceGroupProcessorImpl) ProcessServiceGroup(serviceGroup *mod
 
 		if len(missingMembers) > 0 {
 			for _, member := range missingMembers {
+				if lastApplied != nil && containsMemeber(lastApplied.Members, member) {
+					//the member was added before, so it was removed out of band
+					keep := processor.applied.keeps(true)
+					processor.reportOutOfBand(serviceGroup, fmt.Sprintf("member %s:%d of service group %s", member.ServerName, member.Port, serviceGroup.Name), nil, keep)
+					if keep {
+						continue
+					}
+				}
 				err := processor.a10Client.CreateMember(member)
 				if err != nil && !processor.a10Client.IsMemberAlreadyExists(err) {
 					glog.Errorf("Failed to create member %s:%d for service group %s. error: %s", member.ServerName, member.Port, member.ServiceGroupName, err)
+					applied.restore(member)
 					a10err = err
+					continue
 				}
+				processor.recorder.recordServiceGroup(serviceGroup, model.ReasonUpdated, fmt.Sprintf("member %s:%d added to service group %s", member.ServerName, member.Port, serviceGroup.Name))
+				added++
+				changed = true
 			}
 		}
 
 		extraMembers := findExtraMembers(serviceGroup.Members, a10ServiceGroup.Members)
+		ownedMembers := findOwnedMembers(serviceGroup, extraMembers)
+		removedMembers := make([]*model.Member, 0, len(ownedMembers))
 
-		if len(extraMembers) > 0 {
-			for _, member := range extraMembers {
-				err := processor.a10Client.DeleteMember(member)
-				if err != nil {
-					glog.Errorf("Failed to delete member %s:%d for service group %s. error: %s", member.ServerName, member.Port, member.ServiceGroupName, err)
-					a10err = err
+		for _, member := range extraMembers {
+			if !containsMemeber(ownedMembers, member) {
+				applied.restore(member)
+				continue
+			}
+			if util.Contains(serviceGroup.KeptServers, member.ServerName) {
+				glog.Infof("Keeping member %s:%d of service group %s, its server failed to sync", member.ServerName, member.Port, serviceGroup.Name)
+				applied.restore(member)
+				continue
+			}
+			if lastApplied != nil && !containsMemeber(lastApplied.Members, member) {
+				//the member was never added by a10bridge
+				keep := processor.applied.keeps(true)
+				processor.reportOutOfBand(serviceGroup, fmt.Sprintf("member %s:%d of service group %s", member.ServerName, member.Port, serviceGroup.Name), nil, keep)
+				if keep {
+					continue
+				}
+			}
+			removedMembers = append(removedMembers, member)
+		}
+
+		if len(removedMembers) > 0 {
+			remaining := len(a10ServiceGroup.Members) + added - len(removedMembers)
+			refusal = processor.removals.check(serviceGroup.Name, len(a10ServiceGroup.Members), len(removedMembers), remaining)
+			if refusal != nil {
+				glog.Errorf("Not removing members of service group %s. error: %s", serviceGroup.Name, refusal)
+				for _, member := range removedMembers {
+					applied.restore(member)
 				}
+				removedMembers = nil
+			}
+		}
+
+		for _, member := range removedMembers {
+			err := processor.a10Client.DeleteMember(member)
+			if err != nil {
+				glog.Errorf("Failed to delete member %s:%d for service group %s. error: %s", member.ServerName, member.Port, member.ServiceGroupName, err)
+				applied.restore(member)
+				a10err = err
+				continue
 			}
+			processor.removals.memberRemoved()
+			processor.recorder.recordServiceGroup(serviceGroup, model.ReasonDeleted, fmt.Sprintf("member %s:%d removed from service group %s", member.ServerName, member.Port, serviceGroup.Name))
+			changed = true
+		}
+
+		changedMembers := findChangedMembers(serviceGroup.Members, a10ServiceGroup.Members)
+
+		for _, member := range changedMembers {
+			if lastApplied != nil {
+				appliedMember := findMember(lastApplied.Members, member)
+				a10Member := findMember(a10ServiceGroup.Members, member)
+				if appliedMember != nil && appliedMember.PriorityOrDefault() != a10Member.PriorityOrDefault() {
+					keep := processor.applied.keeps(appliedMember.PriorityOrDefault() == member.PriorityOrDefault())
+					outOfBand := model.FieldChanges{model.FieldChange{Field: "priority", Old: strconv.Itoa(appliedMember.PriorityOrDefault()), New: strconv.Itoa(a10Member.PriorityOrDefault())}}
+					processor.reportOutOfBand(serviceGroup, fmt.Sprintf("member %s:%d of service group %s", member.ServerName, member.Port, serviceGroup.Name), outOfBand, keep)
+					if keep {
+						continue
+					}
+				}
+			}
+			err := processor.a10Client.UpdateMember(member)
+			if err != nil {
+				glog.Errorf("Failed to update member %s:%d for service group %s. error: %s", member.ServerName, member.Port, member.ServiceGroupName, err)
+				applied.restore(member)
+				a10err = err
+				continue
+			}
+			processor.recorder.recordServiceGroup(serviceGroup, model.ReasonUpdated, fmt.Sprintf("member %s:%d priority set to %d in service group %s", member.ServerName, member.Port, member.PriorityOrDefault(), serviceGroup.Name))
+			changed = true
+		}
+
+		processor.applied.serviceGroupApplied(serviceGroup, applied.members())
+		if changed {
+			processor.canary.ServiceGroupChanged(serviceGroup.Name, a10ServiceGroup)
+		}
+
+		if !changed && a10err == nil && refusal == nil {
+			processor.recorder.recordServiceGroup(serviceGroup, model.ReasonInSync, fmt.Sprintf("service group %s is in sync", serviceGroup.Name))
 		}
 	}
 
-	return a10err
+	if a10err != nil {
+		return a10err
+	}
+	return refusal
+}
+
+//reportOutOfBand reports a10 object which differs from the state a10bridge last applied to it
+func (processor serviceGroupProcessorImpl) reportOutOfBand(serviceGroup *model.ServiceGroup, object string, changes model.FieldChanges, keep bool) {
+	message := describeOutOfBand(object, changes, keep)
+	glog.Warning(message)
+	processor.recorder.recordServiceGroup(serviceGroup, model.ReasonChangedOutOfBand, message)
+}
+
+//appliedMembers members recorded as applied to a service group, members whose change failed keep what was applied before
+type appliedMembers struct {
+	applied  []*model.Member
+	previous []*model.Member
 }
 
-func sameGroupConfigs(serviceGroup *model.ServiceGroup, a10ServiceGroup *model.ServiceGroup) bool {
-	if serviceGroup.Health.Name != a10ServiceGroup.Health.Name {
-		glog.Infof("Health monitor names '%s' and '%s' don't match", serviceGroup.Health.Name, a10ServiceGroup.Health.Name)
-		return false
+func newAppliedMembers(members []*model.Member, lastApplied *model.ServiceGroup) *appliedMembers {
+	applied := &appliedMembers{applied: append([]*model.Member{}, members...)}
+	if lastApplied != nil {
+		applied.previous = lastApplied.Members
 	}
+	return applied
+}
+
+func (applied *appliedMembers) restore(member *model.Member) {
+	kept := make([]*model.Member, 0, len(applied.applied))
+	for _, item := range applied.applied {
+		if item.ServerName != member.ServerName || item.Port != member.Port {
+			kept = append(kept, item)
+		}
+	}
+	previous := findMember(applied.previous, member)
+	if previous != nil {
+		kept = append(kept, previous)
+	}
+	applied.applied = kept
+}
 
-	return true
+func (applied *appliedMembers) members() []*model.Member {
+	return applied.applied
+}
+
+func healthName(serviceGroup *model.ServiceGroup) string {
+	if serviceGroup.Health == nil {
+		return ""
+	}
+	return serviceGroup.Health.Name
 }
 
 func findExtraMembers(expected []*model.Member, members []*model.Member) []*model.Member {
@@ -98,6 +334,23 @@ func findExtraMembers(expected []*model.Member, members []*model.Member) []*mode
 	return extraMembers
 }
 
+//findOwnedMembers leaves out members on servers of clusters whose state is not known, they may still be valid members
+func findOwnedMembers(serviceGroup *model.ServiceGroup, members []*model.Member) []*model.Member {
+	if serviceGroup.OwnedServers == nil {
+		return members
+	}
+
+	ownedMembers := make([]*model.Member, 0)
+	for _, member := range members {
+		if !util.Contains(serviceGroup.OwnedServers, member.ServerName) {
+			glog.Infof("Keeping member %s:%d of service group %s, its server is not owned by any cluster with known state", member.ServerName, member.Port, serviceGroup.Name)
+			continue
+		}
+		ownedMembers = append(ownedMembers, member)
+	}
+	return ownedMembers
+}
+
 func findMissingMembers(expected []*model.Member, members []*model.Member) []*model.Member {
 	missingMembers := make([]*model.Member, 0)
 
@@ -111,6 +364,30 @@ func findMissingMembers(expected []*model.Member, members []*model.Member) []*mo
 	return missingMembers
 }
 
+//findChangedMembers finds expected members already present in a10 with a different priority
+func findChangedMembers(expected []*model.Member, members []*model.Member) []*model.Member {
+	changedMembers := make([]*model.Member, 0)
+
+	for _, member := range expected {
+		a10Member := findMember(members, member)
+		if a10Member != nil && a10Member.PriorityOrDefault() != member.PriorityOrDefault() {
+			glog.Infof("'%s' priority %d should be %d", member.ServerName, a10Member.PriorityOrDefault(), member.PriorityOrDefault())
+			changedMembers = append(changedMembers, member)
+		}
+	}
+
+	return changedMembers
+}
+
+func findMember(members []*model.Member, lookFor *model.Member) *model.Member {
+	for _, item := range members {
+		if item.ServerName == lookFor.ServerName && item.Port == lookFor.Port {
+			return item
+		}
+	}
+	return nil
+}
+
 func containsMemeber(members []*model.Member, lookFor *model.Member) bool {
 	for _, item := range members {
 		if item.ServerName == lookFor.ServerName && item.Port == lookFor.Port {
@@ -120,22 +397,46 @@ func containsMemeber(members []*model.Member, lookFor *model.Member) bool {
 	return false
 }
 
-func buildMembers(serviceGroup *model.ServiceGroup, excludedNodeNames []string) []*model.Member {
+func buildMembers(serviceGroup *model.ServiceGroup, excludedServers []string, homeZone string) []*model.Member {
 	members := make([]*model.Member, 0)
 
 	for _, controller := range serviceGroup.IngressControllers {
 		port := controller.Port
 		for _, node := range controller.Nodes {
-			if util.Contains(excludedNodeNames, node.Name) {
+			if util.Contains(excludedServers, node.A10Server) {
 				continue
 			}
+			priority, overridden := serviceGroup.MemberPriorities[node.A10Server]
+			if !overridden {
+				priority = memberPriority(controller, node, homeZone)
+			}
 			members = append(members, &model.Member{
 				Port:             port,
 				ServerName:       node.A10Server,
 				ServiceGroupName: serviceGroup.Name,
+				Priority:         priority,
+				Source:           controller.PortSource,
 			})
 		}
 	}
 
 	return members
 }
+
+//memberPriority priority set on the node wins over the one of the ingress controller,
+//without any the nodes in the home zone are preferred when the a10 instance has one
+func memberPriority(controller *model.IngressController, node *model.Node, homeZone string) int {
+	if node.MemberPriority > 0 {
+		return node.MemberPriority
+	}
+	if controller.MemberPriority > 0 {
+		return controller.MemberPriority
+	}
+	if len(homeZone) > 0 {
+		if node.Zone() == homeZone {
+			return homeZoneMemberPriority
+		}
+		return model.MemberPriorityDefault
+	}
+	return 0
+}
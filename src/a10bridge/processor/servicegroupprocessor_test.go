@@ -1,11 +1,13 @@
 package processor_test
 
 import (
+	"a10bridge/config"
 	"a10bridge/mocks"
 	"a10bridge/model"
 	"a10bridge/processor"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -25,13 +27,30 @@ func TestServiceGroupProcessor(t *testing.T) {
 	suite.Run(t, tests)
 }
 
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_failedServersAreExcluded() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].Nodes = []*model.Node{
+		&model.Node{Name: "node1", A10Server: "server1"},
+		&model.Node{Name: "node2", A10Server: "server2"},
+	}
+	existing := *serviceGroup
+	existing.Members = []*model.Member{&model.Member{ServerName: "server1", Port: 8080}}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{"server2"})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
 func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_noMembers() {
 	client := suite.client
 	processor := suite.helper.BuildServiceGroupProcessor(client)
 	serviceGroup := serviceGroup()
-	failedNodeNames := []string{serviceGroup.IngressControllers[0].Nodes[0].A10Server}
+	failedServers := []string{serviceGroup.IngressControllers[0].Nodes[0].A10Server}
 
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -40,10 +59,10 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_notChanged(
 	client := suite.client
 	processor := suite.helper.BuildServiceGroupProcessor(client)
 	serviceGroup := serviceGroup()
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(serviceGroup, nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -53,11 +72,11 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_GetServiceG
 	client := suite.client
 	processor := suite.helper.BuildServiceGroupProcessor(client)
 	serviceGroup := serviceGroup()
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(nil, a10error)
 	client.On("IsServiceGroupNotFound", a10error).Once().Return(false)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -67,27 +86,42 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_notFound()
 	client := suite.client
 	processor := suite.helper.BuildServiceGroupProcessor(client)
 	serviceGroup := serviceGroup()
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(nil, a10error)
 	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
 	client.On("CreateServiceGroup", serviceGroup).Once().Return(nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
 
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_invalidName() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.Name = "service group"
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(nil, a10error)
+	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "CreateServiceGroup", serviceGroup)
+}
+
 func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_createSserviceGroupFails() {
 	a10error := new(mocks.A10Error)
 	client := suite.client
 	processor := suite.helper.BuildServiceGroupProcessor(client)
 	serviceGroup := serviceGroup()
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(nil, a10error)
 	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
 	client.On("CreateServiceGroup", serviceGroup).Once().Return(a10error)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -100,7 +134,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_healthCheck
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	existing.Members = []*model.Member{
 		&model.Member{
 			ServerName: "server",
@@ -110,7 +144,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_healthCheck
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
 	client.On("UpdateServiceGroup", serviceGroup).Once().Return(nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -124,11 +158,11 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_updateFails
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
 	client.On("UpdateServiceGroup", serviceGroup).Once().Return(a10error)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -141,7 +175,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_missingMemb
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	existing.Members = []*model.Member{}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
@@ -149,9 +183,29 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_missingMemb
 	client.On("CreateMember", &model.Member{
 		Port:             8080,
 		ServerName:       "server",
-		ServiceGroupName: "service group",
+		ServiceGroupName: "service-group",
+	}).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberSource() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].PortSource = model.PortSourceNodePort
+	existing := *serviceGroup
+	existing.Members = []*model.Member{}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("CreateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "server",
+		ServiceGroupName: "service-group",
+		Source:           model.PortSourceNodePort,
 	}).Once().Return(nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{"server_down"})
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -169,7 +223,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_createMembe
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	existing.Members = []*model.Member{}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
@@ -177,15 +231,15 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_createMembe
 	client.On("CreateMember", &model.Member{
 		Port:             8080,
 		ServerName:       "server",
-		ServiceGroupName: "service group",
+		ServiceGroupName: "service-group",
 	}).Once().Return(a10error)
 	client.On("IsMemberAlreadyExists", a10error).Once().Return(false)
 	client.On("CreateMember", &model.Member{
 		Port:             8080,
 		ServerName:       "server2",
-		ServiceGroupName: "service group",
+		ServiceGroupName: "service-group",
 	}).Once().Return(nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -199,7 +253,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberCreat
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	existing.Members = []*model.Member{}
 
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
@@ -207,10 +261,10 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberCreat
 	client.On("CreateMember", &model.Member{
 		Port:             8080,
 		ServerName:       "server",
-		ServiceGroupName: "service group",
+		ServiceGroupName: "service-group",
 	}).Once().Return(a10error)
 	client.On("IsMemberAlreadyExists", a10error).Once().Return(true)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
 }
@@ -223,7 +277,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_extraMember
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	extraServer1 := &model.Member{
 		ServerName: "server2",
 		Port:       8080,
@@ -245,9 +299,39 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_extraMember
 	client.On("UpdateServiceGroup", serviceGroup).Once().Return(nil)
 	client.On("DeleteMember", extraServer1).Once().Return(nil)
 	client.On("DeleteMember", extraServer2).Once().Return(nil)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_keepsMembersOfUnknownClusters() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.OwnedServers = []string{"server", "server2"}
+	existing := *serviceGroup
+	ownedMember := &model.Member{
+		ServerName: "server2",
+		Port:       8080,
+	}
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName: "server",
+			Port:       8080,
+		},
+		ownedMember,
+		&model.Member{
+			ServerName: "other-cluster",
+			Port:       8080,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("DeleteMember", ownedMember).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
 	suite.Assert().Nil(err)
 	client.AssertExpectations(suite.T())
+	client.AssertNumberOfCalls(suite.T(), "DeleteMember", 1)
 }
 
 func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_deleteMemberFails() {
@@ -259,7 +343,7 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_deleteMembe
 	healthCheck := *serviceGroup.Health
 	healthCheck.Name = "changed"
 	existing.Health = &healthCheck
-	failedNodeNames := []string{"server_down"}
+	failedServers := []string{"server_down"}
 	extraServer := &model.Member{
 		ServerName: "server2",
 		Port:       8080,
@@ -275,17 +359,667 @@ func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_deleteMembe
 	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
 	client.On("UpdateServiceGroup", serviceGroup).Once().Return(nil)
 	client.On("DeleteMember", extraServer).Once().Return(a10error)
-	err := processor.ProcessServiceGroup(serviceGroup, failedNodeNames)
+	err := processor.ProcessServiceGroup(serviceGroup, failedServers)
+	suite.Assert().NotNil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberPriorityChanged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].MemberPriority = 2
+	existing := *serviceGroup
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName:       "server",
+			Port:             8080,
+			ServiceGroupName: "service-group",
+			Priority:         1,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("UpdateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "server",
+		ServiceGroupName: "service-group",
+		Priority:         2,
+	}).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_defaultMemberPriorityMatches() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	existing := *serviceGroup
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName:       "server",
+			Port:             8080,
+			ServiceGroupName: "service-group",
+			Priority:         model.MemberPriorityDefault,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_nodeMemberPriority() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].MemberPriority = 2
+	serviceGroup.IngressControllers[0].Nodes = append(serviceGroup.IngressControllers[0].Nodes, &model.Node{
+		Name:           "backup",
+		A10Server:      "backup",
+		MemberPriority: 1,
+	})
+	existing := *serviceGroup
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName: "server",
+			Port:       8080,
+			Priority:   2,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("CreateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "backup",
+		ServiceGroupName: "service-group",
+		Priority:         1,
+	}).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberPrioritiesOverride() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].MemberPriority = 3
+	serviceGroup.MemberPriorities = map[string]int{"server": 1}
+	existing := *serviceGroup
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName: "server",
+			Port:       8080,
+			Priority:   3,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("UpdateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "server",
+		ServiceGroupName: "service-group",
+		Priority:         1,
+	}).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestCountDownMembers() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+
+	client.On("GetMemberStatuses", "service-group").Once().Return([]*model.MemberStatus{
+		&model.MemberStatus{ServerName: "server1", Port: 8080, Up: true},
+		&model.MemberStatus{ServerName: "server2", Port: 8080, Up: false},
+		&model.MemberStatus{ServerName: "server3", Port: 8080, Up: false},
+	}, nil)
+	down, err := processor.CountDownMembers("service-group")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(2, down)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestCountDownMembers_fails() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	a10err := new(mocks.A10Error)
+
+	client.On("GetMemberStatuses", "service-group").Once().Return(nil, a10err)
+	_, err := processor.CountDownMembers("service-group")
+	suite.Assert().NotNil(err)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_homeZone() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessorForZone(client, "hall-a")
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].Nodes = []*model.Node{
+		&model.Node{Name: "local", A10Server: "local", Labels: map[string]string{model.LabelZone: "hall-a"}},
+		&model.Node{Name: "remote", A10Server: "remote", Labels: map[string]string{model.LabelZone: "hall-b"}},
+		&model.Node{Name: "pinned", A10Server: "pinned", Labels: map[string]string{model.LabelZone: "hall-b"}, MemberPriority: 4},
+	}
+	existing := *serviceGroup
+	existing.Members = []*model.Member{}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("CreateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "local",
+		ServiceGroupName: "service-group",
+		Priority:         2,
+	}).Once().Return(nil)
+	client.On("CreateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "remote",
+		ServiceGroupName: "service-group",
+		Priority:         model.MemberPriorityDefault,
+	}).Once().Return(nil)
+	client.On("CreateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "pinned",
+		ServiceGroupName: "service-group",
+		Priority:         4,
+	}).Once().Return(nil)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_updateMemberFails() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	serviceGroup := serviceGroup()
+	serviceGroup.IngressControllers[0].Nodes[0].MemberPriority = 16
+	existing := *serviceGroup
+	existing.Members = []*model.Member{
+		&model.Member{
+			ServerName: "server",
+			Port:       8080,
+		},
+	}
+
+	client.On("GetServiceGroup", serviceGroup.Name).Once().Return(&existing, nil)
+	client.On("UpdateMember", &model.Member{
+		Port:             8080,
+		ServerName:       "server",
+		ServiceGroupName: "service-group",
+		Priority:         16,
+	}).Once().Return(a10error)
+	err := processor.ProcessServiceGroup(serviceGroup, []string{})
 	suite.Assert().NotNil(err)
 	client.AssertExpectations(suite.T())
 }
 
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_methodChanged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	expected.Method = "least-connection"
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Method = "round-robin"
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	client.On("UpdateServiceGroup", expected).Once().Return(nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_methodNotManaged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Method = "round-robin"
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_healthMonitorNotManaged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	expected.Health = nil
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Health = &model.HealthCheck{Name: "set-by-operator"}
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_protocolChanged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	expected.Protocol = model.ProtocolUDP
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Protocol = model.ProtocolTCP
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	client.On("UpdateServiceGroup", expected).Once().Return(nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_defaultProtocolMatchesTCP() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Protocol = model.ProtocolTCP
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_minActiveMembersChanged() {
+	client := suite.client
+	processor := suite.helper.BuildServiceGroupProcessor(client)
+	expected := serviceGroup()
+	expected.MinActiveMembers = 2
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	client.On("UpdateServiceGroup", expected).Once().Return(nil)
+	err := processor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_removedMinActiveMembersIsReset() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	previous := appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"})
+	previous.ServiceGroups["service-group"].MinActiveMembers = 2
+	applied.Start(previous, false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.MinActiveMembers = 2
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+	reset := *expected
+	reset.Members = buildExpectedMembers(expected)
+	reset.ResetMinActiveMembers = true
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	client.On("UpdateServiceGroup", &reset).Once().Return(nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	suite.Assert().False(expected.ResetMinActiveMembers)
+	suite.Assert().Equal(0, applied.State().ServiceGroups["service-group"].MinActiveMembers)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_unmanagedMinActiveMembersIsLeftAlone() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	a10ServiceGroup := serviceGroup()
+	a10ServiceGroup.MinActiveMembers = 2
+	a10ServiceGroup.Members = buildExpectedMembers(expected)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(a10ServiceGroup, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_recordsEvents() {
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRecorder(client, recorder)
+	expected := serviceGroup()
+	expected.IngressControllers[0].Namespace = "ingress"
+	expected.IngressControllers = append(expected.IngressControllers, &model.IngressController{
+		Name:  "default/web",
+		Nodes: expected.IngressControllers[0].Nodes,
+		Port:  8081,
+	})
+	existing := *expected
+	extraMember := &model.Member{ServerName: "server2", Port: 8080}
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		extraMember,
+	}
+	missingMember := &model.Member{ServerName: "server", Port: 8081, ServiceGroupName: "service-group"}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	client.On("CreateMember", missingMember).Once().Return(nil)
+	client.On("DeleteMember", extraMember).Once().Return(nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonUpdated, Message: "a10 lb01: member server:8081 added to service group service-group"},
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonDeleted, Message: "a10 lb01: member server2:8080 removed from service group service-group"},
+	}, recorder.Events())
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_recordsFailure() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRecorder(client, recorder)
+	expected := serviceGroup()
+	expected.IngressControllers[0].Namespace = "ingress"
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(nil, a10error)
+	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
+	client.On("CreateServiceGroup", expected).Once().Return(a10error)
+	a10error.On("Error").Return("invalid session")
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().NotNil(err)
+
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonSyncFailed, Message: "a10 lb01: invalid session"},
+	}, recorder.Events())
+	client.AssertExpectations(suite.T())
+}
+
+func buildExpectedMembers(serviceGroup *model.ServiceGroup) []*model.Member {
+	members := make([]*model.Member, 0)
+	for _, controller := range serviceGroup.IngressControllers {
+		for _, node := range controller.Nodes {
+			members = append(members, &model.Member{
+				Port:             controller.Port,
+				ServerName:       node.A10Server,
+				ServiceGroupName: serviceGroup.Name,
+			})
+		}
+	}
+	return members
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_massRemovalIsRefused() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MaxRemovedPercent: 50}, false, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	expected := serviceGroup()
+	existing := *expected
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		&model.Member{ServerName: "server2", Port: 8080},
+		&model.Member{ServerName: "server3", Port: 8080},
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().EqualError(err, "removing 2 of 3 members of service group service-group breaches the limit of 50% removed members, run with allow-mass-removal if it is intended")
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "DeleteMember", mock.Anything)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_removalLeavingTooFewMembersIsRefused() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MinRemainingMembers: 2}, false, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	expected := serviceGroup()
+	existing := *expected
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		&model.Member{ServerName: "server2", Port: 8080},
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().EqualError(err, "removing 1 members of service group service-group leaves 1 members, at least 2 have to remain, run with allow-mass-removal if it is intended")
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "DeleteMember", mock.Anything)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_removalsCountIntoRunLimit() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MaxRemovedMembers: 1, MaxRemovedMembersPerRun: 1}, false, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	first := serviceGroup()
+	second := serviceGroup()
+	second.Name = "service-group2"
+	firstMember := &model.Member{ServerName: "server2", Port: 8080}
+	firstExisting := *first
+	firstExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, firstMember}
+	secondExisting := *second
+	secondExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, &model.Member{ServerName: "server3", Port: 8080}}
+
+	client.On("GetServiceGroup", first.Name).Once().Return(&firstExisting, nil)
+	client.On("GetServiceGroup", second.Name).Once().Return(&secondExisting, nil)
+	client.On("DeleteMember", firstMember).Once().Return(nil)
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(first, []string{}))
+	err := serviceGroupProcessor.ProcessServiceGroup(second, []string{})
+	suite.Assert().EqualError(err, "removing 1 members of service group service-group2 after 1 members removed in this run breaches the limit of 1 removed members per run, run with allow-mass-removal if it is intended")
+	suite.Assert().Equal(1, removals.Removed())
+	client.AssertExpectations(suite.T())
+	client.AssertNumberOfCalls(suite.T(), "DeleteMember", 1)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_removalsOfEarlierInstancesCountIntoRunLimit() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MaxRemovedMembersPerRun: 2}, false, 2)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	expected := serviceGroup()
+	existing := *expected
+	existing.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, &model.Member{ServerName: "server2", Port: 8080}}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().EqualError(err, "removing 1 members of service group service-group after 2 members removed in this run breaches the limit of 2 removed members per run, run with allow-mass-removal if it is intended")
+	suite.Assert().Equal(0, removals.Removed())
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "DeleteMember", mock.Anything)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_failedRemovalsDontCountIntoRunLimit() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MaxRemovedMembersPerRun: 1}, false, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	first := serviceGroup()
+	second := serviceGroup()
+	second.Name = "service-group2"
+	firstMember := &model.Member{ServerName: "server2", Port: 8080}
+	secondMember := &model.Member{ServerName: "server3", Port: 8080}
+	firstExisting := *first
+	firstExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, firstMember}
+	secondExisting := *second
+	secondExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, secondMember}
+
+	client.On("GetServiceGroup", first.Name).Once().Return(&firstExisting, nil)
+	client.On("GetServiceGroup", second.Name).Once().Return(&secondExisting, nil)
+	client.On("DeleteMember", firstMember).Once().Return(a10error)
+	client.On("DeleteMember", secondMember).Once().Return(nil)
+	suite.Assert().Equal(a10error, serviceGroupProcessor.ProcessServiceGroup(first, []string{}))
+	suite.Assert().Equal(0, removals.Removed())
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(second, []string{}))
+	suite.Assert().Equal(1, removals.Removed())
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_serviceGroupRemovalLimitsOverrideGlobalLimits() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{
+		MaxRemovedPercent: 50,
+		ServiceGroups: map[string]config.ServiceGroupLimits{
+			"service-group2": config.ServiceGroupLimits{MaxRemovedMembers: 1},
+		},
+	}, false, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	first := serviceGroup()
+	second := serviceGroup()
+	second.Name = "service-group2"
+	firstExisting := *first
+	firstExisting.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		&model.Member{ServerName: "server2", Port: 8080},
+		&model.Member{ServerName: "server3", Port: 8080},
+	}
+	removedMember := &model.Member{ServerName: "server2", Port: 8080}
+	secondExisting := *second
+	secondExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, removedMember}
+
+	client.On("GetServiceGroup", first.Name).Once().Return(&firstExisting, nil)
+	client.On("GetServiceGroup", second.Name).Once().Return(&secondExisting, nil)
+	client.On("DeleteMember", removedMember).Once().Return(nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(first, []string{})
+	suite.Assert().EqualError(err, "removing 2 of 3 members of service group service-group breaches the limit of 50% removed members, run with allow-mass-removal if it is intended")
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(second, []string{}))
+	client.AssertExpectations(suite.T())
+	client.AssertNumberOfCalls(suite.T(), "DeleteMember", 1)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_overriddenRemovalLimits() {
+	client := suite.client
+	removals := processor.NewRemovalGuard()
+	removals.Start(config.RemovalLimits{MaxRemovedMembers: 1}, true, 0)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithRemovalGuard(client, nil, removals)
+	expected := serviceGroup()
+	existing := *expected
+	extraMember1 := &model.Member{ServerName: "server2", Port: 8080}
+	extraMember2 := &model.Member{ServerName: "server3", Port: 8080}
+	existing.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, extraMember1, extraMember2}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	client.On("DeleteMember", extraMember1).Once().Return(nil)
+	client.On("DeleteMember", extraMember2).Once().Return(nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_keepsMembersOfFailedNodes() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	appliedMember := &model.Member{ServerName: "failed", Port: 8080, ServiceGroupName: "service-group"}
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}, appliedMember), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	expected.IngressControllers[0].Nodes = append(expected.IngressControllers[0].Nodes, &model.Node{Name: "failed", A10Server: "failed"})
+	expected.KeptServers = []string{"failed"}
+	existing := *expected
+	keptMember := &model.Member{ServerName: "failed", Port: 8080}
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		keptMember,
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{"failed"})
+	suite.Assert().Nil(err)
+	suite.Assert().Contains(applied.State().ServiceGroups["service-group"].Members, appliedMember)
+	client.AssertExpectations(suite.T())
+	client.AssertNotCalled(suite.T(), "DeleteMember", mock.Anything)
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_canaryKeepsChangedServiceGroups() {
+	client := suite.client
+	canary := processor.NewCanaryRollout()
+	canary.Start()
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithCanary(client, nil, canary)
+	created := serviceGroup()
+	created.Name = "created"
+	inSync := serviceGroup()
+	inSync.Name = "in-sync"
+	inSyncExisting := *inSync
+	inSyncExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}}
+	changed := serviceGroup()
+	changedExisting := *changed
+	previousMember := &model.Member{ServerName: "server2", Port: 8080}
+	changedExisting.Members = []*model.Member{&model.Member{ServerName: "server", Port: 8080}, previousMember}
+	a10error := new(mocks.A10Error)
+
+	client.On("GetServiceGroup", created.Name).Once().Return(nil, a10error)
+	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
+	client.On("CreateServiceGroup", created).Once().Return(nil)
+	client.On("GetServiceGroup", inSync.Name).Once().Return(&inSyncExisting, nil)
+	client.On("GetServiceGroup", changed.Name).Once().Return(&changedExisting, nil)
+	client.On("DeleteMember", previousMember).Once().Return(nil)
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(created, []string{}))
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(inSync, []string{}))
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(changed, []string{}))
+
+	suite.Assert().Equal([]string{"created", "service-group"}, canary.ServiceGroups())
+	suite.Assert().Nil(canary.PreviousServiceGroup("created"))
+	suite.Assert().Equal(changedExisting.Members, canary.PreviousServiceGroup("service-group").Members)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestRevertServiceGroup() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	appliedMember := &model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}
+	applied.Start(appliedServiceGroup(appliedMember), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithCanary(client, applied, nil)
+	previous := serviceGroup()
+	removedMember := &model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}
+	previous.Method = "round-robin"
+	previous.Members = []*model.Member{removedMember}
+	current := *previous
+	current.Method = "least-connection"
+	addedMember := &model.Member{ServerName: "server2", Port: 8080, ServiceGroupName: "service-group"}
+	current.Members = []*model.Member{addedMember}
+
+	client.On("GetServiceGroup", previous.Name).Once().Return(&current, nil)
+	client.On("UpdateServiceGroup", previous).Once().Return(nil)
+	client.On("DeleteMember", addedMember).Once().Return(nil)
+	client.On("CreateMember", removedMember).Once().Return(nil)
+	err := serviceGroupProcessor.RevertServiceGroup(previous.Name, previous)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]*model.Member{appliedMember}, applied.State().ServiceGroups["service-group"].Members)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestRevertServiceGroup_deletesCreatedServiceGroup() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(model.NewAppliedState(), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithCanary(client, applied, nil)
+	created := serviceGroup()
+	a10error := new(mocks.A10Error)
+
+	client.On("GetServiceGroup", created.Name).Once().Return(nil, a10error)
+	client.On("IsServiceGroupNotFound", a10error).Once().Return(true)
+	client.On("CreateServiceGroup", created).Once().Return(nil)
+	client.On("DeleteServiceGroup", created.Name).Once().Return(nil)
+	suite.Assert().Nil(serviceGroupProcessor.ProcessServiceGroup(created, []string{}))
+	suite.Assert().Contains(applied.State().ServiceGroups, created.Name)
+	err := serviceGroupProcessor.RevertServiceGroup(created.Name, nil)
+	suite.Assert().Nil(err)
+	suite.Assert().NotContains(applied.State().ServiceGroups, created.Name)
+	client.AssertExpectations(suite.T())
+}
+
 func serviceGroup() *model.ServiceGroup {
 	return &model.ServiceGroup{
 		Health: &model.HealthCheck{
 			Name: "test",
 		},
-		Name: "service group",
+		Name: "service-group",
 		IngressControllers: []*model.IngressController{
 			&model.IngressController{
 				Health: &model.HealthCheck{
@@ -303,3 +1037,138 @@ func serviceGroup() *model.ServiceGroup {
 		},
 	}
 }
+
+//appliedServiceGroup state of serviceGroup() applied by the previous run
+func appliedServiceGroup(members ...*model.Member) *model.AppliedState {
+	previous := model.NewAppliedState()
+	previous.ServiceGroups["service-group"] = &model.ServiceGroup{
+		Name:    "service-group",
+		Health:  &model.HealthCheck{Name: "test"},
+		Members: members,
+	}
+	return previous
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberAddedOutOfBandIsKept() {
+	client := suite.client
+	recorder := processor.NewSyncRecorder("lb01")
+	applied := processor.NewAppliedStateTracker()
+	member := &model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}
+	applied.Start(appliedServiceGroup(member), true)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, recorder, applied)
+	expected := serviceGroup()
+	expected.IngressControllers[0].Namespace = "ingress"
+	existing := *expected
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		&model.Member{ServerName: "manual", Port: 8080},
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+
+	suite.Assert().Equal([]*model.SyncEvent{
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeWarning, Reason: model.ReasonChangedOutOfBand,
+			Message: "a10 lb01: member manual:8080 of service group service-group was changed outside of a10bridge and is left alone"},
+		&model.SyncEvent{Kind: model.KindDaemonSet, Namespace: "ingress", Name: "ingress 1", Instance: "lb01", Type: model.EventTypeNormal, Reason: model.ReasonInSync, Message: "a10 lb01: service group service-group is in sync"},
+	}, recorder.Events())
+	suite.Assert().Equal([]*model.Member{member}, applied.State().ServiceGroups["service-group"].Members)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberAddedOutOfBandIsReset() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	existing := *expected
+	manualMember := &model.Member{ServerName: "manual", Port: 8080}
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		manualMember,
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	client.On("DeleteMember", manualMember).Once().Return(nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberRemovedOutOfBandIsKept() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}), true)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	existing := *expected
+	existing.Members = []*model.Member{}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_memberPriorityChangedOutOfBandIsKept() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}), true)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	existing := *expected
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080, Priority: 5},
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_configChangedOutOfBandIsKept() {
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	expected.Method = model.LBMethodRoundRobin
+	existing := *expected
+	existing.Method = model.LBMethodLeastConnection
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+	}
+	previous := appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"})
+	previous.ServiceGroups["service-group"].Method = model.LBMethodRoundRobin
+	applied.Start(previous, true)
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().Nil(err)
+	client.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceGroupProcessorTestSuite) TestProcessServiceGroup_failedDeleteKeepsAppliedMember() {
+	a10error := new(mocks.A10Error)
+	client := suite.client
+	applied := processor.NewAppliedStateTracker()
+	appliedMember := &model.Member{ServerName: "server2", Port: 8080, ServiceGroupName: "service-group"}
+	applied.Start(appliedServiceGroup(&model.Member{ServerName: "server", Port: 8080, ServiceGroupName: "service-group"}, appliedMember), false)
+	serviceGroupProcessor := suite.helper.BuildServiceGroupProcessorWithAppliedState(client, nil, applied)
+	expected := serviceGroup()
+	existing := *expected
+	staleMember := &model.Member{ServerName: "server2", Port: 8080}
+	existing.Members = []*model.Member{
+		&model.Member{ServerName: "server", Port: 8080},
+		staleMember,
+	}
+
+	client.On("GetServiceGroup", expected.Name).Once().Return(&existing, nil)
+	client.On("DeleteMember", staleMember).Once().Return(a10error)
+	err := serviceGroupProcessor.ProcessServiceGroup(expected, []string{})
+	suite.Assert().NotNil(err)
+	suite.Assert().Contains(applied.State().ServiceGroups["service-group"].Members, appliedMember)
+	client.AssertExpectations(suite.T())
+}
@@ -0,0 +1,24 @@
+package processor
+
+import (
+	"a10bridge/config"
+	"a10bridge/model"
+)
+
+//StateSource source of desired state other than a kubernetes cluster
+type StateSource interface {
+	Name() string
+	Load() (*model.SourceState, error)
+}
+
+//BuildStateSources builds the configured static sources
+func BuildStateSources(sources []config.SourceConfig) []StateSource {
+	stateSources := make([]StateSource, 0, len(sources))
+	for _, source := range sources {
+		stateSources = append(stateSources, &fileSourceImpl{
+			name: source.Name,
+			file: source.File,
+		})
+	}
+	return stateSources
+}
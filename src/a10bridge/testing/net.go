@@ -8,29 +8,29 @@ import (
 
 //ConfigurableResolver configurable implementation of HostResolver interface
 type ConfigurableResolver struct {
-	data map[string]string
+	data map[string][]string
 }
 
-//LookupIP call to get the first looked up ip as string
-func (resolver ConfigurableResolver) LookupIP(hostname string) (string, error) {
-	addr, found := resolver.data[hostname]
+//LookupIP call to get the looked up ips as strings
+func (resolver ConfigurableResolver) LookupIP(hostname string) ([]string, error) {
+	addrs, found := resolver.data[hostname]
 	if found {
-		return addr, nil
+		return addrs, nil
 	}
-	return "", fmt.Errorf("Failed to resolve ip for host %s", hostname)
+	return nil, fmt.Errorf("Failed to resolve ip for host %s", hostname)
 }
 
-//AddRecord add new record for lookups
+//AddRecord add new record for lookups, adding more records for the same host makes it resolve to multiple addresses
 func (resolver *ConfigurableResolver) AddRecord(hostname, addr string) {
-	resolver.data[hostname] = addr
+	resolver.data[hostname] = append(resolver.data[hostname], addr)
 }
 
 //Reset reset record configuration
 func (resolver *ConfigurableResolver) Reset() {
-	resolver.data = make(map[string]string)
+	resolver.data = make(map[string][]string)
 }
 
 func (resolver *ConfigurableResolver) init() {
 	glog.Error("in init")
-	resolver.data = make(map[string]string)
+	resolver.data = make(map[string][]string)
 }
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//A10NameMaxLength longest object name accepted by ACOS for servers, service groups and health monitors
+const A10NameMaxLength = 63
+
+//A10DescriptionMaxLength longest object description accepted by ACOS
+const A10DescriptionMaxLength = 63
+
+//bounds of server weights accepted by both a10 api versions
+const (
+	A10WeightMin = 1
+	A10WeightMax = 100
+)
+
+var invalidA10NameChars = regexp.MustCompile("[^A-Za-z0-9_.-]")
+
+//SanitizeA10Name replaces characters not allowed by ACOS with - and shortens names over the limit with a stable hash suffix
+func SanitizeA10Name(name string) string {
+	return HashSuffix(A10NameMaxLength, invalidA10NameChars.ReplaceAllString(name, "-"))
+}
+
+//ValidateA10Name checks the name can be used for an a10 object
+func ValidateA10Name(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("a10 object name can't be empty")
+	}
+	if len(name) > A10NameMaxLength {
+		return fmt.Errorf("a10 object name %s is longer than %d characters", name, A10NameMaxLength)
+	}
+	if invalidA10NameChars.MatchString(name) {
+		return fmt.Errorf("a10 object name %s contains characters other than letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+//SanitizeA10Description drops characters which can't be sent inside of a json string as is and cuts the description to the allowed length
+func SanitizeA10Description(description string) string {
+	description = strings.Map(func(r rune) rune {
+		if r == '"' || r == '\\' || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, description)
+	if runes := []rune(description); len(runes) > A10DescriptionMaxLength {
+		description = string(runes[:A10DescriptionMaxLength])
+	}
+	return description
+}
+
+//ParseA10Weight parses server weight, the weight is rendered into a10 requests as is so only numbers within bounds are accepted
+func ParseA10Weight(value string) (int, error) {
+	weight, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("weight '%s' is not a number", value)
+	}
+	return weight, ValidateA10Weight(weight)
+}
+
+//ValidateA10Weight checks the weight is accepted by ACOS
+func ValidateA10Weight(weight int) error {
+	if weight < A10WeightMin || weight > A10WeightMax {
+		return fmt.Errorf("weight %d has to be between %d and %d", weight, A10WeightMin, A10WeightMax)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package util_test
+
+import (
+	"a10bridge/util"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NameTestSuite struct {
+	suite.Suite
+}
+
+func TestName(t *testing.T) {
+	suite.Run(t, new(NameTestSuite))
+}
+
+func (suite *NameTestSuite) TestSanitizeA10Name() {
+	suite.Assert().Equal("dc-prod_web.80", util.SanitizeA10Name("dc-prod_web.80"))
+	suite.Assert().Equal("ingress-web-group", util.SanitizeA10Name("ingress/web group"))
+}
+
+func (suite *NameTestSuite) TestSanitizeA10Name_long() {
+	name := strings.Repeat("a", 70)
+	other := strings.Repeat("a", 69) + "b"
+
+	sanitized := util.SanitizeA10Name(name)
+	suite.Assert().Len(sanitized, util.A10NameMaxLength)
+	suite.Assert().Nil(util.ValidateA10Name(sanitized))
+	suite.Assert().Equal(sanitized, util.SanitizeA10Name(name))
+	suite.Assert().NotEqual(sanitized, util.SanitizeA10Name(other))
+}
+
+func (suite *NameTestSuite) TestValidateA10Name() {
+	suite.Assert().Nil(util.ValidateA10Name("dc-prod_web.80"))
+	suite.Assert().NotNil(util.ValidateA10Name(""))
+	suite.Assert().NotNil(util.ValidateA10Name("web group"))
+	suite.Assert().NotNil(util.ValidateA10Name(strings.Repeat("a", util.A10NameMaxLength+1)))
+}
+
+func (suite *NameTestSuite) TestParseA10Weight() {
+	weight, err := util.ParseA10Weight("10")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(10, weight)
+	_, err = util.ParseA10Weight("0")
+	suite.Assert().NotNil(err)
+	_, err = util.ParseA10Weight("101")
+	suite.Assert().NotNil(err)
+	_, err = util.ParseA10Weight("10}")
+	suite.Assert().NotNil(err)
+}
+
+func (suite *NameTestSuite) TestSanitizeA10Description() {
+	suite.Assert().Equal("k8s dc-prod node node1", util.SanitizeA10Description("k8s dc-prod node node1"))
+	suite.Assert().Equal("edge node", util.SanitizeA10Description("edge \"node\"\n\\"))
+	suite.Assert().Equal(strings.Repeat("é", util.A10DescriptionMaxLength), util.SanitizeA10Description(strings.Repeat("é", 70)))
+}
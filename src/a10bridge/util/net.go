@@ -1,14 +1,49 @@
 package util
 
 import (
+	"fmt"
 	"net"
 )
 
 var ipResolver IPResolver = new(defaultResolverImpl)
 
-//LookupIP call to get one of the resolved ips as string
-func LookupIP(hostname string) (string, error) {
-	return ipResolver.LookupIP(hostname)
+//LookupIP call to get the first resolved ipv4 and ipv6 addresses as strings, one of them can be empty if the host has no address of that family
+func LookupIP(hostname string) (string, string, error) {
+	addrs, err := ipResolver.LookupIP(hostname)
+	if err != nil {
+		return "", "", err
+	}
+
+	var ipv4, ipv6 string
+	for _, addr := range addrs {
+		if IsIPv4(addr) {
+			if len(ipv4) == 0 {
+				ipv4 = addr
+			}
+		} else if IsIPv6(addr) {
+			if len(ipv6) == 0 {
+				ipv6 = addr
+			}
+		}
+	}
+
+	if len(ipv4) == 0 && len(ipv6) == 0 {
+		return "", "", fmt.Errorf("No ip address resolved for host %s", hostname)
+	}
+
+	return ipv4, ipv6, nil
+}
+
+//IsIPv4 checks if the string is a valid ipv4 address
+func IsIPv4(addr string) bool {
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.To4() != nil
+}
+
+//IsIPv6 checks if the string is a valid ipv6 address
+func IsIPv6(addr string) bool {
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.To4() == nil
 }
 
 //InjectIPResolver allows injection of custom host resolver and returns the current resolver
@@ -20,16 +55,20 @@ func InjectIPResolver(resolver IPResolver) IPResolver {
 
 //IPResolver implementations of this interface are responsible for ip lookups
 type IPResolver interface {
-	LookupIP(hostname string) (string, error)
+	LookupIP(hostname string) ([]string, error)
 }
 
 type defaultResolverImpl struct{}
 
-//LookupIP call to get the first looked up ip as string
-func (resolver defaultResolverImpl) LookupIP(hostname string) (string, error) {
-	addr, err := net.LookupIP(hostname)
+//LookupIP call to get all looked up ips as strings
+func (resolver defaultResolverImpl) LookupIP(hostname string) ([]string, error) {
+	addrs, err := net.LookupIP(hostname)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	ips := make([]string, len(addrs))
+	for idx, addr := range addrs {
+		ips[idx] = addr.String()
 	}
-	return addr[0].String(), err
+	return ips, err
 }
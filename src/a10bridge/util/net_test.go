@@ -2,7 +2,6 @@ package util_test
 
 import (
 	"a10bridge/util"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -18,35 +17,74 @@ func TestNetUtils(t *testing.T) {
 }
 
 func (suite *NetUtilsTestSuite) TestLookupLocalhost() {
-	localIp, err := util.LookupIP("localhost")
+	ipv4, ipv6, err := util.LookupIP("localhost")
 	suite.Assert().Nil(err)
 
-	if strings.Contains(localIp, ":") {
-		suite.Assert().Equal("::1", localIp)
-	} else {
-		suite.Assert().Equal("127.0.0.1", localIp)
+	if len(ipv4) > 0 {
+		suite.Assert().Equal("127.0.0.1", ipv4)
+	}
+	if len(ipv6) > 0 {
+		suite.Assert().Equal("::1", ipv6)
 	}
 }
 
 func (suite *NetUtilsTestSuite) TestLookupFailure() {
-	_, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
+	_, _, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
 	suite.Assert().NotNil(err)
 }
 
 func (suite *NetUtilsTestSuite) TestResolverInjection() {
-	_, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
+	_, _, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
 	suite.Assert().NotNil(err)
 
-	original := util.InjectIPResolver(testResolver{})
+	original := util.InjectIPResolver(testResolver{"10.10.10.10"})
+	defer util.InjectIPResolver(original)
+
+	ipv4, ipv6, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("10.10.10.10", ipv4)
+	suite.Assert().Equal("", ipv6)
+}
+
+func (suite *NetUtilsTestSuite) TestLookupDualStack() {
+	original := util.InjectIPResolver(testResolver{"fd00::10", "10.10.10.10", "fd00::11", "10.10.10.11"})
+	defer util.InjectIPResolver(original)
+
+	ipv4, ipv6, err := util.LookupIP("dual.stack.host")
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("10.10.10.10", ipv4)
+	suite.Assert().Equal("fd00::10", ipv6)
+}
+
+func (suite *NetUtilsTestSuite) TestLookupIPv6Only() {
+	original := util.InjectIPResolver(testResolver{"fd00::10"})
 	defer util.InjectIPResolver(original)
 
-	ip, err := util.LookupIP("i.m.definitelly.not.a.hosname.of.any.kind")
+	ipv4, ipv6, err := util.LookupIP("ipv6.host")
 	suite.Assert().Nil(err)
-	suite.Assert().Equal("10.10.10.10", ip)
+	suite.Assert().Equal("", ipv4)
+	suite.Assert().Equal("fd00::10", ipv6)
+}
+
+func (suite *NetUtilsTestSuite) TestLookupNoValidAddress() {
+	original := util.InjectIPResolver(testResolver{"not-an-ip"})
+	defer util.InjectIPResolver(original)
+
+	_, _, err := util.LookupIP("broken.host")
+	suite.Assert().NotNil(err)
+}
+
+func (suite *NetUtilsTestSuite) TestAddressFamilies() {
+	suite.Assert().True(util.IsIPv4("10.10.10.10"))
+	suite.Assert().False(util.IsIPv4("fd00::10"))
+	suite.Assert().True(util.IsIPv6("fd00::10"))
+	suite.Assert().False(util.IsIPv6("10.10.10.10"))
+	suite.Assert().False(util.IsIPv4("blah"))
+	suite.Assert().False(util.IsIPv6("blah"))
 }
 
-type testResolver struct{}
+type testResolver []string
 
-func (resolver testResolver) LookupIP(hostname string) (string, error) {
-	return "10.10.10.10", nil
+func (resolver testResolver) LookupIP(hostname string) ([]string, error) {
+	return resolver, nil
 }
@@ -20,7 +20,7 @@ func ToJSON(entity interface{}) string {
 //ApplyTemplate processes a string template using the provided data entity for lookups
 func ApplyTemplate(data interface{}, tpl string) (string, error) {
 	var result string
-	tmpl, err := template.New(tpl).Parse(tpl)
+	tmpl, err := template.New(tpl).Funcs(templateFuncs).Parse(tpl)
 	if err != nil {
 		return result, err
 	}
@@ -68,6 +68,56 @@ func (suite *StringUtilsTestSuite) TestApplyTemplate_wrongEntity() {
 	suite.Assert().NotNil(err)
 }
 
+func (suite *StringUtilsTestSuite) TestApplyTemplate_functions() {
+	entity := struct {
+		Name string
+		Data map[string]string
+	}{
+		Name: "Web.Prod",
+		Data: map[string]string{},
+	}
+	result, err := util.ApplyTemplate(entity, `{{.Name | lower | replace "." "-"}} {{.Name | upper | trunc 3}} {{default "none" .Data.region}} {{regexReplace "[aeiou]" "" .Name}}`)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("web-prod WEB none Wb.Prd", result)
+}
+
+func (suite *StringUtilsTestSuite) TestApplyTemplate_hashSuffix() {
+	entity := struct{ Name string }{Name: "a-very-long-service-group-name"}
+	result, err := util.ApplyTemplate(entity, `{{hashSuffix 20 .Name}} {{hashSuffix 40 .Name}}`)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(util.HashSuffix(20, entity.Name)+" "+entity.Name, result)
+	suite.Assert().Len(util.HashSuffix(20, entity.Name), 20)
+}
+
+func (suite *StringUtilsTestSuite) TestApplyTemplate_multiByte() {
+	entity := struct{ Name string }{Name: "größe-dienst-überwachung"}
+	result, err := util.ApplyTemplate(entity, `{{.Name | trunc 4}}`)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("größ", result)
+
+	hashed := util.HashSuffix(12, entity.Name)
+	suite.Assert().Len([]rune(hashed), 12)
+	suite.Assert().Equal("grö", string([]rune(hashed)[:3]))
+}
+
+func (suite *StringUtilsTestSuite) TestApplyTemplate_invalidRegex() {
+	_, err := util.ApplyTemplate(struct{ Name string }{Name: "web"}, `{{regexReplace "(" "" .Name}}`)
+	suite.Assert().NotNil(err)
+}
+
+func (suite *StringUtilsTestSuite) TestApplyTemplate_arithmetic() {
+	entity := struct {
+		CPU  float64
+		Pods int
+	}{CPU: 6, Pods: 2}
+	result, err := util.ApplyTemplate(entity, `{{.CPU | mul 2 | add .Pods | div 4}} {{add "1.5" .Pods}}`)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal("3.5 3.5", result)
+
+	_, err = util.ApplyTemplate(entity, `{{div 0 .Pods}}`)
+	suite.Assert().NotNil(err)
+}
+
 func (suite *StringUtilsTestSuite) TestToJSON() {
 	entity := struct {
 		Name   string `json:"name"`
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//templateFuncs functions available in every template processed by ApplyTemplate, the value is the last argument so they can be chained with pipes
+var templateFuncs = template.FuncMap{
+	"lower":        strings.ToLower,
+	"upper":        strings.ToUpper,
+	"replace":      replace,
+	"trunc":        trunc,
+	"hashSuffix":   HashSuffix,
+	"default":      defaultValue,
+	"regexReplace": regexReplace,
+	"add":          add,
+	"mul":          mul,
+	"div":          div,
+}
+
+func replace(old, new, value string) string {
+	return strings.Replace(value, old, new, -1)
+}
+
+func trunc(length int, value string) string {
+	runes := []rune(value)
+	if length < 0 || len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
+
+func defaultValue(fallback string, value interface{}) string {
+	if value == nil {
+		return fallback
+	}
+	text := fmt.Sprint(value)
+	if len(text) == 0 {
+		return fallback
+	}
+	return text
+}
+
+func regexReplace(pattern, replacement, value string) (string, error) {
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return expression.ReplaceAllString(value, replacement), nil
+}
+
+func add(operand, value interface{}) (float64, error) {
+	left, right, err := toFloats(value, operand)
+	return left + right, err
+}
+
+func mul(operand, value interface{}) (float64, error) {
+	left, right, err := toFloats(value, operand)
+	return left * right, err
+}
+
+//div divides the value by the divisor so it can be used as {{.Value | div 2}}
+func div(divisor, value interface{}) (float64, error) {
+	left, right, err := toFloats(value, divisor)
+	if err == nil && right == 0 {
+		err = fmt.Errorf("division of %v by zero", value)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return left / right, nil
+}
+
+func toFloats(left, right interface{}) (float64, float64, error) {
+	leftValue, err := toFloat(left)
+	if err != nil {
+		return 0, 0, err
+	}
+	rightValue, err := toFloat(right)
+	return leftValue, rightValue, err
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), nil
+	case int64:
+		return float64(number), nil
+	case float64:
+		return number, nil
+	case string:
+		return strconv.ParseFloat(number, 64)
+	}
+	return 0, fmt.Errorf("'%v' is not a number", value)
+}
+
+//HashSuffix shortens the value to the length replacing its end with a hash of the whole value, the same value always produces the same result
+func HashSuffix(length int, value string) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(value))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	if length <= len(suffix) {
+		return suffix[len(suffix)-length:]
+	}
+	return string(runes[:length-len(suffix)]) + suffix
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"a10bridge/apiserver"
+	"a10bridge/config"
+	"a10bridge/model"
+	"a10bridge/processor"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var processorBuildK8sProcessor = processor.BuildK8sProcessor
+
+//ValidatePath path the validating webhook is served on
+const ValidatePath = "/validate"
+
+type handler struct {
+	k8sProcessor      processor.K8sProcessor
+	environmentConfig *config.EnvironmentConfig
+}
+
+//NewHandler builds http handler validating a10 annotations of daemon sets and nodes in admission reviews
+func NewHandler(k8sProcessor processor.K8sProcessor, environmentConfig *config.EnvironmentConfig) http.Handler {
+	return handler{
+		k8sProcessor:      k8sProcessor,
+		environmentConfig: environmentConfig,
+	}
+}
+
+//Start serves the validating webhook over tls, blocks until the server fails
+func Start(address, certFile, keyFile string, clientConfig apiserver.ClientConfig, environmentConfig *config.EnvironmentConfig) error {
+	k8sProcessor, err := processorBuildK8sProcessor(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(ValidatePath, NewHandler(k8sProcessor, environmentConfig))
+	glog.Infof("Starting validating webhook on %s", address)
+	return http.ListenAndServeTLS(address, certFile, keyFile, mux)
+}
+
+func (handler handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1beta1.AdmissionReview{}
+	err = json.Unmarshal(body, &review)
+	if err != nil || review.Request == nil {
+		http.Error(writer, "request body is not an admission review", http.StatusBadRequest)
+		return
+	}
+
+	violations := handler.validate(review.Request)
+	response := &admissionv1beta1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: len(violations) == 0,
+	}
+	if !response.Allowed {
+		glog.Infof("Rejecting %s %s/%s: %s", review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, strings.Join(violations, "; "))
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: strings.Join(violations, "; "),
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+
+	review.Request = nil
+	review.Response = response
+	responseBody, err := json.Marshal(review)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(responseBody)
+}
+
+func (handler handler) validate(request *admissionv1beta1.AdmissionRequest) []string {
+	switch request.Kind.Kind {
+	case "DaemonSet":
+		daemonSet := v1beta1.DaemonSet{}
+		err := json.Unmarshal(request.Object.Raw, &daemonSet)
+		if err != nil {
+			return []string{fmt.Sprintf("Failed to read daemon set: %s", err)}
+		}
+		if !apiserver.HasA10Annotations(daemonSet.Annotations) {
+			return []string{}
+		}
+		environment, err := handler.k8sProcessor.BuildEnvironment(handler.environmentConfig)
+		if err != nil {
+			glog.Errorf("Failed to build environment for validating daemon set %s/%s. error: %s", request.Namespace, request.Name, err)
+			environment = &model.Environment{}
+		}
+		return apiserver.ValidateIngressController(daemonSet, environment)
+	case "Node":
+		node := v1.Node{}
+		err := json.Unmarshal(request.Object.Raw, &node)
+		if err != nil {
+			return []string{fmt.Sprintf("Failed to read node: %s", err)}
+		}
+		return apiserver.ValidateNode(node)
+	}
+	return []string{}
+}
@@ -0,0 +1,152 @@
+package webhook_test
+
+import (
+	"a10bridge/config"
+	"a10bridge/mocks"
+	"a10bridge/model"
+	"a10bridge/webhook"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	k8sProcessor *mocks.K8sProcessor
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.k8sProcessor = new(mocks.K8sProcessor)
+}
+
+func TestWebhook(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (suite *WebhookTestSuite) TestValidDaemonSet() {
+	suite.k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(&model.Environment{Cluster: "dc-prod"}, nil)
+
+	response := suite.review(`{
+  "kind": "AdmissionReview",
+  "apiVersion": "admission.k8s.io/v1beta1",
+  "request": {
+    "uid": "123",
+    "kind": {"group": "extensions", "version": "v1beta1", "kind": "DaemonSet"},
+    "namespace": "ingress",
+    "name": "ingress-controller",
+    "object": {
+      "metadata": {
+        "name": "ingress-controller",
+        "annotations": {"a10.service_group": "{{.Cluster}}-web", "a10.health.port": "10254"}
+      },
+      "spec": {"template": {"spec": {"containers": [{
+        "name": "controller",
+        "ports": [{"name": "http", "hostPort": 80, "containerPort": 80}],
+        "livenessProbe": {"httpGet": {"path": "/healthz", "port": 10254}}
+      }]}}}
+    }
+  }
+}`)
+
+	suite.Assert().Equal("123", string(response.UID))
+	suite.Assert().True(response.Allowed)
+}
+
+func (suite *WebhookTestSuite) TestInvalidDaemonSet() {
+	suite.k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(&model.Environment{Cluster: "dc-prod"}, nil)
+
+	response := suite.review(`{
+  "request": {
+    "uid": "123",
+    "kind": {"kind": "DaemonSet"},
+    "object": {
+      "metadata": {
+        "name": "ingress-controller",
+        "annotations": {"a10.service_group": "{{.Clustr}}-web", "a10.health.port": "http"}
+      }
+    }
+  }
+}`)
+
+	suite.Assert().False(response.Allowed)
+	suite.Assert().Contains(response.Result.Message, "a10.service_group template")
+	suite.Assert().Contains(response.Result.Message, "a10.health.port annotation is invalid")
+}
+
+func (suite *WebhookTestSuite) TestDaemonSetWithoutA10Annotations() {
+	response := suite.review(`{
+  "request": {
+    "uid": "123",
+    "kind": {"kind": "DaemonSet"},
+    "object": {"metadata": {"name": "fluentd"}}
+  }
+}`)
+
+	suite.Assert().True(response.Allowed)
+}
+
+func (suite *WebhookTestSuite) TestDaemonSet_buildEnvironmentFails() {
+	suite.k8sProcessor.On("BuildEnvironment", config.DefaultEnvironmentConfig()).Return(nil, errors.New("failure"))
+
+	response := suite.review(`{
+  "request": {
+    "uid": "123",
+    "kind": {"kind": "DaemonSet"},
+    "object": {"metadata": {"name": "ingress-controller", "annotations": {"a10.service_group": ""}}}
+  }
+}`)
+
+	suite.Assert().False(response.Allowed)
+	suite.Assert().Equal("a10.service_group annotation is required", response.Result.Message)
+}
+
+func (suite *WebhookTestSuite) TestInvalidNode() {
+	response := suite.review(`{
+  "request": {
+    "uid": "123",
+    "kind": {"kind": "Node"},
+    "object": {"metadata": {"name": "node1", "annotations": {"a10.server.weight": "heavy"}}}
+  }
+}`)
+
+	suite.Assert().False(response.Allowed)
+	suite.Assert().Contains(response.Result.Message, "a10.server.weight")
+}
+
+func (suite *WebhookTestSuite) TestOtherKind() {
+	response := suite.review(`{"request": {"uid": "123", "kind": {"kind": "Pod"}, "object": {}}}`)
+
+	suite.Assert().True(response.Allowed)
+}
+
+func (suite *WebhookTestSuite) TestNotAdmissionReview() {
+	recorder := httptest.NewRecorder()
+	webhook.NewHandler(suite.k8sProcessor, config.DefaultEnvironmentConfig()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, webhook.ValidatePath, strings.NewReader(`{}`)))
+
+	suite.Assert().Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *WebhookTestSuite) TestWrongMethod() {
+	recorder := httptest.NewRecorder()
+	webhook.NewHandler(suite.k8sProcessor, config.DefaultEnvironmentConfig()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, webhook.ValidatePath, nil))
+
+	suite.Assert().Equal(http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func (suite *WebhookTestSuite) review(body string) *admissionv1beta1.AdmissionResponse {
+	recorder := httptest.NewRecorder()
+	webhook.NewHandler(suite.k8sProcessor, config.DefaultEnvironmentConfig()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, webhook.ValidatePath, strings.NewReader(body)))
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	review := admissionv1beta1.AdmissionReview{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &review)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(review.Response)
+	return review.Response
+}